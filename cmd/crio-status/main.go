@@ -2,8 +2,11 @@ package main
 
 import (
 	"fmt"
+	"net"
 	"os"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/cri-o/cri-o/internal/client"
 	"github.com/cri-o/cri-o/internal/criocli"
@@ -62,6 +65,16 @@ func main() {
 		}},
 		Name:  "containers",
 		Usage: "Display detailed information about the provided container ID.",
+	}, {
+		Action:  events,
+		Aliases: []string{"e"},
+		Name:    "events",
+		Usage:   "Display the recorded events which are not covered by the CRI container events.",
+	}, {
+		Action:  hostports,
+		Aliases: []string{"hp"},
+		Name:    "hostports",
+		Usage:   "Display the reserved host ports and the last hostport reconciliation.",
 	}, {
 		Action:  info,
 		Aliases: []string{"i"},
@@ -131,6 +144,66 @@ func containers(c *cli.Context) error {
 	return nil
 }
 
+func events(c *cli.Context) error {
+	crioClient, err := crioClient(c)
+	if err != nil {
+		return err
+	}
+
+	events, err := crioClient.Events()
+	if err != nil {
+		return err
+	}
+
+	for i := range events {
+		e := &events[i]
+		object := ""
+		if e.ContainerID != "" {
+			object = " container " + e.ContainerID
+		} else if e.PodSandboxID != "" {
+			object = " pod sandbox " + e.PodSandboxID
+		}
+		fmt.Printf("%s %s %s%s: %s\n", time.Unix(0, e.Time).Format(time.RFC3339), e.Type, e.Reason, object, e.Message)
+	}
+
+	return nil
+}
+
+func hostports(c *cli.Context) error {
+	crioClient, err := crioClient(c)
+	if err != nil {
+		return err
+	}
+
+	info, err := crioClient.HostPortsInfo()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("reservations:\n")
+	for _, r := range info.Reservations {
+		hostIP := r.HostIP
+		if hostIP == "" {
+			hostIP = "*"
+		}
+		fmt.Printf("  %s/%s: %s(%s)\n", net.JoinHostPort(hostIP, fmt.Sprint(r.HostPort)), r.Protocol, r.PodName, r.PodID)
+	}
+
+	r := info.Reconciliation
+	if r == nil {
+		fmt.Printf("last reconciliation: never\n")
+		return nil
+	}
+	fmt.Printf("last reconciliation: %v\n", time.Unix(0, r.Time))
+	fmt.Printf("re-added sandboxes: %s\n", strings.Join(r.Readded, ", "))
+	fmt.Printf("removed chains: %s\n", strings.Join(r.Removed, ", "))
+	if r.Error != "" {
+		fmt.Printf("error: %s\n", r.Error)
+	}
+
+	return nil
+}
+
 func info(c *cli.Context) error {
 	crioClient, err := crioClient(c)
 	if err != nil {
@@ -154,6 +227,19 @@ func info(c *cli.Context) error {
 	for _, m := range info.DefaultIDMappings.Uids {
 		fmt.Printf("  %d:%d:%d\n", m.ContainerID, m.HostID, m.Size)
 	}
+	names := make([]string, 0, len(info.RuntimeFeatures))
+	for name := range info.RuntimeFeatures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		features := info.RuntimeFeatures[name]
+		if features == nil {
+			fmt.Printf("runtime %s: features unknown\n", name)
+			continue
+		}
+		fmt.Printf("runtime %s: OCI version %s - %s\n", name, features.OCIVersionMin, features.OCIVersionMax)
+	}
 
 	return nil
 }
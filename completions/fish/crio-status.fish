@@ -2,7 +2,7 @@
 
 function __fish_crio-status_no_subcommand --description 'Test if there has been any subcommand yet'
     for i in (commandline -opc)
-        if contains -- $i complete completion help h man markdown md config c containers container cs s info i help h
+        if contains -- $i complete completion help h man markdown md config c containers container cs s events e hostports hp info i help h
             return 1
         end
     end
@@ -28,6 +28,10 @@ complete -r -c crio-status -n '__fish_crio-status_no_subcommand' -a 'config c' -
 complete -c crio-status -n '__fish_seen_subcommand_from containers container cs s' -f -l help -s h -d 'show help'
 complete -r -c crio-status -n '__fish_crio-status_no_subcommand' -a 'containers container cs s' -d 'Display detailed information about the provided container ID.'
 complete -c crio-status -n '__fish_seen_subcommand_from containers container cs s' -f -l id -s i -r -d 'the container ID'
+complete -c crio-status -n '__fish_seen_subcommand_from events e' -f -l help -s h -d 'show help'
+complete -r -c crio-status -n '__fish_crio-status_no_subcommand' -a 'events e' -d 'Display the recorded events which are not covered by the CRI container events.'
+complete -c crio-status -n '__fish_seen_subcommand_from hostports hp' -f -l help -s h -d 'show help'
+complete -r -c crio-status -n '__fish_crio-status_no_subcommand' -a 'hostports hp' -d 'Display the reserved host ports and the last hostport reconciliation.'
 complete -c crio-status -n '__fish_seen_subcommand_from info i' -f -l help -s h -d 'show help'
 complete -r -c crio-status -n '__fish_crio-status_no_subcommand' -a 'info i' -d 'Retrieve generic information about CRI-O, like the cgroup and storage driver.'
 complete -c crio-status -n '__fish_seen_subcommand_from help h' -f -l help -s h -d 'show help'
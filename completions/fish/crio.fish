@@ -14,9 +14,17 @@ complete -c crio -n '__fish_crio_no_subcommand' -f -l add-inheritable-capabiliti
 complete -c crio -n '__fish_crio_no_subcommand' -f -l additional-devices -r -d 'Devices to add to the containers.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l allowed-devices -r -d 'Devices a user is allowed to specify with the "io.kubernetes.cri-o.Devices" allowed annotation.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l apparmor-profile -r -d 'Name of the apparmor profile to be used as the runtime\'s default. This only takes effect if the user does not specify a profile via the Kubernetes Pod\'s metadata annotation.'
+complete -c crio -n '__fish_crio_no_subcommand' -l apparmor-profiles-dir -r -d 'Directory of AppArmor profile files, which get loaded on startup and configuration reload. Containers can use the profiles via their localhost/ profile name.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l audit-log-max-age -r -d 'Number of days to retain rotated audit log files. If set to 0, rotated files are not removed based on their age.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l audit-log-max-backups -r -d 'Number of rotated audit log files to retain. If set to 0, all of them are retained.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l audit-log-max-size -r -d 'Size in megabytes at which the audit log file gets rotated.'
+complete -c crio -n '__fish_crio_no_subcommand' -l audit-log-path -r -d 'Path of the audit log file of privileged container operations. If empty, the audit log is not written to a file.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l audit-log-to-journald -d 'Send the audit log of privileged container operations to journald.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l audit-redact-patterns -r -d 'Regular expressions matching the parts of exec command arguments, which get redacted in the audit log.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l big-files-temporary-dir -r -d 'Path to the temporary directory to use for storing big files, used to store image blobs and data streams related to containers image management.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l bind-mount-prefix -r -d 'A prefix to use for the source of the bind mounts. This option would be useful if you were running CRI-O in a container. And had `/` mounted on `/host` in your container. Then if you ran CRI-O with the `--bind-mount-prefix=/host` option, CRI-O would add /host to any bind mounts it is handed over CRI. If Kubernetes asked to have `/var/lib/foobar` bind mounted into the container, then CRI-O would bind mount `/host/var/lib/foobar`. Since CRI-O itself is running in a container with `/` or the host mounted on `/host`, the container would end up with `/var/lib/foobar` from the host mounted in the container rather then `/var/lib/foobar` from the CRI-O container.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l blockio-config-file -r -d 'Path to the blockio class configuration file for configuring the cgroup blockio controller.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l capability-policy -r -d 'Policy for containers using capabilities which are not allowed by their runtime handler or Kubernetes namespace: "reject" or "strip".'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l cdi-spec-dirs -r -d 'Directories to scan for CDI Spec files.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l cgroup-manager -r -d 'cgroup manager (cgroupfs or systemd).'
 complete -c crio -n '__fish_crio_no_subcommand' -l clean-shutdown-file -r -d 'Location for CRI-O to lay down the clean shutdown file. It indicates whether we\'ve had time to sync changes to disk before shutting down. If not found, crio wipe will clear the storage directory.'
@@ -49,13 +57,17 @@ complete -c crio -n '__fish_crio_no_subcommand' -f -l default-sysctls -r -d 'Sys
 complete -c crio -n '__fish_crio_no_subcommand' -f -l default-transport -r -d 'A prefix to prepend to image names that cannot be pulled as-is.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l default-ulimits -r -d 'Ulimits to apply to containers by default (name=soft:hard).'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l device-ownership-from-security-context -d 'Set devices\' uid/gid ownership from runAsUser/runAsGroup.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l dns-options -r -d 'Resolver options, like \'ndots:5\', which get applied to the resolv.conf of all pods.'
+complete -c crio -n '__fish_crio_no_subcommand' -l dns-upstream-resolv-conf -r -d 'Path to the resolv.conf containing the upstream servers of a local DNS stub resolver, which replace the stub resolvers for pods outside of the host network.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l drop-infra-ctr -d 'Determines whether pods are created without an infra container, when the pod is not using a pod level PID namespace.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l enable-bandwidth-fallback -d 'Limit the bandwidth of pods by traffic control rules on their host side veth, if the default CNI network does not handle the bandwidth capability.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l enable-criu-support -d 'Enable CRIU integration, requires that the criu binary is available in $PATH.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l enable-metrics -d 'Enable metrics endpoint for the server on localhost:9090.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l enable-nri -d 'Enable NRI (Node Resource Interface) support. (default: false)'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l enable-pod-events -d 'If true, CRI-O starts sending the container events to the kubelet'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l enable-profile-unix-socket -d 'Enable pprof profiler on crio unix domain socket.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l enable-tracing -d 'Enable OpenTelemetry trace data exporting.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l exec-sync-output-size-max -r -d 'Maximum size in bytes of the output buffered for a single exec sync request. Exceeding output is truncated.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l gid-mappings -r -d 'Specify the GID mappings to use for the user namespace.'
 complete -c crio -n '__fish_crio_no_subcommand' -l global-auth-file -r -d 'Path to a file like /var/lib/kubelet/config.json holding credentials necessary for pulling images from secure registries.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l grpc-max-recv-msg-size -r -d 'Maximum grpc receive message size in bytes.'
@@ -79,6 +91,8 @@ complete -c crio -n '__fish_crio_no_subcommand' -f -l hooks-dir -r -d 'Set the O
     For the bind-mount conditions, only mounts explicitly requested by
     Kubernetes configuration are considered. Bind mounts that CRI-O
     inserts by default (e.g. \'/dev/shm\') are not considered.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l hostport-backend -r -d 'Backend used to map the hostports of pods (\'iptables\' or \'nftables\').'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l hostport-reconcile-interval -r -d 'Interval in which the installed hostport rules get reconciled with the running pods. \'0\' disables the periodic reconciliation.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l image-volumes -r -d 'Image volume handling (\'mkdir\', \'bind\', or \'ignore\')
     1. mkdir: A directory is created inside the container root filesystem for
        the volumes.
@@ -114,6 +128,7 @@ complete -c crio -n '__fish_crio_no_subcommand' -f -l metrics-port -r -d 'Port f
 complete -c crio -n '__fish_crio_no_subcommand' -f -l metrics-socket -r -d 'Socket for the metrics endpoint.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l minimum-mappable-gid -r -d 'Specify the lowest host GID which can be specified in mappings for a pod that will be run as a UID other than 0.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l minimum-mappable-uid -r -d 'Specify the lowest host UID which can be specified in mappings for a pod that will be run as a UID other than 0.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l namespace-pool-size -r -d 'The number of pre-created network, IPC and UTS namespaces kept for fast pod startup. Set to 0 to disable the pool.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l namespaces-dir -r -d 'The directory where the state of the managed namespaces gets tracked. Only used when manage-ns-lifecycle is true.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l no-pivot -d 'If true, the runtime will not use `pivot_root`, but instead use `MS_MOVE`.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l nri-disable-connections -r -d 'Disable connections from externally started NRI plugins. (default: false)'
@@ -138,8 +153,10 @@ complete -c crio -n '__fish_crio_no_subcommand' -l root -s r -r -d 'The CRI-O ro
 complete -c crio -n '__fish_crio_no_subcommand' -l runroot -r -d 'The CRI-O state directory.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l runtimes -r -d 'OCI runtimes, format is \'runtime_name:runtime_path:runtime_root:runtime_type:privileged_without_host_devices:runtime_config_path\'.'
 complete -c crio -n '__fish_crio_no_subcommand' -l seccomp-profile -r -d 'Path to the seccomp.json profile to be used as the runtime\'s default. If not specified, then the internal default seccomp profile will be used.'
+complete -c crio -n '__fish_crio_no_subcommand' -l seccomp-record-dir -r -d 'Directory where the seccomp profiles recorded by the seccomp notifier get written to.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l seccomp-use-default-when-empty -d 'Use the default seccomp profile when an empty one is specified.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l selinux -d 'Enable selinux support.'
+complete -c crio -n '__fish_crio_no_subcommand' -l selinux-mcs-allocation-file -r -d 'File where the SELinux MCS levels allocated to pods are persisted. The levels are allocated without persistence if empty.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l separate-pull-cgroup -r -d '[EXPERIMENTAL] Pull in new cgroup.'
 complete -c crio -n '__fish_crio_no_subcommand' -l signature-policy -r -d 'Path to signature policy JSON file.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l stats-collection-period -r -d 'The number of seconds between collecting pod and container stats. If set to 0, the stats are collected on-demand instead.'
@@ -155,6 +172,9 @@ complete -c crio -n '__fish_crio_no_subcommand' -l stream-tls-key -r -d 'Path to
 complete -c crio -n '__fish_crio_no_subcommand' -f -l tracing-endpoint -r -d 'Address on which the gRPC tracing collector will listen.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l tracing-sampling-rate-per-million -r -d 'Number of samples to collect per million OpenTelemetry spans. Set to 1000000 to always sample.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l uid-mappings -r -d 'Specify the UID mappings to use for the user namespace.'
+complete -c crio -n '__fish_crio_no_subcommand' -l userns-allocation-file -r -d 'File where the user namespace ID ranges allocated to pods are persisted.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l userns-allocation-ranges -r -d 'Host ID ranges in the form HOSTID:SIZE used for allocating the user namespace ID ranges of pods.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l userns-allocation-user -r -d 'User whose subordinate ID ranges are used for allocating the user namespace ID ranges of pods.'
 complete -c crio -n '__fish_crio_no_subcommand' -l version-file -r -d 'Location for CRI-O to lay down the temporary version file. It is used to check if crio wipe should wipe containers, which should always happen on a node reboot.'
 complete -c crio -n '__fish_crio_no_subcommand' -l version-file-persist -r -d 'Location for CRI-O to lay down the persistent version file. It is used to check if crio wipe should wipe images, which should only happen when CRI-O has been upgraded.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l help -s h -d 'show help'
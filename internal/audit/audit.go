@@ -0,0 +1,264 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-systemd/v22/journal"
+	"github.com/cri-o/cri-o/internal/log"
+	json "github.com/json-iterator/go"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/peer"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// Operation is an audited container operation.
+type Operation string
+
+const (
+	// OperationExec is the preparation of a streaming exec session.
+	OperationExec Operation = "exec"
+	// OperationExecSync is a synchronous exec.
+	OperationExecSync Operation = "execSync"
+	// OperationAttach is the preparation of a streaming attach session.
+	OperationAttach Operation = "attach"
+	// OperationPortForward is the preparation of a streaming port forward
+	// session.
+	OperationPortForward Operation = "portForward"
+	// OperationCheckpoint is the checkpoint of a container.
+	OperationCheckpoint Operation = "checkpoint"
+	// OperationCreatePrivileged is the creation of a privileged container.
+	OperationCreatePrivileged Operation = "createPrivileged"
+)
+
+const (
+	// ResultSuccess is the result of a successful operation.
+	ResultSuccess = "success"
+	// ResultFailure is the result of a failed operation.
+	ResultFailure = "failure"
+)
+
+// Redacted replaces the parts of command arguments matching a redaction
+// pattern.
+const Redacted = "[REDACTED]"
+
+// journalIdentifier is the syslog identifier of audit events in the journal.
+const journalIdentifier = "crio-audit"
+
+// Event is a single entry of the audit log.
+type Event struct {
+	Time          time.Time `json:"time"`
+	Operation     Operation `json:"operation"`
+	RequestID     string    `json:"requestID,omitempty"`
+	RequestMethod string    `json:"requestMethod,omitempty"`
+	Peer          string    `json:"peer,omitempty"`
+	ContainerID   string    `json:"containerID,omitempty"`
+	ContainerName string    `json:"containerName,omitempty"`
+	PodSandboxID  string    `json:"podSandboxID,omitempty"`
+	PodName       string    `json:"podName,omitempty"`
+	PodNamespace  string    `json:"podNamespace,omitempty"`
+	Command       []string  `json:"command,omitempty"`
+	TTY           bool      `json:"tty,omitempty"`
+	Stdin         bool      `json:"stdin,omitempty"`
+	Ports         []int32   `json:"ports,omitempty"`
+	Image         string    `json:"image,omitempty"`
+	// CheckpointLocation is the location of the checkpoint archive.
+	CheckpointLocation string `json:"checkpointLocation,omitempty"`
+	// ExitCode is the exit code of the command of a synchronous exec.
+	ExitCode *int32 `json:"exitCode,omitempty"`
+	Result   string `json:"result"`
+	Error    string `json:"error,omitempty"`
+	// DurationMilliseconds is the duration of the CRI request.
+	DurationMilliseconds float64 `json:"durationMilliseconds"`
+}
+
+// Options are the options of a Logger.
+type Options struct {
+	// Path is the path of the audit log file. The file is not written if it
+	// is empty.
+	Path string
+	// Journald specifies if the events should be sent to journald too.
+	Journald bool
+	// MaxSize is the size in megabytes at which the audit log gets rotated.
+	MaxSize int
+	// MaxBackups is the number of rotated audit logs to retain, 0 retains
+	// all of them.
+	MaxBackups int
+	// MaxAge is the number of days to retain rotated audit logs, 0 retains
+	// them regardless of their age.
+	MaxAge int
+	// RedactPatterns are regular expressions matching the parts of command
+	// arguments to redact.
+	RedactPatterns []string
+}
+
+// Logger writes audit events as JSON lines to an append-only file and
+// optionally to journald. A nil Logger does not log anything.
+type Logger struct {
+	writer   io.WriteCloser
+	journald bool
+	redact   []*regexp.Regexp
+	mutex    sync.Mutex
+}
+
+// New creates a new Logger. It returns nil if neither a path nor journald is
+// configured.
+func New(options *Options) (*Logger, error) {
+	if options.Path == "" && !options.Journald {
+		return nil, nil
+	}
+
+	redact, err := CompileRedactPatterns(options.RedactPatterns)
+	if err != nil {
+		return nil, err
+	}
+	l := &Logger{redact: redact}
+
+	if options.Journald {
+		if !journal.Enabled() {
+			return nil, errors.New("journald is not available for the audit log")
+		}
+		l.journald = true
+	}
+
+	if options.Path != "" {
+		if err := createLogFile(options.Path); err != nil {
+			return nil, fmt.Errorf("create audit log: %w", err)
+		}
+		l.writer = &lumberjack.Logger{
+			Filename:   options.Path,
+			MaxSize:    options.MaxSize,
+			MaxBackups: options.MaxBackups,
+			MaxAge:     options.MaxAge,
+			LocalTime:  true,
+		}
+	}
+	logrus.Infof("Writing audit log to %s (journald: %v)", options.Path, options.Journald)
+	return l, nil
+}
+
+// CompileRedactPatterns compiles the redaction patterns of command arguments.
+func CompileRedactPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	result := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+		}
+		result = append(result, re)
+	}
+	return result, nil
+}
+
+// createLogFile creates the audit log file if it does not exist, which makes
+// sure that it is only readable by root. The rotated files keep its mode.
+func createLogFile(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// Start returns a new event for the operation, which is populated from the
+// request metadata of the context. It returns nil for a nil Logger.
+func (l *Logger) Start(ctx context.Context, operation Operation) *Event {
+	if l == nil {
+		return nil
+	}
+	event := &Event{
+		Time:      time.Now(),
+		Operation: operation,
+	}
+	if id, ok := ctx.Value(log.ID{}).(string); ok {
+		event.RequestID = id
+	}
+	if name, ok := ctx.Value(log.Name{}).(string); ok {
+		event.RequestMethod = name
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		event.Peer = p.Addr.String()
+	}
+	return event
+}
+
+// Finish sets the result and duration of the event and logs it. It is a no-op
+// for a nil Logger or event.
+func (l *Logger) Finish(ctx context.Context, event *Event, err error) {
+	if l == nil || event == nil {
+		return
+	}
+	event.DurationMilliseconds = float64(time.Since(event.Time).Microseconds()) / 1000
+	event.Result = ResultSuccess
+	if err != nil {
+		event.Result = ResultFailure
+		event.Error = err.Error()
+	}
+	event.Command = l.Redact(event.Command)
+
+	if err := l.write(event); err != nil {
+		log.Errorf(ctx, "Unable to write audit event of %s operation: %v", event.Operation, err)
+	}
+}
+
+// Redact returns a copy of the command with the parts of the arguments
+// matching a redaction pattern replaced.
+func (l *Logger) Redact(cmd []string) []string {
+	if len(cmd) == 0 || len(l.redact) == 0 {
+		return cmd
+	}
+	result := make([]string, 0, len(cmd))
+	for _, arg := range cmd {
+		for _, re := range l.redact {
+			arg = re.ReplaceAllString(arg, Redacted)
+		}
+		result = append(result, arg)
+	}
+	return result
+}
+
+func (l *Logger) write(event *Event) error {
+	content, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encode audit event: %w", err)
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.writer != nil {
+		if _, err := l.writer.Write(append(content, '\n')); err != nil {
+			return err
+		}
+	}
+	if l.journald {
+		if err := journal.Send(string(content), journal.PriInfo, map[string]string{
+			"SYSLOG_IDENTIFIER":    journalIdentifier,
+			"CRIO_AUDIT_OPERATION": string(event.Operation),
+			"CRIO_AUDIT_RESULT":    event.Result,
+		}); err != nil {
+			return fmt.Errorf("send audit event to journald: %w", err)
+		}
+	}
+	return nil
+}
+
+// Close closes the audit log. It is a no-op for a nil Logger.
+func (l *Logger) Close() error {
+	if l == nil || l.writer == nil {
+		return nil
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.writer.Close()
+}
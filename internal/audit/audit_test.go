@@ -0,0 +1,148 @@
+package audit_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/cri-o/cri-o/internal/audit"
+	"github.com/cri-o/cri-o/internal/log"
+	json "github.com/json-iterator/go"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = t.Describe("Logger", func() {
+	var path string
+
+	BeforeEach(func() {
+		path = filepath.Join(t.MustTempDir("audit"), "audit", "audit.log")
+	})
+
+	readEvents := func() []audit.Event {
+		f, err := os.Open(path)
+		Expect(err).ToNot(HaveOccurred())
+		defer f.Close()
+
+		events := []audit.Event{}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var event audit.Event
+			Expect(json.Unmarshal(scanner.Bytes(), &event)).To(Succeed())
+			events = append(events, event)
+		}
+		Expect(scanner.Err()).ToNot(HaveOccurred())
+		return events
+	}
+
+	It("should be disabled without a path and journald", func() {
+		// Given
+		// When
+		sut, err := audit.New(&audit.Options{})
+
+		// Then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sut).To(BeNil())
+		Expect(sut.Start(context.Background(), audit.OperationExec)).To(BeNil())
+		sut.Finish(context.Background(), nil, nil)
+		Expect(sut.Close()).To(Succeed())
+	})
+
+	It("should fail with an invalid redaction pattern", func() {
+		// Given
+		// When
+		sut, err := audit.New(&audit.Options{Path: path, RedactPatterns: []string{"("}})
+
+		// Then
+		Expect(err).To(HaveOccurred())
+		Expect(sut).To(BeNil())
+	})
+
+	It("should create the audit log only readable by root", func() {
+		// Given
+		// When
+		sut, err := audit.New(&audit.Options{Path: path, MaxSize: 1})
+
+		// Then
+		Expect(err).ToNot(HaveOccurred())
+		defer sut.Close()
+		info, err := os.Stat(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0o600)))
+	})
+
+	It("should append events with the request metadata", func() {
+		// Given
+		sut, err := audit.New(&audit.Options{Path: path, MaxSize: 1})
+		Expect(err).ToNot(HaveOccurred())
+		defer sut.Close()
+		ctx := context.WithValue(context.Background(), log.ID{}, "request-id")
+		ctx = context.WithValue(ctx, log.Name{}, "/runtime.v1.RuntimeService/ExecSync")
+
+		// When
+		event := sut.Start(ctx, audit.OperationExecSync)
+		event.ContainerID = "container-id"
+		event.Command = []string{"ls", "-l"}
+		sut.Finish(ctx, event, nil)
+		sut.Finish(ctx, sut.Start(ctx, audit.OperationCheckpoint), errors.New("failed"))
+
+		// Then
+		events := readEvents()
+		Expect(events).To(HaveLen(2))
+		Expect(events[0].Operation).To(Equal(audit.OperationExecSync))
+		Expect(events[0].RequestID).To(Equal("request-id"))
+		Expect(events[0].RequestMethod).To(Equal("/runtime.v1.RuntimeService/ExecSync"))
+		Expect(events[0].ContainerID).To(Equal("container-id"))
+		Expect(events[0].Command).To(Equal([]string{"ls", "-l"}))
+		Expect(events[0].Result).To(Equal(audit.ResultSuccess))
+		Expect(events[0].Error).To(BeEmpty())
+		Expect(events[1].Operation).To(Equal(audit.OperationCheckpoint))
+		Expect(events[1].Result).To(Equal(audit.ResultFailure))
+		Expect(events[1].Error).To(Equal("failed"))
+	})
+
+	It("should not truncate an existing audit log", func() {
+		// Given
+		sut, err := audit.New(&audit.Options{Path: path, MaxSize: 1})
+		Expect(err).ToNot(HaveOccurred())
+		sut.Finish(context.Background(), sut.Start(context.Background(), audit.OperationAttach), nil)
+		Expect(sut.Close()).To(Succeed())
+
+		// When
+		sut, err = audit.New(&audit.Options{Path: path, MaxSize: 1})
+		Expect(err).ToNot(HaveOccurred())
+		defer sut.Close()
+		sut.Finish(context.Background(), sut.Start(context.Background(), audit.OperationPortForward), nil)
+
+		// Then
+		events := readEvents()
+		Expect(events).To(HaveLen(2))
+		Expect(events[0].Operation).To(Equal(audit.OperationAttach))
+		Expect(events[1].Operation).To(Equal(audit.OperationPortForward))
+	})
+
+	It("should redact the command", func() {
+		// Given
+		sut, err := audit.New(&audit.Options{
+			Path:           path,
+			MaxSize:        1,
+			RedactPatterns: []string{`password=\S+`, `^secret$`},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		defer sut.Close()
+		event := sut.Start(context.Background(), audit.OperationExec)
+		event.Command = []string{"login", "--password=hunter2", "secret", "secrets"}
+
+		// When
+		sut.Finish(context.Background(), event, nil)
+
+		// Then
+		events := readEvents()
+		Expect(events).To(HaveLen(1))
+		Expect(events[0].Command).To(Equal([]string{
+			"login", "--" + audit.Redacted, audit.Redacted, "secrets",
+		}))
+	})
+})
@@ -23,6 +23,8 @@ type CrioClient interface {
 	DaemonInfo() (types.CrioInfo, error)
 	ContainerInfo(string) (*types.ContainerInfo, error)
 	ConfigInfo() (string, error)
+	HostPortsInfo() (*types.HostPortsInfo, error)
+	Events() ([]types.Event, error)
 }
 
 type crioClientImpl struct {
@@ -123,3 +125,41 @@ func (c *crioClientImpl) ConfigInfo() (string, error) {
 	}
 	return string(body), nil
 }
+
+// HostPortsInfo returns the hostport information by querying the cri-o
+// hostports endpoint.
+func (c *crioClientImpl) HostPortsInfo() (*types.HostPortsInfo, error) {
+	req, err := c.getRequest(server.InspectHostPortsEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	info := types.HostPortsInfo{}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// Events returns the recorded node, pod and container events by querying the
+// cri-o events endpoint.
+func (c *crioClientImpl) Events() ([]types.Event, error) {
+	req, err := c.getRequest(server.InspectEventsEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	events := []types.Event{}
+	if err := json.NewDecoder(resp.Body).Decode(&events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
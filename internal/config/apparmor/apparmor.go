@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/containers/common/pkg/apparmor"
 	"github.com/sirupsen/logrus"
@@ -17,13 +18,22 @@ const DefaultProfile = "crio-default"
 type Config struct {
 	enabled        bool
 	defaultProfile string
+
+	// managedProfiles are the profiles loaded from the profiles directory by
+	// their name.
+	managedProfiles map[string]*managedProfile
+	// profileUsers are the profiles used by containers by their ID.
+	profileUsers  map[string]string
+	profilesMutex sync.Mutex
 }
 
 // New creates a new default AppArmor configuration instance
 func New() *Config {
 	return &Config{
-		enabled:        apparmor.IsEnabled(),
-		defaultProfile: DefaultProfile,
+		enabled:         apparmor.IsEnabled(),
+		defaultProfile:  DefaultProfile,
+		managedProfiles: make(map[string]*managedProfile),
+		profileUsers:    make(map[string]string),
 	}
 }
 
@@ -0,0 +1,17 @@
+//go:build test
+// +build test
+
+// All *_inject.go files are meant to be used by tests only. Purpose of this
+// files is to provide a way to inject mocked data into the current setup.
+
+package apparmor
+
+// SetEnabled overrides whether AppArmor is enabled.
+func (c *Config) SetEnabled(enabled bool) {
+	c.enabled = enabled
+}
+
+// SetProfileRemovePath sets the path used for unloading profiles.
+func SetProfileRemovePath(path string) {
+	profileRemovePath = path
+}
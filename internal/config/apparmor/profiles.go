@@ -0,0 +1,173 @@
+package apparmor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cri-o/cri-o/utils/cmdrunner"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+)
+
+// parserBinary is the binary used for loading AppArmor profiles.
+const parserBinary = "apparmor_parser"
+
+// profileRemovePath is the securityfs interface for unloading AppArmor
+// profiles by their name.
+var profileRemovePath = "/sys/kernel/security/apparmor/.remove"
+
+// managedProfile is an AppArmor profile loaded from the profiles directory.
+type managedProfile struct {
+	// file is the path of the file defining the profile.
+	file string
+	// stale is true if the profile has been removed from the profiles
+	// directory but is still in use by containers.
+	stale bool
+}
+
+// LoadProfilesDir loads the AppArmor profiles of all files in dir, which can
+// be used by containers via their "localhost/" profile name. Profiles loaded
+// by a previous call which are not defined in dir anymore get unloaded, unless
+// they are used by containers. Those are unloaded once the last container
+// using them is removed. This method will not fail if AppArmor is disabled or
+// dir does not exist.
+func (c *Config) LoadProfilesDir(dir string) error {
+	if !c.IsEnabled() || dir == "" {
+		return nil
+	}
+
+	c.profilesMutex.Lock()
+	defer c.profilesMutex.Unlock()
+
+	files, err := profileFiles(dir)
+	if err != nil {
+		return fmt.Errorf("read AppArmor profiles directory: %w", err)
+	}
+
+	profiles := make(map[string]*managedProfile)
+	for _, file := range files {
+		names, err := profileNames(file)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			if name == DefaultProfile {
+				return fmt.Errorf("AppArmor profile file %s must not define the default profile %s", file, DefaultProfile)
+			}
+			if other, ok := profiles[name]; ok {
+				return fmt.Errorf("AppArmor profile %s is defined in %s and %s", name, other.file, file)
+			}
+			profiles[name] = &managedProfile{file: file}
+		}
+	}
+
+	for _, file := range files {
+		if output, err := cmdrunner.CombinedOutput(parserBinary, "--replace", file); err != nil {
+			return fmt.Errorf("load AppArmor profile file %s: %s: %w", file, output, err)
+		}
+		logrus.Infof("Loaded AppArmor profiles from %s", file)
+	}
+
+	for name, profile := range c.managedProfiles {
+		if _, ok := profiles[name]; ok {
+			continue
+		}
+		if c.profileInUse(name) {
+			logrus.Warnf("Not unloading removed AppArmor profile %s because it is in use", name)
+			profile.stale = true
+			profiles[name] = profile
+			continue
+		}
+		unloadProfile(name)
+	}
+	c.managedProfiles = profiles
+	return nil
+}
+
+// AddProfileUser tracks that the container with the provided ID uses the
+// AppArmor profile, which prevents a managed profile from being unloaded.
+func (c *Config) AddProfileUser(id, profile string) {
+	profile = strings.TrimPrefix(profile, v1.AppArmorBetaProfileNamePrefix)
+	if profile == "" || profile == v1.AppArmorBetaProfileNameUnconfined {
+		return
+	}
+	c.profilesMutex.Lock()
+	defer c.profilesMutex.Unlock()
+	c.profileUsers[id] = profile
+}
+
+// RemoveProfileUser stops tracking the AppArmor profile of the container with
+// the provided ID. A managed profile which has been removed from the profiles
+// directory gets unloaded if it is not used anymore.
+func (c *Config) RemoveProfileUser(id string) {
+	c.profilesMutex.Lock()
+	defer c.profilesMutex.Unlock()
+	profile, ok := c.profileUsers[id]
+	if !ok {
+		return
+	}
+	delete(c.profileUsers, id)
+
+	if managed, ok := c.managedProfiles[profile]; ok && managed.stale && !c.profileInUse(profile) {
+		unloadProfile(profile)
+		delete(c.managedProfiles, profile)
+	}
+}
+
+func (c *Config) profileInUse(profile string) bool {
+	for _, used := range c.profileUsers {
+		if used == profile {
+			return true
+		}
+	}
+	return false
+}
+
+// profileFiles returns the paths of the regular, not hidden files in dir.
+func profileFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			logrus.Infof("Skipping not-existing AppArmor profiles directory %q", dir)
+			return nil, nil
+		}
+		return nil, err
+	}
+	files := []string{}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		files = append(files, filepath.Join(dir, entry.Name()))
+	}
+	return files, nil
+}
+
+// profileNames returns the names of the profiles defined in file.
+func profileNames(file string) ([]string, error) {
+	output, err := cmdrunner.CombinedOutput(parserBinary, "--names", file)
+	if err != nil {
+		return nil, fmt.Errorf("parse AppArmor profile file %s: %s: %w", file, output, err)
+	}
+	names := []string{}
+	for _, name := range strings.Split(string(output), "\n") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("AppArmor profile file %s does not define any profile", file)
+	}
+	return names, nil
+}
+
+func unloadProfile(name string) {
+	if err := os.WriteFile(profileRemovePath, []byte(name), 0o644); err != nil {
+		logrus.Warnf("Unable to unload AppArmor profile %s: %v", name, err)
+		return
+	}
+	logrus.Infof("Unloaded AppArmor profile %s", name)
+}
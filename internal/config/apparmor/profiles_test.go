@@ -0,0 +1,173 @@
+package apparmor_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/cri-o/cri-o/internal/config/apparmor"
+	runnerMock "github.com/cri-o/cri-o/test/mocks/cmdrunner"
+	"github.com/cri-o/cri-o/utils/cmdrunner"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// The actual test suite
+var _ = t.Describe("ProfilesDir", func() {
+	var (
+		sut        *apparmor.Config
+		runner     *runnerMock.MockCommandRunner
+		dir        string
+		removePath string
+	)
+
+	BeforeEach(func() {
+		sut = apparmor.New()
+		sut.SetEnabled(true)
+		runner = runnerMock.NewMockCommandRunner(mockCtrl)
+		cmdrunner.SetMocked(runner)
+		dir = t.MustTempDir("apparmor")
+		removePath = filepath.Join(t.MustTempDir("securityfs"), ".remove")
+		apparmor.SetProfileRemovePath(removePath)
+	})
+
+	writeProfile := func(name string) string {
+		file := filepath.Join(dir, name)
+		Expect(os.WriteFile(file, []byte("profile "+name+" {}\n"), 0o644)).To(BeNil())
+		return file
+	}
+
+	expectLoad := func(file string, names ...string) {
+		output := ""
+		for _, name := range names {
+			output += name + "\n"
+		}
+		runner.EXPECT().CombinedOutput("apparmor_parser", "--names", file).Return([]byte(output), nil)
+		runner.EXPECT().CombinedOutput("apparmor_parser", "--replace", file).Return(nil, nil)
+	}
+
+	It("should succeed with not existing directory", func() {
+		// Given
+		// When
+		err := sut.LoadProfilesDir(filepath.Join(dir, "not-existing"))
+
+		// Then
+		Expect(err).To(BeNil())
+	})
+
+	It("should load all profile files", func() {
+		// Given
+		expectLoad(writeProfile("first"), "first")
+		expectLoad(writeProfile("second"), "second", "second//child")
+		Expect(os.Mkdir(filepath.Join(dir, "sub"), 0o755)).To(BeNil())
+		Expect(os.WriteFile(filepath.Join(dir, ".hidden"), nil, 0o644)).To(BeNil())
+
+		// When
+		err := sut.LoadProfilesDir(dir)
+
+		// Then
+		Expect(err).To(BeNil())
+	})
+
+	It("should fail if the profiles cannot be parsed", func() {
+		// Given
+		file := writeProfile("broken")
+		runner.EXPECT().CombinedOutput("apparmor_parser", "--names", file).
+			Return([]byte("syntax error"), errors.New("exit status 1"))
+
+		// When
+		err := sut.LoadProfilesDir(dir)
+
+		// Then
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should fail if a profile is defined twice", func() {
+		// Given
+		first := writeProfile("first")
+		second := writeProfile("second")
+		runner.EXPECT().CombinedOutput("apparmor_parser", "--names", first).Return([]byte("same\n"), nil)
+		runner.EXPECT().CombinedOutput("apparmor_parser", "--names", second).Return([]byte("same\n"), nil)
+
+		// When
+		err := sut.LoadProfilesDir(dir)
+
+		// Then
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should fail if the default profile is defined", func() {
+		// Given
+		file := writeProfile("default")
+		runner.EXPECT().CombinedOutput("apparmor_parser", "--names", file).
+			Return([]byte(apparmor.DefaultProfile+"\n"), nil)
+
+		// When
+		err := sut.LoadProfilesDir(dir)
+
+		// Then
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should unload removed profiles which are not in use", func() {
+		// Given
+		file := writeProfile("removed")
+		expectLoad(file, "removed")
+		Expect(sut.LoadProfilesDir(dir)).To(BeNil())
+		Expect(os.Remove(file)).To(BeNil())
+
+		// When
+		err := sut.LoadProfilesDir(dir)
+
+		// Then
+		Expect(err).To(BeNil())
+		content, err := os.ReadFile(removePath)
+		Expect(err).To(BeNil())
+		Expect(string(content)).To(Equal("removed"))
+	})
+
+	It("should unload removed profiles once they are not in use anymore", func() {
+		// Given
+		file := writeProfile("removed")
+		expectLoad(file, "removed")
+		Expect(sut.LoadProfilesDir(dir)).To(BeNil())
+		sut.AddProfileUser("first", "localhost/removed")
+		sut.AddProfileUser("second", "removed")
+		Expect(os.Remove(file)).To(BeNil())
+
+		// When
+		Expect(sut.LoadProfilesDir(dir)).To(BeNil())
+		sut.RemoveProfileUser("first")
+
+		// Then
+		_, err := os.Stat(removePath)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+
+		// When
+		sut.RemoveProfileUser("second")
+
+		// Then
+		content, err := os.ReadFile(removePath)
+		Expect(err).To(BeNil())
+		Expect(string(content)).To(Equal("removed"))
+	})
+
+	It("should keep profiles which are added again", func() {
+		// Given
+		file := writeProfile("readded")
+		expectLoad(file, "readded")
+		Expect(sut.LoadProfilesDir(dir)).To(BeNil())
+		sut.AddProfileUser("id", "readded")
+		Expect(os.Remove(file)).To(BeNil())
+		Expect(sut.LoadProfilesDir(dir)).To(BeNil())
+		expectLoad(writeProfile("readded"), "readded")
+
+		// When
+		Expect(sut.LoadProfilesDir(dir)).To(BeNil())
+		sut.RemoveProfileUser("id")
+
+		// Then
+		_, err := os.Stat(removePath)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+})
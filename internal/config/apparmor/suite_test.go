@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	. "github.com/cri-o/cri-o/test/framework"
+	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -14,11 +15,15 @@ func TestLibConfig(t *testing.T) {
 	RunFrameworkSpecs(t, "AppArmorConfig")
 }
 
-var t *TestFramework
+var (
+	t        *TestFramework
+	mockCtrl *gomock.Controller
+)
 
 var _ = BeforeSuite(func() {
 	t = NewTestFramework(NilFunc, NilFunc)
 	t.Setup()
+	mockCtrl = gomock.NewController(GinkgoT())
 })
 
 var _ = AfterSuite(func() {
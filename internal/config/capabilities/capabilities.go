@@ -38,3 +38,42 @@ func (c Capabilities) Validate() error {
 	logrus.Infof("Using default capabilities: %s", strings.Join(caps, ", "))
 	return nil
 }
+
+// All is the capability name representing all capabilities.
+const All = "ALL"
+
+// Normalize returns the upper case name of the capability with the "CAP_"
+// prefix, or "ALL".
+func Normalize(capability string) string {
+	capability = strings.ToUpper(capability)
+	if capability == All || strings.HasPrefix(capability, "CAP_") {
+		return capability
+	}
+	return "CAP_" + capability
+}
+
+// ValidateAllowed checks if the provided allowed capabilities are available on
+// the system. "ALL" allows all capabilities.
+func (c Capabilities) ValidateAllowed() error {
+	caps := []string{}
+	for _, cap := range c {
+		if cap = Normalize(cap); cap != All {
+			caps = append(caps, cap)
+		}
+	}
+	if err := common.ValidateCapabilities(caps); err != nil {
+		return fmt.Errorf("validating allowed capabilities: %w", err)
+	}
+	return nil
+}
+
+// Allows returns true if the capability is part of the allowed capabilities.
+func (c Capabilities) Allows(capability string) bool {
+	capability = Normalize(capability)
+	for _, allowed := range c {
+		if allowed = Normalize(allowed); allowed == All || allowed == capability {
+			return true
+		}
+	}
+	return false
+}
@@ -40,4 +40,47 @@ var _ = t.Describe("Capabilities", func() {
 		// Then
 		Expect(err).NotTo(BeNil())
 	})
+
+	It("should succeed to validate allowed capabilities", func() {
+		// Given
+		sut := capabilities.Capabilities{"ALL", "CAP_net_admin", "chown"}
+
+		// When
+		err := sut.ValidateAllowed()
+
+		// Then
+		Expect(err).To(BeNil())
+	})
+
+	It("should fail to validate wrong allowed capabilities", func() {
+		// Given
+		sut := capabilities.Capabilities{"CAP_WRONG"}
+
+		// When
+		err := sut.ValidateAllowed()
+
+		// Then
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should allow only the listed capabilities", func() {
+		// Given
+		sut := capabilities.Capabilities{"chown", "CAP_NET_ADMIN"}
+
+		// When
+		// Then
+		Expect(sut.Allows("CAP_CHOWN")).To(BeTrue())
+		Expect(sut.Allows("net_admin")).To(BeTrue())
+		Expect(sut.Allows("CAP_SYS_ADMIN")).To(BeFalse())
+		Expect(capabilities.Capabilities{}.Allows("CAP_CHOWN")).To(BeFalse())
+	})
+
+	It("should allow any capability with ALL", func() {
+		// Given
+		sut := capabilities.Capabilities{"all"}
+
+		// When
+		// Then
+		Expect(sut.Allows("CAP_SYS_ADMIN")).To(BeTrue())
+	})
 })
@@ -1,21 +1,68 @@
 package cnimgr
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 	"time"
 
+	"github.com/containernetworking/cni/libcni"
+	"github.com/containernetworking/cni/pkg/invoke"
 	"github.com/cri-o/ocicni/pkg/ocicni"
-	"k8s.io/apimachinery/pkg/util/wait"
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
 )
 
+const (
+	// ReasonNetworkPluginNotReady is the reason if the CNI plugin has no
+	// default network.
+	ReasonNetworkPluginNotReady = "NetworkPluginNotReady"
+
+	// ReasonNetworkConfigInvalid is the reason if the configuration of the
+	// default network cannot be loaded.
+	ReasonNetworkConfigInvalid = "NetworkConfigInvalid"
+
+	// ReasonNetworkPluginBinaryMissing is the reason if a plugin binary of the
+	// default network cannot be found in the plugin directories.
+	ReasonNetworkPluginBinaryMissing = "NetworkPluginBinaryMissing"
+
+	// pollInterval is the interval to check the readiness while the plugin
+	// is not ready or the directories cannot be watched.
+	pollInterval = 500 * time.Millisecond
+
+	// settleDelay is the delay between a change in the watched directories
+	// and the check, which combines the events of a single update.
+	settleDelay = 200 * time.Millisecond
+)
+
+// NotReadyError is returned if the CNI plugin is not ready.
+type NotReadyError struct {
+	// Reason is a machine readable reason, like ReasonNetworkConfigInvalid.
+	Reason string
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *NotReadyError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *NotReadyError) Unwrap() error {
+	return e.Err
+}
+
 type CNIManager struct {
 	// cniPlugin is the internal OCI CNI plugin
-	plugin    ocicni.CNIPlugin
-	lastError error
-	watchers  []chan bool
-	shutdown  bool
-	mutex     sync.RWMutex
+	plugin     ocicni.CNIPlugin
+	networkDir string
+	pluginDirs []string
+	lastError  error
+	watchers   []chan bool
+	// statusHandler is called whenever the readiness of the plugin changes
+	statusHandler func(error)
+	shutdown      bool
+	done          chan struct{}
+	mutex         sync.RWMutex
 }
 
 func New(defaultNetwork, networkDir string, pluginDirs ...string) (*CNIManager, error) {
@@ -27,54 +74,191 @@ func New(defaultNetwork, networkDir string, pluginDirs ...string) (*CNIManager,
 		return nil, fmt.Errorf("initialize CNI plugin: %w", err)
 	}
 	mgr := &CNIManager{
-		plugin: plugin,
+		plugin:     plugin,
+		networkDir: networkDir,
+		pluginDirs: pluginDirs,
+		lastError:  &NotReadyError{Reason: ReasonNetworkPluginNotReady, Err: errors.New("CNI plugin not checked yet")},
+		done:       make(chan struct{}),
 	}
-	go mgr.pollUntilReady()
+	go mgr.monitor()
 	return mgr, nil
 }
 
-func (c *CNIManager) pollUntilReady() {
-	// nolint:errcheck
-	_ = wait.PollInfinite(500*time.Millisecond, c.pollFunc)
+// monitor checks the readiness of the CNI plugin on every change of the
+// network and plugin directories until the manager gets shut down. The
+// plugin is polled while it is not ready or while a directory cannot be
+// watched, for example because it does not exist yet.
+func (c *CNIManager) monitor() {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		logrus.Warnf("Unable to watch CNI directories, polling them instead: %v", err)
+	} else {
+		defer watcher.Close()
+	}
+	dirs := append([]string{c.networkDir}, c.pluginDirs...)
+	watched := make(map[string]bool, len(dirs))
+
+	poll := time.NewTicker(pollInterval)
+	defer poll.Stop()
+	settle := time.NewTimer(0)
+	defer settle.Stop()
+
+	var events <-chan fsnotify.Event
+	var watchErrors <-chan error
+	if watcher != nil {
+		events = watcher.Events
+		watchErrors = watcher.Errors
+	}
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-settle.C:
+		case <-poll.C:
+			// the CNI plugin reloads its configuration on its own, which
+			// means that it has to be polled until it is ready
+			if watcher != nil && len(watched) == len(dirs) && c.ReadyOrError() == nil {
+				continue
+			}
+		case event, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			logrus.Debugf("CNI directory event: %v", event)
+			settle.Reset(settleDelay)
+			continue
+		case err, ok := <-watchErrors:
+			if !ok {
+				watchErrors = nil
+				continue
+			}
+			logrus.Warnf("Error watching CNI directories: %v", err)
+			continue
+		}
+
+		if watcher != nil {
+			for _, dir := range dirs {
+				if watched[dir] {
+					continue
+				}
+				if err := watcher.Add(dir); err != nil {
+					logrus.Debugf("Unable to watch CNI directory %s yet: %v", dir, err)
+					continue
+				}
+				watched[dir] = true
+			}
+		}
+		//nolint:errcheck
+		_, _ = c.pollFunc()
+	}
 }
 
+// pollFunc checks the readiness of the CNI plugin and notifies the watchers
+// if it became ready. It returns true if the plugin is ready or the manager
+// got shut down.
 func (c *CNIManager) pollFunc() (bool, error) {
 	c.mutex.Lock()
-	defer c.mutex.Unlock()
 	if c.shutdown {
+		c.mutex.Unlock()
 		return true, nil
 	}
+	wasReady := c.lastError == nil
+	err := c.validate()
+	changed := false
+	if err != nil {
+		if wasReady || c.lastError.Error() != err.Error() {
+			logrus.Warnf("CNI network not ready: %v", err)
+			changed = true
+		}
+	} else {
+		if !wasReady {
+			logrus.Infof("CNI network %s is ready", c.plugin.GetDefaultNetworkName())
+			changed = true
+		}
+		for _, watcher := range c.watchers {
+			watcher <- true
+		}
+		c.watchers = nil
+	}
+	c.lastError = err
+	statusHandler := c.statusHandler
+	c.mutex.Unlock()
+
+	// the handler is called without holding the lock, which allows it to
+	// use the manager
+	if changed && statusHandler != nil {
+		statusHandler(err)
+	}
+	return err == nil, nil
+}
+
+// validate checks that the CNI plugin has a default network, and that its
+// configuration can be loaded and all its plugin binaries exist.
+func (c *CNIManager) validate() error {
 	if err := c.plugin.Status(); err != nil {
-		c.lastError = err
-		return false, nil
+		return &NotReadyError{Reason: ReasonNetworkPluginNotReady, Err: err}
 	}
-	c.lastError = nil
-	for _, watcher := range c.watchers {
-		watcher <- true
+	if c.networkDir == "" {
+		return nil
+	}
+
+	network := c.plugin.GetDefaultNetworkName()
+	confList, err := libcni.LoadConfList(c.networkDir, network)
+	if err != nil {
+		return &NotReadyError{
+			Reason: ReasonNetworkConfigInvalid,
+			Err:    fmt.Errorf("load configuration of CNI network %s: %w", network, err),
+		}
+	}
+	for _, plugin := range confList.Plugins {
+		if _, err := invoke.FindInPath(plugin.Network.Type, c.pluginDirs); err != nil {
+			return &NotReadyError{
+				Reason: ReasonNetworkPluginBinaryMissing,
+				Err:    fmt.Errorf("CNI network %s: %w", network, err),
+			}
+		}
 	}
-	return true, nil
+	return nil
 }
 
 // ReadyOrError returns nil if the plugin is ready,
 // or the last error that was received in checking.
+// The error is a *NotReadyError, which contains the reason.
 func (c *CNIManager) ReadyOrError() error {
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
 	return c.lastError
 }
 
+// SetStatusHandler sets the handler which is called whenever the readiness of
+// the CNI plugin changes. The handler gets nil if the plugin became ready, or
+// the *NotReadyError otherwise.
+func (c *CNIManager) SetStatusHandler(handler func(error)) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.statusHandler = handler
+}
+
 // Plugin returns the CNI plugin
 func (c *CNIManager) Plugin() ocicni.CNIPlugin {
 	return c.plugin
 }
 
 // Add watcher creates a new watcher for the CNI manager
-// said watcher will send a `true` value if the CNI plugin was successfully ready
+// said watcher will send a `true` value once the CNI plugin is ready again
 // or `false` if the server shutdown first
 func (c *CNIManager) AddWatcher() chan bool {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 	watcher := make(chan bool, 1)
+	if c.shutdown || c.lastError == nil {
+		// the manager got shut down or the plugin became ready in the
+		// meantime
+		watcher <- !c.shutdown
+		return watcher
+	}
 	c.watchers = append(c.watchers, watcher)
 
 	return watcher
@@ -85,8 +269,15 @@ func (c *CNIManager) AddWatcher() chan bool {
 func (c *CNIManager) Shutdown() {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
+	if c.shutdown {
+		return
+	}
 	c.shutdown = true
+	if c.done != nil {
+		close(c.done)
+	}
 	for _, watcher := range c.watchers {
 		watcher <- false
 	}
+	c.watchers = nil
 }
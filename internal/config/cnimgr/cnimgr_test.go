@@ -0,0 +1,133 @@
+package cnimgr_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cri-o/cri-o/internal/config/cnimgr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// pluginBinary answers the VERSION command of CNI, which is used to validate
+// the network configuration
+const pluginBinary = `#!/bin/sh
+echo '{"cniVersion": "1.0.0", "supportedVersions": ["0.4.0", "1.0.0"]}'
+`
+
+const networkConfig = `{
+	"cniVersion": "1.0.0",
+	"name": "test",
+	"plugins": [{"type": "bridge"}, {"type": "portmap"}]
+}`
+
+func notReadyReason(sut *cnimgr.CNIManager) func() string {
+	return func() string {
+		err := sut.ReadyOrError()
+		if err == nil {
+			return ""
+		}
+		var notReadyErr *cnimgr.NotReadyError
+		Expect(errors.As(err, &notReadyErr)).To(BeTrue())
+		return notReadyErr.Reason
+	}
+}
+
+// The actual test suite
+var _ = t.Describe("CNIManager", func() {
+	var (
+		sut        *cnimgr.CNIManager
+		networkDir string
+		pluginDir  string
+	)
+
+	writePlugin := func(name string) {
+		Expect(os.WriteFile(filepath.Join(pluginDir, name), []byte(pluginBinary), 0o755)).To(BeNil())
+	}
+
+	BeforeEach(func() {
+		networkDir = t.MustTempDir("cni-config")
+		pluginDir = t.MustTempDir("cni-plugins")
+		writePlugin("bridge")
+		writePlugin("portmap")
+
+		var err error
+		sut, err = cnimgr.New("", networkDir, pluginDir)
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		sut.Shutdown()
+	})
+
+	It("should track the readiness of the default network", func() {
+		// Given
+		Eventually(notReadyReason(sut), 5*time.Second).
+			Should(Equal(cnimgr.ReasonNetworkPluginNotReady))
+		watcher := sut.AddWatcher()
+
+		// When
+		Expect(os.WriteFile(filepath.Join(networkDir, "10-test.conflist"),
+			[]byte(networkConfig), 0o644)).To(BeNil())
+
+		// Then
+		Eventually(watcher, 5*time.Second).Should(Receive(BeTrue()))
+		Expect(sut.ReadyOrError()).To(BeNil())
+
+		// When
+		Expect(os.Remove(filepath.Join(pluginDir, "portmap"))).To(BeNil())
+
+		// Then
+		Eventually(notReadyReason(sut), 5*time.Second).
+			Should(Equal(cnimgr.ReasonNetworkPluginBinaryMissing))
+		Expect(sut.ReadyOrError().Error()).To(ContainSubstring("portmap"))
+		watcher = sut.AddWatcher()
+
+		// When
+		writePlugin("portmap")
+
+		// Then
+		Eventually(watcher, 5*time.Second).Should(Receive(BeTrue()))
+		Expect(sut.ReadyOrError()).To(BeNil())
+	})
+
+	It("should call the status handler on readiness changes", func() {
+		// Given
+		statuses := make(chan error, 10)
+		sut.SetStatusHandler(func(err error) { statuses <- err })
+		Eventually(notReadyReason(sut), 5*time.Second).
+			Should(Equal(cnimgr.ReasonNetworkPluginNotReady))
+
+		// When
+		Expect(os.WriteFile(filepath.Join(networkDir, "10-test.conflist"),
+			[]byte(networkConfig), 0o644)).To(BeNil())
+
+		// Then
+		Eventually(statuses, 5*time.Second).Should(Receive(BeNil()))
+
+		// When
+		Expect(os.Remove(filepath.Join(pluginDir, "bridge"))).To(BeNil())
+
+		// Then
+		var err error
+		Eventually(statuses, 5*time.Second).Should(Receive(&err))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("bridge"))
+	})
+
+	It("should notify watchers on shutdown", func() {
+		// Given
+		Eventually(notReadyReason(sut), 5*time.Second).
+			Should(Equal(cnimgr.ReasonNetworkPluginNotReady))
+		watcher := sut.AddWatcher()
+
+		// When
+		sut.Shutdown()
+
+		// Then
+		Expect(watcher).To(Receive(BeFalse()))
+		Expect(sut.AddWatcher()).To(Receive(BeFalse()))
+	})
+})
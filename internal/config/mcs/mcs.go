@@ -0,0 +1,298 @@
+package mcs
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	json "github.com/json-iterator/go"
+	"github.com/sirupsen/logrus"
+)
+
+// MaxCategory is the highest MCS category which can be allocated.
+const MaxCategory = 1023
+
+// ErrExhausted is returned if no MCS level is available anymore.
+var ErrExhausted = errors.New("no MCS level available")
+
+// Range is an inclusive range of MCS categories.
+type Range struct {
+	Min int
+	Max int
+}
+
+// DefaultRange returns the range of all MCS categories.
+func DefaultRange() Range {
+	return Range{Min: 0, Max: MaxCategory}
+}
+
+// ParseRange parses a range of MCS categories in the form "cMIN.cMAX".
+func ParseRange(s string) (Range, error) {
+	minCategory, maxCategory, ok := strings.Cut(s, ".")
+	if !ok {
+		return Range{}, fmt.Errorf("invalid MCS category range %q, expected cMIN.cMAX", s)
+	}
+	r := Range{}
+	var err error
+	if r.Min, err = parseCategory(minCategory); err != nil {
+		return Range{}, err
+	}
+	if r.Max, err = parseCategory(maxCategory); err != nil {
+		return Range{}, err
+	}
+	if r.Min >= r.Max {
+		return Range{}, fmt.Errorf("MCS category range %q has to contain at least two categories", s)
+	}
+	return r, nil
+}
+
+func (r Range) String() string {
+	return fmt.Sprintf("c%d.c%d", r.Min, r.Max)
+}
+
+// size returns the number of MCS levels with two categories of the range.
+func (r Range) size() int {
+	n := r.Max - r.Min + 1
+	return n * (n - 1) / 2
+}
+
+func parseCategory(s string) (int, error) {
+	if !strings.HasPrefix(s, "c") {
+		return 0, fmt.Errorf("invalid MCS category %q", s)
+	}
+	category, err := strconv.Atoi(s[1:])
+	if err != nil || category < 0 || category > MaxCategory {
+		return 0, fmt.Errorf("invalid MCS category %q", s)
+	}
+	return category, nil
+}
+
+// ConflictError is returned if an MCS level is used by more than one sandbox.
+type ConflictError struct {
+	Level string
+	ID    string
+	Other string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("MCS level %s of sandbox %s is already allocated to sandbox %s", e.Level, e.ID, e.Other)
+}
+
+// Allocator hands out unique MCS levels with two categories to sandboxes,
+// and persists the allocations in a file.
+type Allocator struct {
+	path string
+	// allocations are the levels handed out by Allocate, by sandbox ID.
+	allocations map[string]string
+	// reserved are the sandbox IDs by level, for levels which have not
+	// been handed out by Allocate.
+	reserved map[string]map[string]struct{}
+	mutex    sync.Mutex
+}
+
+// New creates a new allocator, which persists the allocations in the file at
+// path.
+func New(path string) (*Allocator, error) {
+	a := &Allocator{
+		path:        path,
+		allocations: make(map[string]string),
+		reserved:    make(map[string]map[string]struct{}),
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return a, nil
+		}
+		return nil, fmt.Errorf("read MCS allocations: %w", err)
+	}
+	if err := json.Unmarshal(content, &a.allocations); err != nil {
+		return nil, fmt.Errorf("decode MCS allocations: %w", err)
+	}
+	return a, nil
+}
+
+// Allocate allocates an unused MCS level of the range for the sandbox with
+// the provided ID. It returns the existing allocation if the sandbox has
+// already one.
+func (a *Allocator) Allocate(id string, r Range) (string, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if level, ok := a.allocations[id]; ok {
+		return level, nil
+	}
+	used := make(map[string]struct{}, len(a.allocations)+len(a.reserved))
+	for _, level := range a.allocations {
+		used[level] = struct{}{}
+	}
+	for level := range a.reserved {
+		used[level] = struct{}{}
+	}
+
+	// start at a random level, like the selinux library does, and take the
+	// next unused one
+	start, err := rand.Int(rand.Reader, big.NewInt(int64(r.size())))
+	if err != nil {
+		return "", err
+	}
+	c1, c2 := r.Min, r.Min+1
+	for i := int64(0); i < start.Int64(); i++ {
+		c1, c2 = r.next(c1, c2)
+	}
+	for i := 0; i < r.size(); i++ {
+		level := formatLevel(c1, c2)
+		if _, ok := used[level]; !ok {
+			a.allocations[id] = level
+			if err := a.save(); err != nil {
+				delete(a.allocations, id)
+				return "", err
+			}
+			return level, nil
+		}
+		c1, c2 = r.next(c1, c2)
+	}
+	return "", ErrExhausted
+}
+
+// next returns the categories of the level following the provided one,
+// wrapping around at the end of the range.
+func (r Range) next(c1, c2 int) (int, int) {
+	c2++
+	if c2 > r.Max {
+		c1++
+		c2 = c1 + 1
+	}
+	if c2 > r.Max {
+		c1, c2 = r.Min, r.Min+1
+	}
+	return c1, c2
+}
+
+// Reserve records the MCS level used by an existing sandbox, which is
+// required after a restart. It returns a ConflictError if the level is
+// allocated to another sandbox. Levels without two categories are ignored.
+func (a *Allocator) Reserve(id, level string) error {
+	level, ok := normalizeLevel(level)
+	if !ok {
+		return nil
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if allocated, ok := a.allocations[id]; ok {
+		if allocated == level {
+			return nil
+		}
+		logrus.Warnf("Sandbox %s uses MCS level %s instead of the allocated %s", id, level, allocated)
+		delete(a.allocations, id)
+		if err := a.save(); err != nil {
+			return err
+		}
+	}
+	if ids, ok := a.reserved[level]; ok {
+		ids[id] = struct{}{}
+	} else {
+		a.reserved[level] = map[string]struct{}{id: {}}
+	}
+	for other, allocated := range a.allocations {
+		if allocated == level {
+			return &ConflictError{Level: level, ID: id, Other: other}
+		}
+	}
+	return nil
+}
+
+// Release frees the MCS level of the sandbox with the provided ID.
+func (a *Allocator) Release(id string) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.unreserve(id)
+	if _, ok := a.allocations[id]; !ok {
+		return nil
+	}
+	delete(a.allocations, id)
+	return a.save()
+}
+
+// Prune frees the MCS levels of all sandboxes for which exists returns false.
+func (a *Allocator) Prune(exists func(id string) bool) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	pruned := false
+	for id := range a.allocations {
+		if exists(id) {
+			continue
+		}
+		logrus.Infof("Releasing MCS level of removed sandbox %s", id)
+		delete(a.allocations, id)
+		pruned = true
+	}
+	if !pruned {
+		return nil
+	}
+	return a.save()
+}
+
+func (a *Allocator) unreserve(id string) {
+	for level, ids := range a.reserved {
+		delete(ids, id)
+		if len(ids) == 0 {
+			delete(a.reserved, level)
+		}
+	}
+}
+
+func (a *Allocator) save() error {
+	content, err := json.Marshal(a.allocations)
+	if err != nil {
+		return fmt.Errorf("encode MCS allocations: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(a.path), 0o700); err != nil {
+		return err
+	}
+	tmpPath := a.path + ".tmp"
+	if err := os.WriteFile(tmpPath, content, 0o600); err != nil {
+		return fmt.Errorf("write MCS allocations: %w", err)
+	}
+	return os.Rename(tmpPath, a.path)
+}
+
+func formatLevel(c1, c2 int) string {
+	return fmt.Sprintf("s0:c%d,c%d", c1, c2)
+}
+
+// normalizeLevel returns the level in the form used by Allocate, if it
+// consists of the sensitivity s0 and two categories.
+func normalizeLevel(level string) (string, bool) {
+	sensitivity, categories, ok := strings.Cut(level, ":")
+	if !ok || sensitivity != "s0" {
+		return "", false
+	}
+	parts := strings.Split(categories, ",")
+	if len(parts) != 2 {
+		return "", false
+	}
+	values := make([]int, 0, len(parts))
+	for _, part := range parts {
+		category, err := parseCategory(part)
+		if err != nil {
+			return "", false
+		}
+		values = append(values, category)
+	}
+	sort.Ints(values)
+	if values[0] == values[1] {
+		return "", false
+	}
+	return formatLevel(values[0], values[1]), true
+}
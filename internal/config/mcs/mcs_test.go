@@ -0,0 +1,171 @@
+package mcs_test
+
+import (
+	"path/filepath"
+
+	"github.com/cri-o/cri-o/internal/config/mcs"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = t.Describe("ParseRange", func() {
+	It("should succeed with a valid range", func() {
+		// Given
+		// When
+		res, err := mcs.ParseRange("c0.c511")
+
+		// Then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(mcs.Range{Min: 0, Max: 511}))
+		Expect(res.String()).To(Equal("c0.c511"))
+	})
+
+	It("should fail with invalid ranges", func() {
+		for _, r := range []string{"c0", "0.511", "c0.c1024", "c5.c5", "c10.c5", "ca.c5"} {
+			// Given
+			// When
+			_, err := mcs.ParseRange(r)
+
+			// Then
+			Expect(err).To(HaveOccurred(), r)
+		}
+	})
+})
+
+var _ = t.Describe("Allocator", func() {
+	var (
+		path string
+		sut  *mcs.Allocator
+	)
+
+	BeforeEach(func() {
+		path = filepath.Join(t.MustTempDir("mcs"), "allocations.json")
+		var err error
+		sut, err = mcs.New(path)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should allocate unique levels within the range", func() {
+		// Given
+		r := mcs.Range{Min: 10, Max: 12}
+		levels := []string{}
+
+		// When
+		for _, id := range []string{"first", "second", "third"} {
+			level, err := sut.Allocate(id, r)
+			Expect(err).ToNot(HaveOccurred())
+			levels = append(levels, level)
+		}
+
+		// Then
+		Expect(levels).To(ConsistOf("s0:c10,c11", "s0:c10,c12", "s0:c11,c12"))
+	})
+
+	It("should return the existing allocation of a sandbox", func() {
+		// Given
+		first, err := sut.Allocate("sandbox", mcs.DefaultRange())
+		Expect(err).ToNot(HaveOccurred())
+
+		// When
+		second, err := sut.Allocate("sandbox", mcs.DefaultRange())
+
+		// Then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(second).To(Equal(first))
+	})
+
+	It("should fail if exhausted", func() {
+		// Given
+		r := mcs.Range{Min: 0, Max: 1}
+		_, err := sut.Allocate("first", r)
+		Expect(err).ToNot(HaveOccurred())
+
+		// When
+		_, err = sut.Allocate("second", r)
+
+		// Then
+		Expect(err).To(MatchError(mcs.ErrExhausted))
+	})
+
+	It("should not allocate reserved levels", func() {
+		// Given
+		r := mcs.Range{Min: 0, Max: 1}
+		Expect(sut.Reserve("existing", "s0:c1,c0")).To(Succeed())
+
+		// When
+		_, err := sut.Allocate("new", r)
+
+		// Then
+		Expect(err).To(MatchError(mcs.ErrExhausted))
+	})
+
+	It("should reuse released levels", func() {
+		// Given
+		r := mcs.Range{Min: 0, Max: 1}
+		level, err := sut.Allocate("first", r)
+		Expect(err).ToNot(HaveOccurred())
+
+		// When
+		Expect(sut.Release("first")).To(Succeed())
+		res, err := sut.Allocate("second", r)
+
+		// Then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(level))
+	})
+
+	It("should persist allocations", func() {
+		// Given
+		r := mcs.Range{Min: 0, Max: 2}
+		level, err := sut.Allocate("first", r)
+		Expect(err).ToNot(HaveOccurred())
+
+		// When
+		restored, err := mcs.New(path)
+		Expect(err).ToNot(HaveOccurred())
+
+		// Then
+		Expect(restored.Reserve("first", level)).To(Succeed())
+		res, err := restored.Allocate("first", r)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(level))
+	})
+
+	It("should report conflicts with allocated levels", func() {
+		// Given
+		level, err := sut.Allocate("first", mcs.DefaultRange())
+		Expect(err).ToNot(HaveOccurred())
+
+		// When
+		err = sut.Reserve("second", level)
+
+		// Then
+		conflict := &mcs.ConflictError{}
+		Expect(err).To(BeAssignableToTypeOf(conflict))
+		Expect(err.(*mcs.ConflictError).Other).To(Equal("first"))
+	})
+
+	It("should ignore levels without two categories", func() {
+		// Given
+		// When
+		err := sut.Reserve("privileged", "s0-s0:c0.c1023")
+
+		// Then
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should prune allocations of removed sandboxes", func() {
+		// Given
+		r := mcs.Range{Min: 0, Max: 1}
+		_, err := sut.Allocate("removed", r)
+		Expect(err).ToNot(HaveOccurred())
+
+		// When
+		err = sut.Prune(func(id string) bool { return false })
+
+		// Then
+		Expect(err).ToNot(HaveOccurred())
+		_, err = sut.Allocate("new", r)
+		Expect(err).ToNot(HaveOccurred())
+	})
+})
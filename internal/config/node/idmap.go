@@ -0,0 +1,61 @@
+//go:build linux
+// +build linux
+
+package node
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/containers/storage/pkg/idmap"
+	"github.com/containers/storage/pkg/idtools"
+	"golang.org/x/sys/unix"
+)
+
+var (
+	idMappedMountsOnce      sync.Once
+	idMappedMountsSupported bool
+	idMappedMountsErr       error
+)
+
+// IDMappedMountsSupported returns whether the kernel supports idmapped bind
+// mounts.
+func IDMappedMountsSupported() bool {
+	idMappedMountsOnce.Do(func() {
+		idMappedMountsSupported, idMappedMountsErr = checkIDMappedMounts()
+	})
+	return idMappedMountsSupported
+}
+
+// checkIDMappedMounts creates an idmapped bind mount of a temporary directory
+// to check whether the kernel supports it.
+func checkIDMappedMounts() (bool, error) {
+	dir, err := os.MkdirTemp("", "crio-idmap")
+	if err != nil {
+		return false, err
+	}
+	defer os.RemoveAll(dir)
+
+	source := filepath.Join(dir, "source")
+	target := filepath.Join(dir, "target")
+	if err := os.Mkdir(source, 0o700); err != nil {
+		return false, err
+	}
+
+	mapping := []idtools.IDMap{{ContainerID: 0, HostID: 0, Size: 1}}
+	pid, cleanup, err := idmap.CreateUsernsProcess(mapping, mapping)
+	if err != nil {
+		return false, fmt.Errorf("create user namespace: %w", err)
+	}
+	defer cleanup()
+
+	if err := idmap.CreateIDMappedMount(source, target, pid); err != nil {
+		return false, fmt.Errorf("create idmapped mount: %w", err)
+	}
+	if err := unix.Unmount(target, unix.MNT_DETACH); err != nil {
+		return false, fmt.Errorf("unmount idmapped mount: %w", err)
+	}
+	return true, nil
+}
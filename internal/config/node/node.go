@@ -11,7 +11,8 @@ import (
 
 // ValidateConfig initializes and validates all of the singleton variables
 // that store the node's configuration.
-// Currently, we check hugetlb, cgroup v1 or v2, pid and memory swap support for cgroups.
+// Currently, we check hugetlb, cgroup v1 or v2, pid and memory swap support for cgroups,
+// as well as the support for idmapped mounts.
 // We check the error at server configuration validation, and if we error, shutdown
 // cri-o early, instead of when we're already trying to run containers.
 func ValidateConfig() error {
@@ -65,6 +66,13 @@ func ValidateConfig() error {
 			activated: &systemdHasAllowedCPUs,
 			fatal:     false,
 		},
+		{
+			name:      "idmapped mounts",
+			init:      IDMappedMountsSupported,
+			err:       &idMappedMountsErr,
+			activated: &idMappedMountsSupported,
+			fatal:     false,
+		},
 		{
 			name:      "fs.may_detach_mounts sysctl",
 			init:      checkFsMayDetachMounts,
@@ -23,6 +23,7 @@ import (
 type NamespaceManager struct {
 	namespacesDir string
 	pinnsPath     string
+	pool          *namespacePool
 }
 
 // New creates a new NamespaceManager.
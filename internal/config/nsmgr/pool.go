@@ -0,0 +1,357 @@
+//go:build linux
+// +build linux
+
+package nsmgr
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	nspkg "github.com/containernetworking/plugins/pkg/ns"
+	"github.com/cri-o/cri-o/utils/cmdrunner"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// poolDirName is the sub-directory of the namespaces directory, where the
+	// namespaces of the pool get pinned.
+	poolDirName = "pool"
+
+	// poolRetryInterval is the interval to retry refilling the pool after an
+	// error.
+	poolRetryInterval = 5 * time.Second
+)
+
+// pooledNamespaceTypes are the types of the namespaces in a pooled set, in
+// the order they get returned.
+var pooledNamespaceTypes = []NSType{IPCNS, NETNS, UTSNS}
+
+// namespacePool keeps pre-created sets of network, IPC and UTS namespaces,
+// which can be used by pods without host namespaces, user namespaces or
+// sysctls.
+type namespacePool struct {
+	mgr     *NamespaceManager
+	dir     string
+	size    int
+	sets    [][]Namespace
+	refill  chan struct{}
+	done    chan struct{}
+	stopped chan struct{}
+	closed  bool
+	mutex   sync.Mutex
+}
+
+// StartPool starts pooling size sets of network, IPC and UTS namespaces. The
+// pool gets refilled in the background. It is a no-op if size is not
+// positive or the pool has already been started.
+func (mgr *NamespaceManager) StartPool(size int) error {
+	if size <= 0 || mgr.pool != nil {
+		return nil
+	}
+	dir := filepath.Join(mgr.namespacesDir, poolDirName)
+	// the namespaces of a previous instance cannot be validated, remove them
+	if err := removePoolDir(dir); err != nil {
+		return fmt.Errorf("remove stale namespace pool: %w", err)
+	}
+	for _, nsType := range pooledNamespaceTypes {
+		if err := os.MkdirAll(filepath.Join(dir, string(nsType)+"ns"), 0o755); err != nil {
+			return fmt.Errorf("create namespace pool directory: %w", err)
+		}
+	}
+
+	mgr.pool = &namespacePool{
+		mgr:     mgr,
+		dir:     dir,
+		size:    size,
+		refill:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go mgr.pool.run()
+	logrus.Infof("Started namespace pool of size %d in %s", size, dir)
+	return nil
+}
+
+// StopPool stops refilling the pool and removes the pooled namespaces.
+func (mgr *NamespaceManager) StopPool() {
+	if mgr == nil || mgr.pool == nil {
+		return
+	}
+	pool := mgr.pool
+	pool.mutex.Lock()
+	if pool.closed {
+		pool.mutex.Unlock()
+		return
+	}
+	pool.closed = true
+	close(pool.done)
+	pool.mutex.Unlock()
+	<-pool.stopped
+
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+	for _, set := range pool.sets {
+		removeNamespaces(set)
+	}
+	pool.sets = nil
+	if err := removePoolDir(pool.dir); err != nil {
+		logrus.Warnf("Unable to remove namespace pool: %v", err)
+	}
+}
+
+// TakePooledNamespaces takes a set of network, IPC and UTS namespaces from the
+// pool and pins them like the namespaces created by NewPodNamespaces. It
+// returns false if the pool is disabled or empty, or none of the pooled
+// namespaces are usable anymore.
+// The caller is responsible for cleaning up the namespaces by calling Namespace.Remove().
+func (mgr *NamespaceManager) TakePooledNamespaces() ([]Namespace, bool) {
+	pool := mgr.pool
+	if pool == nil {
+		return nil, false
+	}
+	defer pool.triggerRefill()
+
+	for {
+		set := pool.pop()
+		if set == nil {
+			return nil, false
+		}
+		if err := validatePooledNamespaces(set); err != nil {
+			logrus.Warnf("Discarding pooled namespaces: %v", err)
+			removeNamespaces(set)
+			continue
+		}
+		namespaces, err := mgr.claimNamespaces(set)
+		if err != nil {
+			logrus.Warnf("Unable to claim pooled namespaces: %v", err)
+			continue
+		}
+		return namespaces, true
+	}
+}
+
+// pop removes a set of namespaces from the pool. It returns nil if the pool
+// is empty or has been stopped.
+func (p *namespacePool) pop() []Namespace {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.closed || len(p.sets) == 0 {
+		return nil
+	}
+	set := p.sets[0]
+	p.sets = p.sets[1:]
+	return set
+}
+
+func (p *namespacePool) triggerRefill() {
+	select {
+	case p.refill <- struct{}{}:
+	default:
+	}
+}
+
+// run refills the pool until it gets stopped.
+func (p *namespacePool) run() {
+	defer close(p.stopped)
+	for {
+		var retry <-chan time.Time
+		if err := p.fill(); err != nil {
+			logrus.Warnf("Unable to refill namespace pool: %v", err)
+			retry = time.After(poolRetryInterval)
+		}
+		select {
+		case <-p.done:
+			return
+		case <-p.refill:
+		case <-retry:
+		}
+	}
+}
+
+// fill creates sets of namespaces until the pool is full.
+func (p *namespacePool) fill() error {
+	for {
+		p.mutex.Lock()
+		full := p.closed || len(p.sets) >= p.size
+		p.mutex.Unlock()
+		if full {
+			return nil
+		}
+
+		set, err := p.create()
+		if err != nil {
+			return err
+		}
+
+		p.mutex.Lock()
+		if p.closed {
+			p.mutex.Unlock()
+			removeNamespaces(set)
+			return nil
+		}
+		p.sets = append(p.sets, set)
+		p.mutex.Unlock()
+	}
+}
+
+// create pins a new set of namespaces in the pool directory and brings up the
+// loopback interface of the network namespace.
+func (p *namespacePool) create() (_ []Namespace, retErr error) {
+	pinnedNamespace := uuid.New().String()
+	pinnsArgs := []string{
+		"-d", p.dir,
+		"-f", pinnedNamespace,
+	}
+	for _, nsType := range pooledNamespaceTypes {
+		pinnsArgs = append(pinnsArgs, "--"+string(nsType))
+	}
+
+	logrus.Debugf("Calling pinns with %v", pinnsArgs)
+	set := make([]Namespace, 0, len(pooledNamespaceTypes))
+	defer func() {
+		if retErr != nil {
+			removeNamespaces(set)
+			for _, nsType := range pooledNamespaceTypes {
+				removePinnedNamespace(filepath.Join(p.dir, string(nsType)+"ns", pinnedNamespace))
+			}
+		}
+	}()
+	output, err := cmdrunner.Command(p.mgr.pinnsPath, pinnsArgs...).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to pin pooled namespaces: %s %w", output, err)
+	}
+
+	for _, nsType := range pooledNamespaceTypes {
+		nsPath := filepath.Join(p.dir, string(nsType)+"ns", pinnedNamespace)
+		ns, err := GetNamespace(nsPath, nsType)
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, ns)
+		if nsType != NETNS {
+			continue
+		}
+		if err := nspkg.WithNetNSPath(nsPath, func(nspkg.NetNS) error {
+			lo, err := netlink.LinkByName("lo")
+			if err != nil {
+				return err
+			}
+			return netlink.LinkSetUp(lo)
+		}); err != nil {
+			return nil, fmt.Errorf("set up loopback interface: %w", err)
+		}
+	}
+	return set, nil
+}
+
+// claimNamespaces moves a set of pooled namespaces into the directories of
+// the namespaces managed for pods. The pooled namespaces are removed in any
+// case.
+func (mgr *NamespaceManager) claimNamespaces(set []Namespace) (_ []Namespace, retErr error) {
+	defer removeNamespaces(set)
+
+	pinnedNamespace := uuid.New().String()
+	namespaces := make([]Namespace, 0, len(set))
+	defer func() {
+		if retErr != nil {
+			removeNamespaces(namespaces)
+		}
+	}()
+	for _, pooled := range set {
+		nsPath := filepath.Join(mgr.dirForType(pooled.Type()), pinnedNamespace)
+		f, err := os.Create(nsPath)
+		if err != nil {
+			return nil, fmt.Errorf("creating namespace path: %w", err)
+		}
+		f.Close()
+
+		if err := unix.Mount(pooled.Path(), nsPath, "none", unix.MS_BIND, ""); err != nil {
+			removePinnedNamespace(nsPath)
+			return nil, fmt.Errorf("error mounting %s namespace path: %w", pooled.Type(), err)
+		}
+		ns, err := GetNamespace(nsPath, pooled.Type())
+		if err != nil {
+			removePinnedNamespace(nsPath)
+			return nil, err
+		}
+		namespaces = append(namespaces, ns)
+	}
+	return namespaces, nil
+}
+
+// validatePooledNamespaces checks that the pooled namespaces are still pinned
+// and that the network namespace is unchanged, which means that it only has
+// the loopback interface and it is up.
+func validatePooledNamespaces(set []Namespace) error {
+	for _, ns := range set {
+		if err := nspkg.IsNSorErr(ns.Path()); err != nil {
+			return fmt.Errorf("%s namespace %s: %w", ns.Type(), ns.Path(), err)
+		}
+		if ns.Type() != NETNS {
+			continue
+		}
+		if err := nspkg.WithNetNSPath(ns.Path(), func(nspkg.NetNS) error {
+			links, err := netlink.LinkList()
+			if err != nil {
+				return err
+			}
+			if len(links) != 1 {
+				return fmt.Errorf("found %d instead of 1 network interfaces", len(links))
+			}
+			attrs := links[0].Attrs()
+			if attrs.Name != "lo" || attrs.Flags&net.FlagUp == 0 {
+				return fmt.Errorf("network interface %s is not the loopback interface or down", attrs.Name)
+			}
+			return nil
+		}); err != nil {
+			return fmt.Errorf("net namespace %s: %w", ns.Path(), err)
+		}
+	}
+	return nil
+}
+
+func removeNamespaces(namespaces []Namespace) {
+	for _, ns := range namespaces {
+		if err := ns.Remove(); err != nil {
+			logrus.Warnf("Failed to remove namespace %s: %v", ns.Path(), err)
+		}
+	}
+}
+
+// removePinnedNamespace unmounts and removes a namespace path, which is not
+// tracked by a Namespace.
+func removePinnedNamespace(nsPath string) {
+	if err := unix.Unmount(nsPath, unix.MNT_DETACH); err != nil && err != unix.EINVAL && err != unix.ENOENT {
+		logrus.Warnf("Failed to unmount %s: %v", nsPath, err)
+	}
+	if err := os.Remove(nsPath); err != nil && !os.IsNotExist(err) {
+		logrus.Warnf("Failed to remove %s: %v", nsPath, err)
+	}
+}
+
+// removePoolDir removes the pool directory including all namespaces pinned
+// in it.
+func removePoolDir(dir string) error {
+	for _, nsType := range pooledNamespaceTypes {
+		nsDir := filepath.Join(dir, string(nsType)+"ns")
+		entries, err := os.ReadDir(nsDir)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return err
+		}
+		for _, entry := range entries {
+			removePinnedNamespace(filepath.Join(nsDir, entry.Name()))
+		}
+	}
+	return os.RemoveAll(dir)
+}
@@ -0,0 +1,132 @@
+//go:build linux
+// +build linux
+
+package nsmgr_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/cri-o/cri-o/internal/config/nsmgr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// The actual test suite
+var _ = t.Describe("NamespacePool", func() {
+	var (
+		sut           *nsmgr.NamespaceManager
+		namespacesDir string
+		poolDir       string
+	)
+
+	BeforeEach(func() {
+		namespacesDir = t.MustTempDir("namespaces")
+		poolDir = filepath.Join(namespacesDir, "pool")
+		// the pool cannot be filled without pinns
+		sut = nsmgr.New(namespacesDir, filepath.Join(namespacesDir, "pinns"))
+	})
+
+	AfterEach(func() {
+		sut.StopPool()
+	})
+
+	t.Describe("StartPool", func() {
+		It("should not start the pool with size 0", func() {
+			// Given
+			// When
+			err := sut.StartPool(0)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(poolDir).NotTo(BeADirectory())
+			_, pooled := sut.TakePooledNamespaces()
+			Expect(pooled).To(BeFalse())
+		})
+
+		It("should create the pool directories", func() {
+			// Given
+			// When
+			err := sut.StartPool(1)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(filepath.Join(poolDir, "ipcns")).To(BeADirectory())
+			Expect(filepath.Join(poolDir, "netns")).To(BeADirectory())
+			Expect(filepath.Join(poolDir, "utsns")).To(BeADirectory())
+		})
+
+		It("should remove the namespaces of a previous instance", func() {
+			// Given
+			stale := filepath.Join(poolDir, "netns", "stale")
+			Expect(os.MkdirAll(filepath.Dir(stale), 0o755)).To(Succeed())
+			Expect(os.WriteFile(stale, nil, 0o644)).To(Succeed())
+
+			// When
+			err := sut.StartPool(1)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(stale).NotTo(BeAnExistingFile())
+		})
+
+		It("should fail if the pool directory cannot be created", func() {
+			// Given
+			Expect(os.WriteFile(poolDir, nil, 0o644)).To(Succeed())
+
+			// When
+			err := sut.StartPool(1)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	t.Describe("TakePooledNamespaces", func() {
+		It("should not take namespaces if the pool is not started", func() {
+			// Given
+			// When
+			namespaces, pooled := sut.TakePooledNamespaces()
+
+			// Then
+			Expect(pooled).To(BeFalse())
+			Expect(namespaces).To(BeEmpty())
+		})
+
+		It("should not take namespaces if the pool is empty", func() {
+			// Given
+			Expect(sut.StartPool(1)).To(Succeed())
+
+			// When
+			namespaces, pooled := sut.TakePooledNamespaces()
+
+			// Then
+			Expect(pooled).To(BeFalse())
+			Expect(namespaces).To(BeEmpty())
+		})
+	})
+
+	t.Describe("StopPool", func() {
+		It("should remove the pool directory", func() {
+			// Given
+			Expect(sut.StartPool(1)).To(Succeed())
+
+			// When
+			sut.StopPool()
+
+			// Then
+			Expect(poolDir).NotTo(BeADirectory())
+			_, pooled := sut.TakePooledNamespaces()
+			Expect(pooled).To(BeFalse())
+		})
+
+		It("should succeed if the pool is not started", func() {
+			// Given
+			// When
+			sut.StopPool()
+
+			// Then
+			Expect(poolDir).NotTo(BeADirectory())
+		})
+	})
+})
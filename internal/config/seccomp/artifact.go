@@ -0,0 +1,27 @@
+package seccomp
+
+import (
+	"context"
+	"strings"
+)
+
+// artifactPrefix is the prefix of seccomp profile references to OCI
+// artifacts, which contain the profile as their single layer.
+const artifactPrefix = "oci://"
+
+// ArtifactPuller returns the content of the OCI artifact ref.
+type ArtifactPuller func(ctx context.Context, ref string) ([]byte, error)
+
+// artifactReference returns the OCI artifact reference of a seccomp profile
+// path and true, if the path uses the oci:// prefix. The kubelet prepends its
+// seccomp root to localhost profiles and cleans the path, which results in a
+// single slash after the scheme.
+func artifactReference(profilePath string) (string, bool) {
+	scheme := strings.TrimSuffix(artifactPrefix, "/")
+	i := strings.Index(profilePath, scheme)
+	if i < 0 || (i > 0 && profilePath[i-1] != '/') {
+		return "", false
+	}
+	ref := strings.TrimLeft(profilePath[i+len(scheme):], "/")
+	return ref, ref != ""
+}
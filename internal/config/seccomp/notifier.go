@@ -22,15 +22,43 @@ import (
 	"github.com/opencontainers/runtime-spec/specs-go"
 	libseccomp "github.com/seccomp/libseccomp-golang"
 	"golang.org/x/sys/unix"
+	"golang.org/x/time/rate"
 )
 
+const (
+	// auditRateLimit is the number of audit notifications per second sent
+	// for a container.
+	auditRateLimit = rate.Limit(10)
+
+	// auditBurst is the maximum burst of audit notifications sent for a
+	// container.
+	auditBurst = 20
+
+	// auditFlushInterval is the interval in which the syscalls exceeding the
+	// rate limit are sent if the container does not issue further ones.
+	auditFlushInterval = time.Second
+)
+
+// recordAlwaysAllowedSyscalls are the syscalls which are not recorded, because
+// the runtime needs them after loading the seccomp profile to pass the
+// notifier file descriptor. They are part of every recorded profile.
+var recordAlwaysAllowedSyscalls = []string{"write"}
+
 // Notifier wraps a seccomp notifier instance for a container.
 type Notifier struct {
-	listener       net.Listener
-	syscalls       sync.Map
-	timer          *time.Timer
-	timeLock       sync.Mutex
-	stopContainers bool
+	listener        net.Listener
+	syscalls        sync.Map
+	architectures   sync.Map
+	timer           *time.Timer
+	timeLock        sync.Mutex
+	stopContainers  bool
+	record          bool
+	audit           bool
+	defaultAction   specs.LinuxSeccompAction
+	defaultErrnoRet *uint
+	// recordRules are the allowing rules of the original profile, which
+	// keep their argument conditions in the recorded profile.
+	recordRules []specs.LinuxSyscall
 }
 
 // StopContainers returns if the notifier should stop containers or not.
@@ -38,6 +66,99 @@ func (n *Notifier) StopContainers() bool {
 	return n.stopContainers
 }
 
+// Audit returns if the notifier lets the blocked syscalls of the container
+// continue and only counts them.
+func (n *Notifier) Audit() bool {
+	return n.audit
+}
+
+// Record returns if the notifier records the syscalls of the container to
+// build a seccomp profile.
+func (n *Notifier) Record() bool {
+	return n.record
+}
+
+// RecordedProfile returns a seccomp profile which allows the recorded syscalls
+// and uses the default action of the original profile for all other syscalls.
+// The recorded syscalls keep the rules of the original profile, including
+// their argument conditions. It returns nil if no syscalls have been recorded.
+func (n *Notifier) RecordedProfile() *specs.LinuxSeccomp {
+	recorded := false
+	n.syscalls.Range(func(_, _ any) bool {
+		recorded = true
+		return false
+	})
+	if !recorded {
+		return nil
+	}
+
+	syscalls := []specs.LinuxSyscall{}
+	covered := map[string]bool{}
+	for i := range n.recordRules {
+		rule := &n.recordRules[i]
+		names := []string{}
+		for _, name := range rule.Names {
+			if _, ok := n.syscalls.Load(name); ok || containsString(recordAlwaysAllowedSyscalls, name) {
+				names = append(names, name)
+				covered[name] = true
+			}
+		}
+		if len(names) == 0 {
+			continue
+		}
+		sort.Strings(names)
+		syscalls = append(syscalls, specs.LinuxSyscall{
+			Names:  names,
+			Action: rule.Action,
+			Args:   rule.Args,
+		})
+	}
+
+	// syscalls which are not part of any rule, like the always allowed ones
+	// missing in the original profile, are allowed unconditionally
+	uncovered := []string{}
+	n.syscalls.Range(func(syscall, _ any) bool {
+		if s, ok := syscall.(string); ok && !covered[s] {
+			uncovered = append(uncovered, s)
+		}
+		return true
+	})
+	for _, name := range recordAlwaysAllowedSyscalls {
+		if _, ok := n.syscalls.Load(name); !ok && !covered[name] {
+			uncovered = append(uncovered, name)
+		}
+	}
+	if len(uncovered) > 0 {
+		sort.Strings(uncovered)
+		syscalls = append(syscalls, specs.LinuxSyscall{
+			Names:  uncovered,
+			Action: specs.ActAllow,
+		})
+	}
+
+	architectures := []specs.Arch{}
+	n.architectures.Range(func(arch, _ any) bool {
+		if a, ok := arch.(specs.Arch); ok {
+			architectures = append(architectures, a)
+		}
+		return true
+	})
+	sort.Slice(architectures, func(i, j int) bool {
+		return architectures[i] < architectures[j]
+	})
+
+	defaultAction := n.defaultAction
+	if defaultAction == "" {
+		defaultAction = specs.ActErrno
+	}
+	return &specs.LinuxSeccomp{
+		DefaultAction:   defaultAction,
+		DefaultErrnoRet: n.defaultErrnoRet,
+		Architectures:   architectures,
+		Syscalls:        syscalls,
+	}
+}
+
 // Close can be used to close the notifier listener.
 func (n *Notifier) Close() error {
 	return n.listener.Close()
@@ -45,14 +166,39 @@ func (n *Notifier) Close() error {
 
 // AddSyscall can be used to add a syscall to the notifier result.
 func (n *Notifier) AddSyscall(syscall string) {
-	initValue := uint64(1)
+	n.AddSyscalls(syscall, 1)
+}
+
+// AddSyscalls can be used to add multiple calls of a syscall to the notifier
+// result.
+func (n *Notifier) AddSyscalls(syscall string, calls uint64) {
+	initValue := calls
 	if count, loaded := n.syscalls.LoadOrStore(syscall, &initValue); loaded {
 		if c, ok := count.(*uint64); ok {
-			atomic.AddUint64(c, 1)
+			atomic.AddUint64(c, calls)
 		}
 	}
 }
 
+// SyscallCounts returns the number of calls per syscall.
+func (n *Notifier) SyscallCounts() map[string]uint64 {
+	res := map[string]uint64{}
+	n.syscalls.Range(func(syscall, count any) bool {
+		s, syscallOk := syscall.(string)
+		c, countOk := count.(*uint64)
+		if syscallOk && countOk {
+			res[s] = atomic.LoadUint64(c)
+		}
+		return true
+	})
+	return res
+}
+
+// addArchitecture adds the architecture of a recorded syscall.
+func (n *Notifier) addArchitecture(arch libseccomp.ScmpArch) {
+	n.architectures.Store(specArch(arch), true)
+}
+
 // UsedSyscalls returns a string representation of the used syscalls, sorted by
 // their name.
 func (n *Notifier) UsedSyscalls() string {
@@ -85,6 +231,7 @@ func (n *Notifier) OnExpired(callback func()) {
 type Notification struct {
 	ctx                  context.Context
 	containerID, syscall string
+	count                uint64
 }
 
 // Ctx returns the context of the notification.
@@ -102,6 +249,11 @@ func (n *Notification) Syscall() string {
 	return n.syscall
 }
 
+// Count returns the number of calls of the syscall for the notification.
+func (n *Notification) Count() uint64 {
+	return n.count
+}
+
 func (c *Config) injectNotifier(
 	ctx context.Context,
 	msgChan chan Notification,
@@ -120,6 +272,20 @@ func (c *Config) injectNotifier(
 
 	log.Infof(ctx, "Injecting seccomp notifier into seccomp profile of container %s", containerID)
 
+	if sandboxAnnotations[annotations.SeccompNotifierActionAnnotation] == annotations.SeccompNotifierActionRecord {
+		recordRules := injectRecorder(ctx, profile)
+		profile.ListenerPath = filepath.Join(c.NotifierPath(), containerID)
+
+		notifier, err := NewNotifier(ctx, msgChan, containerID, profile.ListenerPath, sandboxAnnotations)
+		if err != nil {
+			return nil, fmt.Errorf("unable to run notifier: %w", err)
+		}
+		notifier.defaultAction = profile.DefaultAction
+		notifier.defaultErrnoRet = profile.DefaultErrnoRet
+		notifier.recordRules = recordRules
+		return notifier, nil
+	}
+
 	isActionToOverride := func(action specs.LinuxSeccompAction) bool {
 		if action == specs.ActErrno ||
 			action == specs.ActKill ||
@@ -157,6 +323,75 @@ func (c *Config) injectNotifier(
 	return notifier, nil
 }
 
+// injectRecorder modifies the profile to notify about all allowed syscalls,
+// which makes it possible to record the syscalls used by the container. It
+// returns a copy of the original allowing rules.
+func injectRecorder(ctx context.Context, profile *specs.LinuxSeccomp) []specs.LinuxSyscall {
+	if profile.DefaultAction == specs.ActAllow || profile.DefaultAction == specs.ActLog {
+		log.Infof(
+			ctx,
+			"The seccomp profile default action %s cannot be overridden to %s, "+
+				"which means that syscalls using that default action can't be "+
+				"recorded by the notifier",
+			profile.DefaultAction, seccomp.ActNotify,
+		)
+	}
+
+	rules := []specs.LinuxSyscall{}
+	for i, syscall := range profile.Syscalls {
+		if syscall.Action != specs.ActAllow && syscall.Action != specs.ActLog {
+			continue
+		}
+		rules = append(rules, specs.LinuxSyscall{
+			Names:  append([]string{}, syscall.Names...),
+			Action: syscall.Action,
+			Args:   syscall.Args,
+		})
+		names, allowed := []string{}, []string{}
+		for _, name := range syscall.Names {
+			if containsString(recordAlwaysAllowedSyscalls, name) {
+				allowed = append(allowed, name)
+				continue
+			}
+			names = append(names, name)
+		}
+		if len(names) == 0 {
+			continue
+		}
+		if len(allowed) > 0 {
+			// keep the always allowed syscalls in a separate rule
+			profile.Syscalls = append(profile.Syscalls, specs.LinuxSyscall{
+				Names:  allowed,
+				Action: syscall.Action,
+				Args:   syscall.Args,
+			})
+		}
+		profile.Syscalls[i].Names = names
+		profile.Syscalls[i].Action = specs.ActNotify
+	}
+	return rules
+}
+
+// specArch converts a libseccomp architecture into the OCI representation.
+func specArch(arch libseccomp.ScmpArch) specs.Arch {
+	switch arch {
+	case libseccomp.ArchAMD64:
+		return specs.ArchX86_64
+	case libseccomp.ArchARM64:
+		return specs.ArchAARCH64
+	}
+	return specs.Arch("SCMP_ARCH_" + strings.ToUpper(arch.String()))
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
 // NewNotifier starts the notifier for the provided arguments.
 func NewNotifier(
 	ctx context.Context,
@@ -164,12 +399,27 @@ func NewNotifier(
 	containerID, listenerPath string,
 	annotationMap map[string]string,
 ) (*Notifier, error) {
+	action, ok := annotationMap[annotations.SeccompNotifierActionAnnotation]
+	if !ok {
+		return nil, fmt.Errorf("%s annotation not set on container", annotations.SeccompNotifierActionAnnotation)
+	}
+
 	log.Infof(ctx, "Waiting for seccomp file descriptor on container %s", containerID)
 	listener, err := net.Listen("unix", listenerPath)
 	if err != nil {
 		return nil, fmt.Errorf("listen for seccomp socket: %w", err)
 	}
 
+	notifier := &Notifier{
+		listener:       listener,
+		syscalls:       sync.Map{},
+		timer:          nil,
+		timeLock:       sync.Mutex{},
+		stopContainers: action == annotations.SeccompNotifierActionStop,
+		record:         action == annotations.SeccompNotifierActionRecord,
+		audit:          action == annotations.SeccompNotifierActionAudit,
+	}
+
 	go func() {
 		for {
 			conn, err := listener.Accept()
@@ -204,22 +454,15 @@ func NewNotifier(
 			}
 
 			log.Infof(ctx, "Received new seccomp fd: %v", newFd)
+			if notifier.record || notifier.audit {
+				go continueHandler(ctx, containerID, notifier, msgChan, libseccomp.ScmpFd(newFd))
+				continue
+			}
 			go handler(ctx, containerID, msgChan, libseccomp.ScmpFd(newFd))
 		}
 	}()
 
-	action, ok := annotationMap[annotations.SeccompNotifierActionAnnotation]
-	if !ok {
-		return nil, fmt.Errorf("%s annotation not set on container", annotations.SeccompNotifierActionAnnotation)
-	}
-
-	return &Notifier{
-		listener:       listener,
-		syscalls:       sync.Map{},
-		timer:          nil,
-		timeLock:       sync.Mutex{},
-		stopContainers: action == annotations.SeccompNotifierActionStop,
-	}, nil
+	return notifier, nil
 }
 
 func handler(
@@ -247,7 +490,7 @@ func handler(
 			syscall, containerID, req.Pid,
 		)
 
-		msgChan <- Notification{ctx, containerID, syscall}
+		msgChan <- Notification{ctx, containerID, syscall, 1}
 
 		resp := &libseccomp.ScmpNotifResp{
 			ID:    req.ID,
@@ -272,6 +515,85 @@ func handler(
 	}
 }
 
+// continueHandler lets the notified syscalls of the container continue until
+// it exits. The syscalls are either recorded or sent as rate limited audit
+// notifications, whereas the syscalls exceeding the rate limit are sent at
+// the latest after the flush interval.
+func continueHandler(
+	ctx context.Context,
+	containerID string,
+	notifier *Notifier,
+	msgChan chan Notification,
+	fd libseccomp.ScmpFd,
+) {
+	defer unix.Close(int(fd))
+
+	limiter := rate.NewLimiter(auditRateLimit, auditBurst)
+	pending := map[string]uint64{}
+	flush := func() {
+		for syscall, count := range pending {
+			msgChan <- Notification{ctx, containerID, syscall, count}
+		}
+		pending = map[string]uint64{}
+	}
+	defer flush()
+
+	for {
+		// the notifier file descriptor hangs up once all processes using
+		// the profile exited
+		pollFds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLIN}}
+		ready, err := unix.Poll(pollFds, int(auditFlushInterval.Milliseconds()))
+		if err != nil {
+			if errors.Is(err, unix.EINTR) {
+				continue
+			}
+			log.Errorf(ctx, "Unable to poll seccomp notifier of container %s: %v", containerID, err)
+			return
+		}
+		if ready == 0 {
+			// send the aggregated syscalls once the container calmed down
+			flush()
+			continue
+		}
+		if pollFds[0].Revents&unix.POLLIN == 0 {
+			log.Infof(ctx, "Stopping seccomp notifier handler of container %s", containerID)
+			return
+		}
+
+		req, err := libseccomp.NotifReceive(fd)
+		if err != nil {
+			log.Debugf(ctx, "Unable to receive notification: %v", err)
+			continue
+		}
+
+		syscall, err := req.Data.Syscall.GetNameByArch(req.Data.Arch)
+		if err != nil {
+			log.Errorf(ctx, "Unable to decode syscall %v: %v", req.Data.Syscall, err)
+		} else if notifier.record {
+			notifier.AddSyscall(syscall)
+			notifier.addArchitecture(req.Data.Arch)
+		} else {
+			// syscalls exceeding the rate limit get aggregated into the
+			// next notification
+			pending[syscall]++
+			if limiter.Allow() {
+				flush()
+			}
+		}
+
+		resp := &libseccomp.ScmpNotifResp{
+			ID:    req.ID,
+			Error: 0,
+			Val:   uint64(0),
+			Flags: libseccomp.NotifRespFlagContinue,
+		}
+		if err := libseccomp.NotifRespond(fd, resp); err != nil {
+			// the process may have been killed in the meantime
+			log.Debugf(ctx, "Unable to send notification response: %v", err)
+		}
+	}
+}
+
 func handleNewMessage(sockfd int) (uintptr, error) {
 	const maxNameLen = 16384
 	stateBuf := make([]byte, maxNameLen)
@@ -82,7 +82,10 @@ type Config struct {
 	enabled          bool
 	defaultWhenEmpty bool
 	profile          *seccomp.Seccomp
+	profileRef       string
+	profileLock      sync.Mutex
 	notifierPath     string
+	artifactPuller   ArtifactPuller
 }
 
 // New creates a new default seccomp configuration instance
@@ -118,6 +121,22 @@ func (c *Config) NotifierPath() string {
 	return c.notifierPath
 }
 
+// SetArtifactPuller sets the puller for seccomp profiles referenced as OCI
+// artifacts. Profiles referencing artifacts cannot be pulled before it is set.
+func (c *Config) SetArtifactPuller(puller ArtifactPuller) {
+	c.profileLock.Lock()
+	defer c.profileLock.Unlock()
+	c.artifactPuller = puller
+}
+
+// ArtifactPuller returns the puller for seccomp profiles referenced as OCI
+// artifacts, which may be nil.
+func (c *Config) ArtifactPuller() ArtifactPuller {
+	c.profileLock.Lock()
+	defer c.profileLock.Unlock()
+	return c.artifactPuller
+}
+
 // LoadProfile can be used to load a seccomp profile from the provided path.
 // This method will not fail if seccomp is disabled.
 func (c *Config) LoadProfile(profilePath string) error {
@@ -133,6 +152,26 @@ func (c *Config) LoadProfile(profilePath string) error {
 		return nil
 	}
 
+	if ref, ok := artifactReference(profilePath); ok {
+		var profile *seccomp.Seccomp
+		if c.ArtifactPuller() != nil {
+			var err error
+			profile, err = c.pullProfile(context.Background(), ref)
+			if err != nil {
+				return err
+			}
+			logrus.Infof("Successfully loaded seccomp profile %q", ref)
+		} else {
+			// the image service is not available yet
+			logrus.Infof("Seccomp profile %q will be pulled on first use", ref)
+		}
+		c.profileLock.Lock()
+		defer c.profileLock.Unlock()
+		c.profile = profile
+		c.profileRef = ref
+		return nil
+	}
+
 	profile, err := os.ReadFile(profilePath)
 	if err != nil {
 		return fmt.Errorf("open seccomp profile: %w", err)
@@ -143,7 +182,10 @@ func (c *Config) LoadProfile(profilePath string) error {
 		return fmt.Errorf("decoding seccomp profile failed: %w", err)
 	}
 
+	c.profileLock.Lock()
+	defer c.profileLock.Unlock()
 	c.profile = tmpProfile
+	c.profileRef = ""
 	logrus.Infof("Successfully loaded seccomp profile %q", profilePath)
 	logrus.Tracef("Current seccomp profile content: %s", profile)
 	return nil
@@ -152,7 +194,10 @@ func (c *Config) LoadProfile(profilePath string) error {
 // LoadDefaultProfile sets the internal default profile.
 func (c *Config) LoadDefaultProfile() error {
 	logrus.Info("Using the internal default seccomp profile")
+	c.profileLock.Lock()
 	c.profile = DefaultProfile()
+	c.profileRef = ""
+	c.profileLock.Unlock()
 
 	if logrus.IsLevelEnabled(logrus.TraceLevel) {
 		profileString, err := json.MarshalToString(c.profile)
@@ -173,9 +218,40 @@ func (c *Config) IsDisabled() bool {
 
 // Profile returns the currently loaded seccomp profile
 func (c *Config) Profile() *seccomp.Seccomp {
+	c.profileLock.Lock()
+	defer c.profileLock.Unlock()
 	return c.profile
 }
 
+// runtimeDefaultProfile returns the currently loaded seccomp profile, and
+// pulls it first if it is an OCI artifact which has not been pulled yet.
+func (c *Config) runtimeDefaultProfile(ctx context.Context) (*seccomp.Seccomp, error) {
+	c.profileLock.Lock()
+	profile, ref := c.profile, c.profileRef
+	c.profileLock.Unlock()
+	if profile != nil || ref == "" {
+		return profile, nil
+	}
+
+	// pull without holding the lock to not block other containers
+	profile, err := c.pullProfile(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	c.profileLock.Lock()
+	defer c.profileLock.Unlock()
+	if c.profileRef != ref {
+		// the profile has been reloaded in the meantime
+		return profile, nil
+	}
+	if c.profile == nil {
+		c.profile = profile
+		log.Infof(ctx, "Successfully loaded seccomp profile %q", ref)
+	}
+	return c.profile, nil
+}
+
 // Setup can be used to setup the seccomp profile.
 func (c *Config) Setup(
 	ctx context.Context,
@@ -254,8 +330,12 @@ func (c *Config) setupFromPath(
 	// Load the default seccomp profile from the server if the profilePath is a
 	// default one
 	if profilePath == k8sV1.SeccompProfileRuntimeDefault || profilePath == k8sV1.DeprecatedSeccompProfileDockerDefault {
+		profile, err := c.runtimeDefaultProfile(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("load default profile: %w", err)
+		}
 		linuxSpecs, err := seccomp.LoadProfileFromConfig(
-			c.Profile(), specGenerator.Config,
+			profile, specGenerator.Config,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("load default profile: %w", err)
@@ -275,7 +355,7 @@ func (c *Config) setupFromPath(
 	}
 
 	fname := strings.TrimPrefix(profilePath, k8sV1.SeccompLocalhostProfileNamePrefix)
-	file, err := os.ReadFile(filepath.FromSlash(fname))
+	file, err := c.readLocalhostProfile(ctx, fname)
 	if err != nil {
 		return nil, fmt.Errorf("cannot load seccomp profile %q: %w", fname, err)
 	}
@@ -327,8 +407,12 @@ func (c *Config) setupFromField(
 	}
 
 	if profileField.ProfileType == types.SecurityProfile_RuntimeDefault {
+		profile, err := c.runtimeDefaultProfile(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("load default profile: %w", err)
+		}
 		linuxSpecs, err := seccomp.LoadProfileFromConfig(
-			c.Profile(), specGenerator.Config,
+			profile, specGenerator.Config,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("load default profile: %w", err)
@@ -342,7 +426,7 @@ func (c *Config) setupFromField(
 	}
 
 	// Load local seccomp profiles including their availability validation
-	file, err := os.ReadFile(filepath.FromSlash(profileField.LocalhostRef))
+	file, err := c.readLocalhostProfile(ctx, profileField.LocalhostRef)
 	if err != nil {
 		return nil, fmt.Errorf(
 			"unable to load local profile %q: %w", profileField.LocalhostRef, err,
@@ -360,3 +444,35 @@ func (c *Config) setupFromField(
 	specGenerator.Config.Linux.Seccomp = linuxSpecs
 	return notifier, nil
 }
+
+// readLocalhostProfile reads a localhost profile from a file or pulls it, if
+// it references an OCI artifact.
+func (c *Config) readLocalhostProfile(ctx context.Context, profilePath string) ([]byte, error) {
+	if ref, ok := artifactReference(profilePath); ok {
+		return c.pullArtifact(ctx, ref)
+	}
+	return os.ReadFile(filepath.FromSlash(profilePath))
+}
+
+// pullArtifact pulls the content of the OCI artifact ref by using the
+// artifact puller of the configuration.
+func (c *Config) pullArtifact(ctx context.Context, ref string) ([]byte, error) {
+	puller := c.ArtifactPuller()
+	if puller == nil {
+		return nil, errors.New("pulling seccomp profiles from OCI artifacts is not available")
+	}
+	return puller(ctx, ref)
+}
+
+// pullProfile pulls and decodes a seccomp profile from an OCI artifact.
+func (c *Config) pullProfile(ctx context.Context, ref string) (*seccomp.Seccomp, error) {
+	content, err := c.pullArtifact(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("pull seccomp profile %s: %w", ref, err)
+	}
+	profile := &seccomp.Seccomp{}
+	if err := json.Unmarshal(content, profile); err != nil {
+		return nil, fmt.Errorf("decoding seccomp profile %s failed: %w", ref, err)
+	}
+	return profile, nil
+}
@@ -2,16 +2,24 @@ package seccomp_test
 
 import (
 	"context"
+	"errors"
 	"os"
 
 	"github.com/cri-o/cri-o/internal/config/seccomp"
+	"github.com/cri-o/cri-o/pkg/annotations"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/opencontainers/runtime-tools/generate"
 	k8sV1 "k8s.io/api/core/v1"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
 
+const artifactProfile = `{
+	"defaultAction": "SCMP_ACT_ERRNO",
+	"syscalls": [{"names": ["read", "write", "exit_group"], "action": "SCMP_ACT_ALLOW"}]
+}`
+
 // The actual test suite
 var _ = t.Describe("Config", func() {
 	var sut *seccomp.Config
@@ -78,6 +86,87 @@ var _ = t.Describe("Config", func() {
 			Expect(err).To(BeNil())
 		})
 
+		It("should pull a profile from an OCI artifact on first use", func() {
+			// Given
+			if sut.IsDisabled() {
+				Skip("seccomp is not enabled")
+			}
+			generator, err := generate.New("linux")
+			Expect(err).To(BeNil())
+			pulled := []string{}
+
+			// When
+			err = sut.LoadProfile("oci://quay.io/crio/profile:latest")
+			Expect(err).To(BeNil())
+			Expect(sut.Profile()).To(BeNil())
+			sut.SetArtifactPuller(func(_ context.Context, ref string) ([]byte, error) {
+				pulled = append(pulled, ref)
+				return []byte(artifactProfile), nil
+			})
+			_, err = sut.Setup(
+				context.Background(),
+				nil,
+				"",
+				nil,
+				&generator,
+				&types.SecurityProfile{ProfileType: types.SecurityProfile_RuntimeDefault},
+				"",
+			)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(pulled).To(Equal([]string{"quay.io/crio/profile:latest"}))
+			Expect(sut.Profile()).NotTo(BeNil())
+			Expect(generator.Config.Linux.Seccomp.DefaultAction).To(Equal(specs.ActErrno))
+		})
+
+		It("should not hold the profile lock while pulling a profile from an OCI artifact", func() {
+			// Given
+			if sut.IsDisabled() {
+				Skip("seccomp is not enabled")
+			}
+			generator, err := generate.New("linux")
+			Expect(err).To(BeNil())
+			Expect(sut.LoadProfile("oci://quay.io/crio/profile:latest")).To(BeNil())
+			sut.SetArtifactPuller(func(context.Context, string) ([]byte, error) {
+				// would deadlock if the lock is held during the pull
+				Expect(sut.Profile()).To(BeNil())
+				return []byte(artifactProfile), nil
+			})
+
+			// When
+			_, err = sut.Setup(
+				context.Background(),
+				nil,
+				"",
+				nil,
+				&generator,
+				&types.SecurityProfile{ProfileType: types.SecurityProfile_RuntimeDefault},
+				"",
+			)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(sut.Profile()).NotTo(BeNil())
+		})
+
+		It("should fail to load a profile from an OCI artifact if the pull fails", func() {
+			// Given
+			if sut.IsDisabled() {
+				Skip("seccomp is not enabled")
+			}
+			sut.SetArtifactPuller(func(context.Context, string) ([]byte, error) {
+				return nil, errors.New("pull failed")
+			})
+
+			// When
+			err := sut.LoadProfile("oci://quay.io/crio/profile:latest")
+
+			// Then
+			Expect(err).NotTo(BeNil())
+			Expect(sut.Profile()).To(Equal(seccomp.DefaultProfile()))
+		})
+
 		if sut != nil && !sut.IsDisabled() {
 			It("should not fail with non-existing profile", func() {
 				// Given
@@ -200,6 +289,104 @@ var _ = t.Describe("Config", func() {
 			Expect(err).To(BeNil())
 		})
 
+		It("should record the allowed syscalls with the record notifier action", func() {
+			// Given
+			if sut.IsDisabled() {
+				Skip("seccomp is not enabled")
+			}
+			generator, err := generate.New("linux")
+			Expect(err).To(BeNil())
+			sut.SetNotifierPath(t.MustTempDir("seccomp"))
+			field := &types.SecurityProfile{
+				ProfileType: types.SecurityProfile_RuntimeDefault,
+			}
+
+			// When
+			notifier, err := sut.Setup(
+				context.Background(),
+				make(chan seccomp.Notification),
+				"id",
+				map[string]string{
+					annotations.SeccompNotifierActionAnnotation: annotations.SeccompNotifierActionRecord,
+				},
+				&generator,
+				field,
+				"",
+			)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(notifier).NotTo(BeNil())
+			defer notifier.Close()
+			Expect(notifier.Record()).To(BeTrue())
+			Expect(notifier.StopContainers()).To(BeFalse())
+			Expect(notifier.RecordedProfile()).To(BeNil())
+			for _, syscall := range generator.Config.Linux.Seccomp.Syscalls {
+				if syscall.Action == specs.ActAllow {
+					Expect(syscall.Names).To(Equal([]string{"write"}))
+				}
+			}
+
+			// When
+			notifier.AddSyscall("read")
+			notifier.AddSyscall("personality")
+			profile := notifier.RecordedProfile()
+
+			// Then
+			Expect(profile).NotTo(BeNil())
+			unconditional, conditional := []string{}, 0
+			for _, syscall := range profile.Syscalls {
+				Expect(syscall.Action).To(Equal(specs.ActAllow))
+				if len(syscall.Args) == 0 {
+					unconditional = append(unconditional, syscall.Names...)
+					continue
+				}
+				// the argument conditions of the default profile are kept
+				Expect(syscall.Names).To(Equal([]string{"personality"}))
+				conditional++
+			}
+			Expect(unconditional).To(ContainElements("read", "write"))
+			Expect(unconditional).NotTo(ContainElement("personality"))
+			Expect(conditional).To(BeNumerically(">", 0))
+		})
+
+		It("should succeed with localhost profile from OCI artifact", func() {
+			// Given
+			if sut.IsDisabled() {
+				Skip("seccomp is not enabled")
+			}
+			generator, err := generate.New("linux")
+			Expect(err).To(BeNil())
+			pulled := []string{}
+			sut.SetArtifactPuller(func(_ context.Context, ref string) ([]byte, error) {
+				pulled = append(pulled, ref)
+				return []byte(artifactProfile), nil
+			})
+			field := &types.SecurityProfile{
+				ProfileType: types.SecurityProfile_Localhost,
+				// the kubelet prepends its seccomp root and cleans the path
+				LocalhostRef: "/var/lib/kubelet/seccomp/oci:/quay.io/crio/profile@sha256:" +
+					"0000000000000000000000000000000000000000000000000000000000000000",
+			}
+
+			// When
+			_, err = sut.Setup(
+				context.Background(),
+				nil,
+				"",
+				nil,
+				&generator,
+				field,
+				"",
+			)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(pulled).To(Equal([]string{"quay.io/crio/profile@sha256:" +
+				"0000000000000000000000000000000000000000000000000000000000000000"}))
+			Expect(generator.Config.Linux.Seccomp.DefaultAction).To(Equal(specs.ActErrno))
+		})
+
 		It("should fail with custom profile from field if not existing", func() {
 			// Given
 			generator, err := generate.New("linux")
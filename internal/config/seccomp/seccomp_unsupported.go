@@ -6,6 +6,7 @@ package seccomp
 import (
 	"context"
 
+	"github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/opencontainers/runtime-tools/generate"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
@@ -63,6 +64,17 @@ func (c *Config) NotifierPath() string {
 	return ""
 }
 
+// SetArtifactPuller sets the puller for seccomp profiles referenced as OCI
+// artifacts.
+func (c *Config) SetArtifactPuller(puller ArtifactPuller) {
+}
+
+// ArtifactPuller returns the puller for seccomp profiles referenced as OCI
+// artifacts, which may be nil.
+func (c *Config) ArtifactPuller() ArtifactPuller {
+	return nil
+}
+
 // LoadProfile can be used to load a seccomp profile from the provided path.
 // This method will not fail if seccomp is disabled.
 func (c *Config) LoadProfile(profilePath string) error {
@@ -92,6 +104,13 @@ func (*Notifier) Close() error {
 func (*Notifier) AddSyscall(syscall string) {
 }
 
+func (*Notifier) AddSyscalls(syscall string, calls uint64) {
+}
+
+func (*Notifier) SyscallCounts() map[string]uint64 {
+	return nil
+}
+
 func (*Notifier) UsedSyscalls() string {
 	return ""
 }
@@ -100,6 +119,18 @@ func (*Notifier) StopContainers() bool {
 	return false
 }
 
+func (*Notifier) Audit() bool {
+	return false
+}
+
+func (*Notifier) Record() bool {
+	return false
+}
+
+func (*Notifier) RecordedProfile() *specs.LinuxSeccomp {
+	return nil
+}
+
 func (*Notifier) OnExpired(callback func()) {
 }
 
@@ -114,3 +145,7 @@ func (*Notification) ContainerID() string {
 func (*Notification) Syscall() string {
 	return ""
 }
+
+func (*Notification) Count() uint64 {
+	return 0
+}
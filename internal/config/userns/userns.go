@@ -0,0 +1,251 @@
+package userns
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/containers/storage/pkg/idtools"
+	json "github.com/json-iterator/go"
+	"github.com/sirupsen/logrus"
+)
+
+// RangeSize is the number of UIDs and GIDs allocated for a pod.
+const RangeSize = 65536
+
+// ErrExhausted is returned if no ID range is available anymore.
+var ErrExhausted = errors.New("no user namespace ID range available")
+
+// Allocation is a range of host UIDs and GIDs allocated for a pod.
+type Allocation struct {
+	// HostUID is the first host UID of the range.
+	HostUID int `json:"hostUID"`
+	// HostGID is the first host GID of the range.
+	HostGID int `json:"hostGID"`
+}
+
+// UIDMap returns the UID mapping of the allocation.
+func (a *Allocation) UIDMap() []idtools.IDMap {
+	return []idtools.IDMap{{ContainerID: 0, HostID: a.HostUID, Size: RangeSize}}
+}
+
+// GIDMap returns the GID mapping of the allocation.
+func (a *Allocation) GIDMap() []idtools.IDMap {
+	return []idtools.IDMap{{ContainerID: 0, HostID: a.HostGID, Size: RangeSize}}
+}
+
+// Allocator hands out non-overlapping ID ranges of RangeSize to pods, and
+// persists the allocations in a file.
+type Allocator struct {
+	path        string
+	uidBlocks   []int
+	gidBlocks   []int
+	allocations map[string]*Allocation
+	// used are the indexes of the allocated blocks.
+	used  map[int]string
+	mutex sync.Mutex
+}
+
+// New creates a new allocator handing out ranges of the provided host UIDs and
+// GIDs, which get split into blocks of RangeSize. The allocations are
+// persisted in the file at path. Allocations of a previous instance which do
+// not match the current ranges are dropped.
+func New(path string, uids, gids []idtools.IDMap) (*Allocator, error) {
+	a := &Allocator{
+		path:        path,
+		uidBlocks:   blocks(uids),
+		gidBlocks:   blocks(gids),
+		allocations: make(map[string]*Allocation),
+		used:        make(map[int]string),
+	}
+	if a.Total() == 0 {
+		return nil, fmt.Errorf("no user namespace ID range of size %d available", RangeSize)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("read user namespace allocations: %w", err)
+	}
+	if err == nil {
+		allocations := make(map[string]*Allocation)
+		if err := json.Unmarshal(content, &allocations); err != nil {
+			return nil, fmt.Errorf("decode user namespace allocations: %w", err)
+		}
+		for id, allocation := range allocations {
+			block := a.blockOf(allocation)
+			if block < 0 {
+				logrus.Warnf("Dropping user namespace allocation of pod %s outside of the configured ranges", id)
+				continue
+			}
+			if other, ok := a.used[block]; ok {
+				logrus.Warnf("Dropping user namespace allocation of pod %s overlapping with pod %s", id, other)
+				continue
+			}
+			a.allocations[id] = allocation
+			a.used[block] = id
+		}
+	}
+	logrus.Infof("Allocated %d of %d user namespace ID ranges", len(a.allocations), a.Total())
+	return a, nil
+}
+
+// Allocate allocates an ID range for the pod with the provided ID. It returns
+// the existing allocation if the pod has already one.
+func (a *Allocator) Allocate(id string) (*Allocation, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if allocation, ok := a.allocations[id]; ok {
+		return allocation, nil
+	}
+	for block := 0; block < a.Total(); block++ {
+		if _, ok := a.used[block]; ok {
+			continue
+		}
+		allocation := &Allocation{HostUID: a.uidBlocks[block], HostGID: a.gidBlocks[block]}
+		a.allocations[id] = allocation
+		a.used[block] = id
+		if err := a.save(); err != nil {
+			delete(a.allocations, id)
+			delete(a.used, block)
+			return nil, err
+		}
+		return allocation, nil
+	}
+	return nil, ErrExhausted
+}
+
+// Release frees the ID range of the pod with the provided ID. It is a no-op
+// if the pod has no allocation.
+func (a *Allocator) Release(id string) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	allocation, ok := a.allocations[id]
+	if !ok {
+		return nil
+	}
+	delete(a.allocations, id)
+	delete(a.used, a.blockOf(allocation))
+	return a.save()
+}
+
+// Prune frees the ID ranges of all pods for which exists returns false.
+func (a *Allocator) Prune(exists func(id string) bool) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	pruned := false
+	for id, allocation := range a.allocations {
+		if exists(id) {
+			continue
+		}
+		logrus.Infof("Releasing user namespace allocation of removed pod %s", id)
+		delete(a.allocations, id)
+		delete(a.used, a.blockOf(allocation))
+		pruned = true
+	}
+	if !pruned {
+		return nil
+	}
+	return a.save()
+}
+
+// Allocated returns the number of allocated ID ranges.
+func (a *Allocator) Allocated() int {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return len(a.allocations)
+}
+
+// Total returns the number of ID ranges which can be allocated.
+func (a *Allocator) Total() int {
+	if len(a.uidBlocks) < len(a.gidBlocks) {
+		return len(a.uidBlocks)
+	}
+	return len(a.gidBlocks)
+}
+
+// blockOf returns the index of the block of the allocation, or -1 if it does
+// not match any block.
+func (a *Allocator) blockOf(allocation *Allocation) int {
+	for block := 0; block < a.Total(); block++ {
+		if a.uidBlocks[block] == allocation.HostUID && a.gidBlocks[block] == allocation.HostGID {
+			return block
+		}
+	}
+	return -1
+}
+
+func (a *Allocator) save() error {
+	content, err := json.Marshal(a.allocations)
+	if err != nil {
+		return fmt.Errorf("encode user namespace allocations: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(a.path), 0o700); err != nil {
+		return err
+	}
+	tmpPath := a.path + ".tmp"
+	if err := os.WriteFile(tmpPath, content, 0o600); err != nil {
+		return fmt.Errorf("write user namespace allocations: %w", err)
+	}
+	return os.Rename(tmpPath, a.path)
+}
+
+// blocks splits the ID ranges into blocks of RangeSize and returns their
+// first host IDs in ascending order. Blocks overlapping with a previous one
+// are skipped.
+func blocks(ranges []idtools.IDMap) []int {
+	starts := []int{}
+	for _, r := range ranges {
+		for start := r.HostID; start+RangeSize <= r.HostID+r.Size; start += RangeSize {
+			starts = append(starts, start)
+		}
+	}
+	sort.Ints(starts)
+
+	result := []int{}
+	for _, start := range starts {
+		if len(result) > 0 && start < result[len(result)-1]+RangeSize {
+			continue
+		}
+		result = append(result, start)
+	}
+	return result
+}
+
+// ParseRanges parses host ID ranges in the form "HOSTID:SIZE".
+func ParseRanges(ranges []string) ([]idtools.IDMap, error) {
+	result := make([]idtools.IDMap, 0, len(ranges))
+	for _, r := range ranges {
+		hostID, size, ok := strings.Cut(r, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid ID range %q, expected HOSTID:SIZE", r)
+		}
+		start, err := strconv.ParseUint(hostID, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid host ID of range %q: %w", r, err)
+		}
+		length, err := strconv.ParseUint(size, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid size of range %q: %w", r, err)
+		}
+		result = append(result, idtools.IDMap{HostID: int(start), Size: int(length)})
+	}
+	return result, nil
+}
+
+// SubordinateRanges returns the subordinate UID and GID ranges of the user
+// from /etc/subuid and /etc/subgid.
+func SubordinateRanges(user string) (uids, gids []idtools.IDMap, err error) {
+	mappings, err := idtools.NewIDMappings(user, user)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read subordinate ID ranges of user %s: %w", user, err)
+	}
+	return mappings.UIDs(), mappings.GIDs(), nil
+}
@@ -0,0 +1,181 @@
+package userns_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/containers/storage/pkg/idtools"
+	"github.com/cri-o/cri-o/internal/config/userns"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = t.Describe("Allocator", func() {
+	var (
+		path   string
+		ranges []idtools.IDMap
+	)
+
+	BeforeEach(func() {
+		path = filepath.Join(t.MustTempDir("userns"), "allocations.json")
+		ranges = []idtools.IDMap{{HostID: 100000, Size: 2 * userns.RangeSize}}
+	})
+
+	It("should fail without a range of sufficient size", func() {
+		// Given
+		ranges = []idtools.IDMap{{HostID: 100000, Size: userns.RangeSize - 1}}
+
+		// When
+		sut, err := userns.New(path, ranges, ranges)
+
+		// Then
+		Expect(err).To(HaveOccurred())
+		Expect(sut).To(BeNil())
+	})
+
+	It("should allocate non-overlapping ranges", func() {
+		// Given
+		sut, err := userns.New(path, ranges, ranges)
+		Expect(err).ToNot(HaveOccurred())
+
+		// When
+		first, err := sut.Allocate("first")
+		Expect(err).ToNot(HaveOccurred())
+		second, err := sut.Allocate("second")
+		Expect(err).ToNot(HaveOccurred())
+
+		// Then
+		Expect(first.HostUID).To(Equal(100000))
+		Expect(second.HostUID).To(Equal(100000 + userns.RangeSize))
+		Expect(second.UIDMap()).To(Equal([]idtools.IDMap{
+			{ContainerID: 0, HostID: 100000 + userns.RangeSize, Size: userns.RangeSize},
+		}))
+		Expect(sut.Allocated()).To(Equal(2))
+	})
+
+	It("should return the existing allocation of a pod", func() {
+		// Given
+		sut, err := userns.New(path, ranges, ranges)
+		Expect(err).ToNot(HaveOccurred())
+		first, err := sut.Allocate("pod")
+		Expect(err).ToNot(HaveOccurred())
+
+		// When
+		second, err := sut.Allocate("pod")
+
+		// Then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(second).To(Equal(first))
+		Expect(sut.Allocated()).To(Equal(1))
+	})
+
+	It("should fail if exhausted", func() {
+		// Given
+		sut, err := userns.New(path, ranges, ranges)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = sut.Allocate("first")
+		Expect(err).ToNot(HaveOccurred())
+		_, err = sut.Allocate("second")
+		Expect(err).ToNot(HaveOccurred())
+
+		// When
+		_, err = sut.Allocate("third")
+
+		// Then
+		Expect(err).To(MatchError(userns.ErrExhausted))
+	})
+
+	It("should reuse released ranges", func() {
+		// Given
+		sut, err := userns.New(path, ranges, ranges)
+		Expect(err).ToNot(HaveOccurred())
+		first, err := sut.Allocate("first")
+		Expect(err).ToNot(HaveOccurred())
+		_, err = sut.Allocate("second")
+		Expect(err).ToNot(HaveOccurred())
+
+		// When
+		Expect(sut.Release("first")).To(Succeed())
+		third, err := sut.Allocate("third")
+
+		// Then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(third).To(Equal(first))
+	})
+
+	It("should persist allocations", func() {
+		// Given
+		sut, err := userns.New(path, ranges, ranges)
+		Expect(err).ToNot(HaveOccurred())
+		first, err := sut.Allocate("first")
+		Expect(err).ToNot(HaveOccurred())
+
+		// When
+		restored, err := userns.New(path, ranges, ranges)
+		Expect(err).ToNot(HaveOccurred())
+		second, err := restored.Allocate("second")
+
+		// Then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(restored.Allocated()).To(Equal(2))
+		Expect(second.HostUID).NotTo(Equal(first.HostUID))
+	})
+
+	It("should drop persisted allocations outside of the ranges", func() {
+		// Given
+		Expect(os.WriteFile(path, []byte(`{"pod":{"hostUID":1,"hostGID":1}}`), 0o600)).To(Succeed())
+
+		// When
+		sut, err := userns.New(path, ranges, ranges)
+
+		// Then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sut.Allocated()).To(BeZero())
+	})
+
+	It("should prune allocations of removed pods", func() {
+		// Given
+		sut, err := userns.New(path, ranges, ranges)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = sut.Allocate("removed")
+		Expect(err).ToNot(HaveOccurred())
+		_, err = sut.Allocate("running")
+		Expect(err).ToNot(HaveOccurred())
+
+		// When
+		err = sut.Prune(func(id string) bool { return id == "running" })
+
+		// Then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sut.Allocated()).To(Equal(1))
+		restored, err := userns.New(path, ranges, ranges)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(restored.Allocated()).To(Equal(1))
+	})
+})
+
+var _ = t.Describe("ParseRanges", func() {
+	It("should succeed with valid ranges", func() {
+		// Given
+		// When
+		res, err := userns.ParseRanges([]string{"100000:65536", "200000:131072"})
+
+		// Then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal([]idtools.IDMap{
+			{HostID: 100000, Size: 65536},
+			{HostID: 200000, Size: 131072},
+		}))
+	})
+
+	It("should fail with invalid ranges", func() {
+		for _, r := range []string{"100000", "a:65536", "100000:b", "-1:65536"} {
+			// Given
+			// When
+			_, err := userns.ParseRanges([]string{r})
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		}
+	})
+})
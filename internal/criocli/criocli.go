@@ -167,15 +167,24 @@ func mergeConfig(config *libconfig.Config, ctx *cli.Context) error {
 	if ctx.IsSet("selinux") {
 		config.SELinux = ctx.Bool("selinux")
 	}
+	if ctx.IsSet("selinux-mcs-allocation-file") {
+		config.SELinuxMCSAllocationFile = ctx.String("selinux-mcs-allocation-file")
+	}
 	if ctx.IsSet("seccomp-profile") {
 		config.SeccompProfile = ctx.String("seccomp-profile")
 	}
 	if ctx.IsSet("seccomp-use-default-when-empty") {
 		config.SeccompUseDefaultWhenEmpty = ctx.Bool("seccomp-use-default-when-empty")
 	}
+	if ctx.IsSet("seccomp-record-dir") {
+		config.SeccompRecordDir = ctx.String("seccomp-record-dir")
+	}
 	if ctx.IsSet("apparmor-profile") {
 		config.ApparmorProfile = ctx.String("apparmor-profile")
 	}
+	if ctx.IsSet("apparmor-profiles-dir") {
+		config.ApparmorProfilesDir = ctx.String("apparmor-profiles-dir")
+	}
 	if ctx.IsSet("blockio-config-file") {
 		config.BlockIOConfigFile = ctx.String("blockio-config-file")
 	}
@@ -203,6 +212,9 @@ func mergeConfig(config *libconfig.Config, ctx *cli.Context) error {
 	if ctx.IsSet("add-inheritable-capabilities") {
 		config.AddInheritableCapabilities = ctx.Bool("add-inheritable-capabilities")
 	}
+	if ctx.IsSet("capability-policy") {
+		config.CapabilityPolicy = ctx.String("capability-policy")
+	}
 	if ctx.IsSet("default-sysctls") {
 		config.DefaultSysctls = StringSliceTrySplit(ctx, "default-sysctls")
 	}
@@ -215,6 +227,9 @@ func mergeConfig(config *libconfig.Config, ctx *cli.Context) error {
 	if ctx.IsSet("log-size-max") {
 		config.LogSizeMax = ctx.Int64("log-size-max")
 	}
+	if ctx.IsSet("exec-sync-output-size-max") {
+		config.ExecSyncOutputSizeMax = ctx.Int64("exec-sync-output-size-max")
+	}
 	if ctx.IsSet("log-journald") {
 		config.LogToJournald = ctx.Bool("log-journald")
 	}
@@ -227,6 +242,21 @@ func mergeConfig(config *libconfig.Config, ctx *cli.Context) error {
 	if ctx.IsSet("cni-plugin-dir") {
 		config.PluginDirs = StringSliceTrySplit(ctx, "cni-plugin-dir")
 	}
+	if ctx.IsSet("hostport-backend") {
+		config.HostPortBackend = ctx.String("hostport-backend")
+	}
+	if ctx.IsSet("enable-bandwidth-fallback") {
+		config.EnableBandwidthFallback = ctx.Bool("enable-bandwidth-fallback")
+	}
+	if ctx.IsSet("hostport-reconcile-interval") {
+		config.HostPortReconcileInterval = ctx.String("hostport-reconcile-interval")
+	}
+	if ctx.IsSet("dns-options") {
+		config.DNSOptions = StringSliceTrySplit(ctx, "dns-options")
+	}
+	if ctx.IsSet("dns-upstream-resolv-conf") {
+		config.DNSUpstreamResolvConf = ctx.String("dns-upstream-resolv-conf")
+	}
 	if ctx.IsSet("image-volumes") {
 		config.ImageVolumes = libconfig.ImageVolumesType(ctx.String("image-volumes"))
 	}
@@ -248,6 +278,15 @@ func mergeConfig(config *libconfig.Config, ctx *cli.Context) error {
 	if ctx.IsSet("minimum-mappable-gid") {
 		config.MinimumMappableGID = ctx.Int64("minimum-mappable-gid")
 	}
+	if ctx.IsSet("userns-allocation-user") {
+		config.UsernsAllocationUser = ctx.String("userns-allocation-user")
+	}
+	if ctx.IsSet("userns-allocation-ranges") {
+		config.UsernsAllocationRanges = StringSliceTrySplit(ctx, "userns-allocation-ranges")
+	}
+	if ctx.IsSet("userns-allocation-file") {
+		config.UsernsAllocationFile = ctx.String("userns-allocation-file")
+	}
 	if ctx.IsSet("log-level") {
 		config.LogLevel = ctx.String("log-level")
 	}
@@ -299,6 +338,9 @@ func mergeConfig(config *libconfig.Config, ctx *cli.Context) error {
 	if ctx.IsSet("namespaces-dir") {
 		config.NamespacesDir = ctx.String("namespaces-dir")
 	}
+	if ctx.IsSet("namespace-pool-size") {
+		config.NamespacePoolSize = ctx.Int("namespace-pool-size")
+	}
 	if ctx.IsSet("pinns-path") {
 		config.PinnsPath = ctx.String("pinns-path")
 	}
@@ -401,6 +443,24 @@ func mergeConfig(config *libconfig.Config, ctx *cli.Context) error {
 	if ctx.IsSet("enable-pod-events") {
 		config.EnablePodEvents = ctx.Bool("enable-pod-events")
 	}
+	if ctx.IsSet("audit-log-path") {
+		config.AuditLogPath = ctx.String("audit-log-path")
+	}
+	if ctx.IsSet("audit-log-to-journald") {
+		config.AuditLogToJournald = ctx.Bool("audit-log-to-journald")
+	}
+	if ctx.IsSet("audit-log-max-size") {
+		config.AuditLogMaxSize = ctx.Int("audit-log-max-size")
+	}
+	if ctx.IsSet("audit-log-max-backups") {
+		config.AuditLogMaxBackups = ctx.Int("audit-log-max-backups")
+	}
+	if ctx.IsSet("audit-log-max-age") {
+		config.AuditLogMaxAge = ctx.Int("audit-log-max-age")
+	}
+	if ctx.IsSet("audit-redact-patterns") {
+		config.AuditRedactPatterns = ctx.StringSlice("audit-redact-patterns")
+	}
 	return nil
 }
 
@@ -615,7 +675,7 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 		},
 		&cli.StringFlag{
 			Name:      "seccomp-profile",
-			Usage:     "Path to the seccomp.json profile to be used as the runtime's default. If not specified, then the internal default seccomp profile will be used.",
+			Usage:     "Path to the seccomp.json profile to be used as the runtime's default. If not specified, then the internal default seccomp profile will be used. OCI artifacts containing a profile can be referenced as oci://REGISTRY/REPOSITORY:TAG.",
 			EnvVars:   []string{"CONTAINER_SECCOMP_PROFILE"},
 			TakesFile: true,
 		},
@@ -625,12 +685,26 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 			EnvVars: []string{"CONTAINER_SECCOMP_USE_DEFAULT_WHEN_EMPTY"},
 			Value:   defConf.Seccomp().UseDefaultWhenEmpty(),
 		},
+		&cli.StringFlag{
+			Name:      "seccomp-record-dir",
+			Usage:     "Directory where the seccomp profiles recorded by the seccomp notifier get written to.",
+			Value:     defConf.SeccompRecordDir,
+			EnvVars:   []string{"CONTAINER_SECCOMP_RECORD_DIR"},
+			TakesFile: true,
+		},
 		&cli.StringFlag{
 			Name:    "apparmor-profile",
 			Usage:   "Name of the apparmor profile to be used as the runtime's default. This only takes effect if the user does not specify a profile via the Kubernetes Pod's metadata annotation.",
 			Value:   defConf.ApparmorProfile,
 			EnvVars: []string{"CONTAINER_APPARMOR_PROFILE"},
 		},
+		&cli.StringFlag{
+			Name:      "apparmor-profiles-dir",
+			Usage:     "Directory of AppArmor profile files, which get loaded on startup and configuration reload. Containers can use the profiles via their localhost/ profile name.",
+			Value:     defConf.ApparmorProfilesDir,
+			EnvVars:   []string{"CONTAINER_APPARMOR_PROFILES_DIR"},
+			TakesFile: true,
+		},
 		&cli.StringFlag{
 			Name:  "blockio-config-file",
 			Usage: "Path to the blockio class configuration file for configuring the cgroup blockio controller.",
@@ -652,6 +726,13 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 			EnvVars: []string{"CONTAINER_SELINUX"},
 			Value:   defConf.SELinux,
 		},
+		&cli.StringFlag{
+			Name:      "selinux-mcs-allocation-file",
+			Usage:     "File where the SELinux MCS levels allocated to pods are persisted. The levels are allocated without persistence if empty.",
+			Value:     defConf.SELinuxMCSAllocationFile,
+			EnvVars:   []string{"CONTAINER_SELINUX_MCS_ALLOCATION_FILE"},
+			TakesFile: true,
+		},
 		&cli.StringFlag{
 			Name:    "cgroup-manager",
 			Usage:   "cgroup manager (cgroupfs or systemd).",
@@ -670,6 +751,12 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 			Usage:   "Maximum log size in bytes for a container. If it is positive, it must be >= 8192 to match/exceed conmon read buffer. This option is deprecated. The Kubelet flag '--container-log-max-size' should be used instead.",
 			EnvVars: []string{"CONTAINER_LOG_SIZE_MAX"},
 		},
+		&cli.Int64Flag{
+			Name:    "exec-sync-output-size-max",
+			Value:   libconfig.DefaultExecSyncOutputSizeMax,
+			Usage:   "Maximum size in bytes of the output buffered for a single exec sync request. Exceeding output is truncated.",
+			EnvVars: []string{"CONTAINER_EXEC_SYNC_OUTPUT_SIZE_MAX"},
+		},
 		&cli.BoolFlag{
 			Name:    "log-journald",
 			Usage:   "Log to systemd journal (journald) in addition to kubernetes log file.",
@@ -695,6 +782,37 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 			Usage:   "CNI plugin binaries directory.",
 			EnvVars: []string{"CONTAINER_CNI_PLUGIN_DIR"},
 		},
+		&cli.StringFlag{
+			Name:    "hostport-backend",
+			Usage:   "Backend used to map the hostports of pods ('iptables' or 'nftables').",
+			Value:   defConf.HostPortBackend,
+			EnvVars: []string{"CONTAINER_HOSTPORT_BACKEND"},
+		},
+		&cli.StringFlag{
+			Name:    "hostport-reconcile-interval",
+			Usage:   "Interval in which the installed hostport rules get reconciled with the running pods. '0' disables the periodic reconciliation.",
+			Value:   defConf.HostPortReconcileInterval,
+			EnvVars: []string{"CONTAINER_HOSTPORT_RECONCILE_INTERVAL"},
+		},
+		&cli.BoolFlag{
+			Name:    "enable-bandwidth-fallback",
+			Usage:   "Limit the bandwidth of pods by traffic control rules on their host side veth, if the default CNI network does not handle the bandwidth capability.",
+			Value:   defConf.EnableBandwidthFallback,
+			EnvVars: []string{"CONTAINER_ENABLE_BANDWIDTH_FALLBACK"},
+		},
+		&cli.StringSliceFlag{
+			Name:    "dns-options",
+			Usage:   "Resolver options, like 'ndots:5', which get applied to the resolv.conf of all pods.",
+			Value:   cli.NewStringSlice(defConf.DNSOptions...),
+			EnvVars: []string{"CONTAINER_DNS_OPTIONS"},
+		},
+		&cli.StringFlag{
+			Name:      "dns-upstream-resolv-conf",
+			Usage:     "Path to the resolv.conf containing the upstream servers of a local DNS stub resolver, which replace the stub resolvers for pods outside of the host network.",
+			Value:     defConf.DNSUpstreamResolvConf,
+			EnvVars:   []string{"CONTAINER_DNS_UPSTREAM_RESOLV_CONF"},
+			TakesFile: true,
+		},
 		&cli.StringFlag{
 			Name:  "image-volumes",
 			Value: string(libconfig.ImageVolumesMkdir),
@@ -748,6 +866,12 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 			EnvVars: []string{"CONTAINER_ADD_INHERITABLE_CAPABILITIES"},
 			Value:   defConf.AddInheritableCapabilities,
 		},
+		&cli.StringFlag{
+			Name:    "capability-policy",
+			Usage:   `Policy for containers using capabilities which are not allowed by their runtime handler or Kubernetes namespace: "reject" or "strip".`,
+			EnvVars: []string{"CONTAINER_CAPABILITY_POLICY"},
+			Value:   defConf.CapabilityPolicy,
+		},
 		&cli.StringSliceFlag{
 			Name:    "default-sysctls",
 			Usage:   "Sysctls to add to the containers.",
@@ -910,6 +1034,23 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 			Value:   defConf.MinimumMappableGID,
 			EnvVars: []string{"CONTAINER_MINIMUM_MAPPABLE_GID"},
 		},
+		&cli.StringFlag{
+			Name:    "userns-allocation-user",
+			Usage:   "User whose subordinate ID ranges are used for allocating the user namespace ID ranges of pods.",
+			EnvVars: []string{"CONTAINER_USERNS_ALLOCATION_USER"},
+		},
+		&cli.StringSliceFlag{
+			Name:    "userns-allocation-ranges",
+			Usage:   "Host ID ranges in the form HOSTID:SIZE used for allocating the user namespace ID ranges of pods.",
+			EnvVars: []string{"CONTAINER_USERNS_ALLOCATION_RANGES"},
+		},
+		&cli.StringFlag{
+			Name:      "userns-allocation-file",
+			Usage:     "File where the user namespace ID ranges allocated to pods are persisted.",
+			Value:     defConf.UsernsAllocationFile,
+			EnvVars:   []string{"CONTAINER_USERNS_ALLOCATION_FILE"},
+			TakesFile: true,
+		},
 		&cli.StringSliceFlag{
 			Name:    "allowed-devices",
 			Usage:   "Devices a user is allowed to specify with the \"io.kubernetes.cri-o.Devices\" allowed annotation.",
@@ -996,6 +1137,12 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 			Value:   defConf.NamespacesDir,
 			EnvVars: []string{"CONTAINER_NAMESPACES_DIR"},
 		},
+		&cli.IntFlag{
+			Name:    "namespace-pool-size",
+			Usage:   "The number of pre-created network, IPC and UTS namespaces kept for fast pod startup. Set to 0 to disable the pool.",
+			Value:   defConf.NamespacePoolSize,
+			EnvVars: []string{"CONTAINER_NAMESPACE_POOL_SIZE"},
+		},
 		&cli.BoolFlag{
 			Name:    "no-pivot",
 			Usage:   "If true, the runtime will not use `pivot_root`, but instead use `MS_MOVE`.",
@@ -1098,6 +1245,42 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 			Usage:   "The number of seconds between collecting pod and container stats. If set to 0, the stats are collected on-demand instead.",
 			EnvVars: []string{"CONTAINER_STATS_COLLECTION_PERIOD"},
 		},
+		&cli.StringFlag{
+			Name:      "audit-log-path",
+			Value:     defConf.AuditLogPath,
+			Usage:     "Path of the audit log file of privileged container operations. If empty, the audit log is not written to a file.",
+			EnvVars:   []string{"CONTAINER_AUDIT_LOG_PATH"},
+			TakesFile: true,
+		},
+		&cli.BoolFlag{
+			Name:    "audit-log-to-journald",
+			Value:   defConf.AuditLogToJournald,
+			Usage:   "Send the audit log of privileged container operations to journald.",
+			EnvVars: []string{"CONTAINER_AUDIT_LOG_TO_JOURNALD"},
+		},
+		&cli.IntFlag{
+			Name:    "audit-log-max-size",
+			Value:   defConf.AuditLogMaxSize,
+			Usage:   "Size in megabytes at which the audit log file gets rotated.",
+			EnvVars: []string{"CONTAINER_AUDIT_LOG_MAX_SIZE"},
+		},
+		&cli.IntFlag{
+			Name:    "audit-log-max-backups",
+			Value:   defConf.AuditLogMaxBackups,
+			Usage:   "Number of rotated audit log files to retain. If set to 0, all of them are retained.",
+			EnvVars: []string{"CONTAINER_AUDIT_LOG_MAX_BACKUPS"},
+		},
+		&cli.IntFlag{
+			Name:    "audit-log-max-age",
+			Value:   defConf.AuditLogMaxAge,
+			Usage:   "Number of days to retain rotated audit log files. If set to 0, rotated files are not removed based on their age.",
+			EnvVars: []string{"CONTAINER_AUDIT_LOG_MAX_AGE"},
+		},
+		&cli.StringSliceFlag{
+			Name:    "audit-redact-patterns",
+			Usage:   "Regular expressions matching the parts of exec command arguments, which get redacted in the audit log.",
+			EnvVars: []string{"CONTAINER_AUDIT_REDACT_PATTERNS"},
+		},
 		&cli.BoolFlag{
 			Name:    "enable-criu-support",
 			Usage:   "Enable CRIU integration, requires that the criu binary is available in $PATH.",
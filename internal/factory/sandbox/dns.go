@@ -0,0 +1,216 @@
+package sandbox
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+const (
+	// maxDNSSearches is the maximum number of search domains supported by
+	// the resolver of the C library
+	maxDNSSearches = 6
+
+	// maxDNSSearchListChars is the maximum length of the search list
+	// supported by the resolver of the C library
+	maxDNSSearchListChars = 256
+
+	// hostResolvConf is the resolv.conf of the host
+	hostResolvConf = "/etc/resolv.conf"
+)
+
+// DNSConfigurer creates the resolv.conf of a pod sandbox.
+type DNSConfigurer interface {
+	// WriteResolvConf writes the resolv.conf for the DNS config of a pod to
+	// path. The returned warnings describe the adjustments which had to be
+	// made to the DNS config.
+	WriteResolvConf(dnsConfig *types.DNSConfig, hostNetwork bool, path string) (warnings []string, err error)
+}
+
+// dnsConfigurer is the default DNSConfigurer. It applies a node-wide template
+// of resolver options, replaces local stub resolvers by their upstream
+// servers and enforces the limits of the search list.
+type dnsConfigurer struct {
+	hostResolvConf     string
+	upstreamResolvConf string
+	optionsTemplate    []string
+}
+
+// NewDNSConfigurer creates a new DNSConfigurer. The resolv.conf of the host is
+// used for pods without DNS config, and the upstream resolv.conf provides the
+// servers which replace the local stub resolvers for pods which are not
+// running in the host network. The options template gets applied to all pods.
+func NewDNSConfigurer(hostResolvConf, upstreamResolvConf string, optionsTemplate []string) DNSConfigurer {
+	return &dnsConfigurer{
+		hostResolvConf:     hostResolvConf,
+		upstreamResolvConf: upstreamResolvConf,
+		optionsTemplate:    optionsTemplate,
+	}
+}
+
+func (d *dnsConfigurer) WriteResolvConf(dnsConfig *types.DNSConfig, hostNetwork bool, path string) (warnings []string, err error) {
+	servers := dnsConfig.Servers
+	searches := dnsConfig.Searches
+	options := dnsConfig.Options
+	if len(servers) == 0 && len(searches) == 0 && len(options) == 0 {
+		if hostNetwork && len(d.optionsTemplate) == 0 {
+			// the host resolv.conf works as it is
+			return nil, copyFile(d.hostResolvConf, path)
+		}
+		servers, searches, options, err = parseResolvConf(d.hostResolvConf)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	options = mergeDNSOptions(d.optionsTemplate, options)
+	if !hostNetwork {
+		var serverWarnings []string
+		servers, serverWarnings = d.replaceStubResolvers(servers)
+		warnings = append(warnings, serverWarnings...)
+	}
+	searches, searchWarnings := limitDNSSearches(searches)
+	warnings = append(warnings, searchWarnings...)
+
+	return warnings, ParseDNSOptions(servers, searches, options, path)
+}
+
+// replaceStubResolvers removes the nameservers on the loopback interface,
+// which are not reachable from the network namespace of the pod, and adds the
+// servers of the upstream resolv.conf instead.
+func (d *dnsConfigurer) replaceStubResolvers(servers []string) (result, warnings []string) {
+	stubs := []string{}
+	for _, server := range servers {
+		if ip := net.ParseIP(server); ip != nil && ip.IsLoopback() {
+			stubs = append(stubs, server)
+			continue
+		}
+		result = append(result, server)
+	}
+	if len(stubs) == 0 {
+		return servers, nil
+	}
+	warnings = append(warnings, fmt.Sprintf(
+		"Removed the nameservers %v, which are not reachable from the pod network",
+		stubs))
+
+	upstreamServers, _, _, err := parseResolvConf(d.upstreamResolvConf)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf(
+			"Unable to read the upstream nameservers: %v", err))
+		return result, warnings
+	}
+	added := []string{}
+	for _, server := range upstreamServers {
+		if ip := net.ParseIP(server); ip != nil && ip.IsLoopback() {
+			continue
+		}
+		if containsString(result, server) {
+			continue
+		}
+		result = append(result, server)
+		added = append(added, server)
+	}
+	if len(added) > 0 {
+		warnings = append(warnings, fmt.Sprintf(
+			"Using the upstream nameservers %v of %s instead",
+			added, d.upstreamResolvConf))
+	} else if len(result) == 0 {
+		warnings = append(warnings, fmt.Sprintf(
+			"No upstream nameservers found in %s, the pod has no nameservers",
+			d.upstreamResolvConf))
+	}
+	return result, warnings
+}
+
+// limitDNSSearches omits the search domains exceeding the limits of the
+// resolver of the C library.
+func limitDNSSearches(searches []string) (result, warnings []string) {
+	result = searches
+	if len(result) > maxDNSSearches {
+		result = result[:maxDNSSearches]
+	}
+	for len(result) > 0 && len(strings.Join(result, " ")) > maxDNSSearchListChars {
+		result = result[:len(result)-1]
+	}
+	if len(result) < len(searches) {
+		warnings = append(warnings, fmt.Sprintf(
+			"Search line limits of %d domains and %d characters were exceeded, some search domains have been omitted, the applied search line is: %s",
+			maxDNSSearches, maxDNSSearchListChars, strings.Join(result, " ")))
+	}
+	return result, warnings
+}
+
+// mergeDNSOptions merges the resolver options of the pod into the template,
+// whereas options of the pod replace template options with the same name.
+func mergeDNSOptions(template, options []string) []string {
+	if len(template) == 0 {
+		return options
+	}
+	names := make(map[string]bool, len(options))
+	for _, option := range options {
+		name, _, _ := strings.Cut(option, ":")
+		names[name] = true
+	}
+	result := make([]string, 0, len(template)+len(options))
+	for _, option := range template {
+		name, _, _ := strings.Cut(option, ":")
+		if !names[name] {
+			result = append(result, option)
+		}
+	}
+	return append(result, options...)
+}
+
+// parseResolvConf returns the nameservers, search domains and options of a
+// resolv.conf.
+func parseResolvConf(path string) (servers, searches, options []string, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer f.Close()
+
+	domain := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], ";") {
+			continue
+		}
+		switch fields[0] {
+		case "nameserver":
+			if len(fields) > 1 {
+				servers = append(servers, fields[1])
+			}
+		case "search":
+			searches = fields[1:]
+		case "domain":
+			if len(fields) > 1 {
+				domain = fields[1]
+			}
+		case "options":
+			options = append(options, fields[1:]...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, nil, err
+	}
+	if len(searches) == 0 && domain != "" {
+		searches = []string{domain}
+	}
+	return servers, searches, options, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
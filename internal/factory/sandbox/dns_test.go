@@ -0,0 +1,145 @@
+package sandbox_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cri-o/cri-o/internal/factory/sandbox"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// The actual test suite
+var _ = t.Describe("DNSConfigurer", func() {
+	var (
+		hostResolvConf     string
+		upstreamResolvConf string
+		path               string
+	)
+
+	writeFile := func(path, content string) {
+		Expect(os.WriteFile(path, []byte(content), 0o644)).To(BeNil())
+	}
+	readFile := func(path string) string {
+		content, err := os.ReadFile(path)
+		Expect(err).To(BeNil())
+		return string(content)
+	}
+
+	BeforeEach(func() {
+		dir := t.MustTempDir("dns")
+		hostResolvConf = filepath.Join(dir, "host-resolv.conf")
+		upstreamResolvConf = filepath.Join(dir, "upstream-resolv.conf")
+		path = filepath.Join(dir, "resolv.conf")
+		writeFile(hostResolvConf, "# stub resolver\nnameserver 127.0.0.53\noptions edns0 trust-ad\nsearch example.com\n")
+		writeFile(upstreamResolvConf, "nameserver 192.168.0.1\nnameserver ::1\nnameserver 192.168.0.2\n")
+	})
+
+	It("should copy the host resolv.conf for host network pods", func() {
+		// Given
+		sut := sandbox.NewDNSConfigurer(hostResolvConf, upstreamResolvConf, nil)
+
+		// When
+		warnings, err := sut.WriteResolvConf(&types.DNSConfig{}, true, path)
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(warnings).To(BeEmpty())
+		Expect(readFile(path)).To(Equal(readFile(hostResolvConf)))
+	})
+
+	It("should replace the stub resolvers of the host resolv.conf", func() {
+		// Given
+		sut := sandbox.NewDNSConfigurer(hostResolvConf, upstreamResolvConf, nil)
+
+		// When
+		warnings, err := sut.WriteResolvConf(&types.DNSConfig{}, false, path)
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(warnings).To(HaveLen(2))
+		Expect(warnings[0]).To(ContainSubstring("127.0.0.53"))
+		Expect(warnings[1]).To(ContainSubstring("[192.168.0.1 192.168.0.2]"))
+		Expect(readFile(path)).To(Equal("search example.com\n" +
+			"nameserver 192.168.0.1\nnameserver 192.168.0.2\n" +
+			"options edns0 trust-ad\n"))
+	})
+
+	It("should keep the stub resolvers for host network pods", func() {
+		// Given
+		sut := sandbox.NewDNSConfigurer(hostResolvConf, upstreamResolvConf, nil)
+
+		// When
+		warnings, err := sut.WriteResolvConf(&types.DNSConfig{
+			Servers: []string{"127.0.0.53"},
+		}, true, path)
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(warnings).To(BeEmpty())
+		Expect(readFile(path)).To(Equal("nameserver 127.0.0.53\n"))
+	})
+
+	It("should warn if no upstream servers are available", func() {
+		// Given
+		sut := sandbox.NewDNSConfigurer(hostResolvConf, filepath.Join(t.MustTempDir("dns"), "missing"), nil)
+
+		// When
+		warnings, err := sut.WriteResolvConf(&types.DNSConfig{
+			Servers: []string{"127.0.0.53", "10.96.0.10"},
+		}, false, path)
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(warnings).To(HaveLen(2))
+		Expect(readFile(path)).To(Equal("nameserver 10.96.0.10\n"))
+	})
+
+	It("should apply the options template", func() {
+		// Given
+		sut := sandbox.NewDNSConfigurer(hostResolvConf, upstreamResolvConf,
+			[]string{"ndots:2", "timeout:1", "attempts:3"})
+
+		// When
+		warnings, err := sut.WriteResolvConf(&types.DNSConfig{
+			Servers: []string{"10.96.0.10"},
+			Options: []string{"ndots:5"},
+		}, false, path)
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(warnings).To(BeEmpty())
+		Expect(readFile(path)).To(Equal("nameserver 10.96.0.10\n" +
+			"options timeout:1 attempts:3 ndots:5\n"))
+	})
+
+	It("should limit the search domains", func() {
+		// Given
+		sut := sandbox.NewDNSConfigurer(hostResolvConf, upstreamResolvConf, nil)
+		long := strings.Repeat("a", 100) + ".com"
+
+		// When
+		warnings, err := sut.WriteResolvConf(&types.DNSConfig{
+			Servers:  []string{"10.96.0.10"},
+			Searches: []string{"1.com", "2.com", "3.com", "4.com", "5.com", "6.com", "7.com"},
+		}, false, path)
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(warnings).To(HaveLen(1))
+		Expect(readFile(path)).To(HavePrefix("search 1.com 2.com 3.com 4.com 5.com 6.com\n"))
+
+		// When
+		warnings, err = sut.WriteResolvConf(&types.DNSConfig{
+			Servers:  []string{"10.96.0.10"},
+			Searches: []string{long, long, long},
+		}, false, path)
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(warnings).To(HaveLen(1))
+		Expect(warnings[0]).To(HaveSuffix(long + " " + long))
+	})
+})
@@ -45,7 +45,8 @@ func (s *sandbox) InitInfraContainer(serverConfig *libconfig.Config, podContaine
 	}
 	g.SetProcessArgs(pauseCommand)
 
-	if err := s.createResolvConf(podContainer); err != nil {
+	dnsConfigurer := NewDNSConfigurer(hostResolvConf, serverConfig.DNSUpstreamResolvConf, serverConfig.DNSOptions)
+	if err := s.createResolvConf(podContainer, dnsConfigurer); err != nil {
 		return fmt.Errorf("create resolv conf: %w", err)
 	}
 
@@ -86,17 +87,16 @@ func PauseCommand(cfg *libconfig.Config, image *v1.Image) ([]string, error) {
 	return cmd, nil
 }
 
-func (s *sandbox) createResolvConf(podContainer *storage.ContainerInfo) (retErr error) {
+func (s *sandbox) createResolvConf(podContainer *storage.ContainerInfo, dnsConfigurer DNSConfigurer) (retErr error) {
 	// set DNS options
 	if s.config.DnsConfig == nil {
 		return nil
 	}
 
-	dnsServers := s.config.DnsConfig.Servers
-	dnsSearches := s.config.DnsConfig.Searches
-	dnsOptions := s.config.DnsConfig.Options
+	hostNetwork := s.config.Linux.SecurityContext.NamespaceOptions.GetNetwork() == types.NamespaceMode_NODE
 	s.resolvPath = fmt.Sprintf("%s/resolv.conf", podContainer.RunDir)
-	err := ParseDNSOptions(dnsServers, dnsSearches, dnsOptions, s.resolvPath)
+	warnings, err := dnsConfigurer.WriteResolvConf(s.config.DnsConfig, hostNetwork, s.resolvPath)
+	s.dnsWarnings = warnings
 	defer func() {
 		if retErr != nil {
 			if err := os.Remove(s.resolvPath); err != nil {
@@ -39,15 +39,20 @@ type Sandbox interface {
 
 	// ResolvPath returns the sandbox's resolvPath
 	ResolvPath() string
+
+	// DNSWarnings returns the warnings about the adjustments made to the DNS
+	// config while creating the resolv.conf
+	DNSWarnings() []string
 }
 
 // sandbox is the hidden default type behind the Sandbox interface
 type sandbox struct {
-	config     *types.PodSandboxConfig
-	id         string
-	name       string
-	infra      container.Container
-	resolvPath string
+	config      *types.PodSandboxConfig
+	id          string
+	name        string
+	infra       container.Container
+	resolvPath  string
+	dnsWarnings []string
 }
 
 // New creates a new, empty Sandbox instance
@@ -138,3 +143,7 @@ func (s *sandbox) Name() string {
 func (s *sandbox) ResolvPath() string {
 	return s.resolvPath
 }
+
+func (s *sandbox) DNSWarnings() []string {
+	return s.dnsWarnings
+}
@@ -0,0 +1,178 @@
+package hostport
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type fakeNftTable struct {
+	chains map[string][]string
+	// sets and maps, whereas set elements have an empty value
+	sets map[string]map[string]string
+}
+
+// fakeNftables implements the subset of the nft script syntax which is used by
+// the nftables hostport manager.
+type fakeNftables struct {
+	tables map[string]*fakeNftTable
+}
+
+func newFakeNftables() *fakeNftables {
+	return &fakeNftables{tables: make(map[string]*fakeNftTable)}
+}
+
+func (f *fakeNftables) Run(script string) error {
+	// scripts are applied atomically, so work on a copy
+	tables := f.copyTables()
+	for _, line := range strings.Split(script, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if err := runFakeNftCommand(tables, line); err != nil {
+			return fmt.Errorf("%s: %w", line, err)
+		}
+	}
+	f.tables = tables
+	return nil
+}
+
+func (f *fakeNftables) List(family, tableName string) (*nftTableContent, error) {
+	table, ok := f.tables[family+" "+tableName]
+	if !ok {
+		return nil, errors.New("no such table")
+	}
+	content := &nftTableContent{
+		chains: make(map[string]int, len(table.chains)),
+		maps:   make(map[string]map[string]string),
+	}
+	for chain, rules := range table.chains {
+		content.chains[chain] = len(rules)
+	}
+	for name, elements := range table.sets {
+		content.maps[name] = make(map[string]string, len(elements))
+		for k, v := range elements {
+			content.maps[name][k] = v
+		}
+	}
+	return content, nil
+}
+
+func (f *fakeNftables) copyTables() map[string]*fakeNftTable {
+	tables := make(map[string]*fakeNftTable, len(f.tables))
+	for name, table := range f.tables {
+		c := &fakeNftTable{
+			chains: make(map[string][]string, len(table.chains)),
+			sets:   make(map[string]map[string]string, len(table.sets)),
+		}
+		for chain, rules := range table.chains {
+			c.chains[chain] = append([]string{}, rules...)
+		}
+		for set, elements := range table.sets {
+			c.sets[set] = make(map[string]string, len(elements))
+			for k, v := range elements {
+				c.sets[set][k] = v
+			}
+		}
+		tables[name] = c
+	}
+	return tables
+}
+
+func runFakeNftCommand(tables map[string]*fakeNftTable, line string) error {
+	args := strings.Fields(line)
+	if len(args) < 4 {
+		return errors.New("invalid command")
+	}
+	command, object, tableName := args[0]+" "+args[1], args[1], args[2]+" "+args[3]
+
+	if command == "add table" {
+		if _, ok := tables[tableName]; !ok {
+			tables[tableName] = &fakeNftTable{
+				chains: make(map[string][]string),
+				sets:   make(map[string]map[string]string),
+			}
+		}
+		return nil
+	}
+	table, ok := tables[tableName]
+	if !ok {
+		return errors.New("no such table")
+	}
+	if len(args) < 5 {
+		return fmt.Errorf("missing %s name", object)
+	}
+	name := args[4]
+	body := strings.Trim(strings.TrimSpace(strings.Join(args[5:], " ")), "{}")
+
+	switch command {
+	case "add chain":
+		if _, ok := table.chains[name]; !ok {
+			table.chains[name] = []string{}
+		}
+	case "flush chain":
+		if _, ok := table.chains[name]; !ok {
+			return errors.New("no such chain")
+		}
+		table.chains[name] = []string{}
+	case "delete chain":
+		rules, ok := table.chains[name]
+		if !ok {
+			return errors.New("no such chain")
+		}
+		if len(rules) > 0 || table.chainReferenced(name) {
+			return errors.New("chain is busy")
+		}
+		delete(table.chains, name)
+	case "add rule":
+		if _, ok := table.chains[name]; !ok {
+			return errors.New("no such chain")
+		}
+		table.chains[name] = append(table.chains[name], strings.Join(args[5:], " "))
+	case "add set", "add map":
+		if _, ok := table.sets[name]; !ok {
+			table.sets[name] = make(map[string]string)
+		}
+	case "add element":
+		set, ok := table.sets[name]
+		if !ok {
+			return errors.New("no such set")
+		}
+		key, value, _ := strings.Cut(body, " : ")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if chain, ok := strings.CutPrefix(value, "jump "); ok {
+			if _, ok := table.chains[chain]; !ok {
+				return errors.New("no such chain")
+			}
+		}
+		if existing, ok := set[key]; ok && existing != value {
+			return errors.New("element clashes with an existing one")
+		}
+		set[key] = value
+	case "delete element":
+		set, ok := table.sets[name]
+		if !ok {
+			return errors.New("no such set")
+		}
+		key := strings.TrimSpace(body)
+		if _, ok := set[key]; !ok {
+			return errors.New("no such element")
+		}
+		delete(set, key)
+	default:
+		return fmt.Errorf("unsupported command %q", command)
+	}
+	return nil
+}
+
+func (t *fakeNftTable) chainReferenced(chain string) bool {
+	for _, set := range t.sets {
+		for _, value := range set {
+			if value == "jump "+chain {
+				return true
+			}
+		}
+	}
+	return false
+}
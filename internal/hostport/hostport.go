@@ -19,6 +19,7 @@ package hostport
 import (
 	"fmt"
 	"net"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -26,6 +27,7 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	utiliptables "k8s.io/kubernetes/pkg/util/iptables"
+	utilnet "k8s.io/utils/net"
 )
 
 const (
@@ -52,6 +54,16 @@ type PodPortMapping struct {
 	IP           net.IP
 }
 
+// ReconcileResult is the outcome of a hostport reconciliation.
+type ReconcileResult struct {
+	// Readded are the identifiers of the pods whose missing hostport rules
+	// got added again.
+	Readded []string
+	// Removed are the hostport chains which got removed, because they did
+	// not belong to any pod.
+	Removed []string
+}
+
 // ipFamily refers to a specific family if not empty, i.e. "4" or "6".
 type ipFamily string
 
@@ -126,6 +138,55 @@ func portMappingToHostport(portMapping *PortMapping, family ipFamily) hostport {
 	}
 }
 
+// expectedHostport is a single hostport which is expected to be installed.
+type expectedHostport struct {
+	id             string
+	podPortMapping *PodPortMapping
+	portMapping    *PortMapping
+}
+
+// gatherExpectedHostports returns the hostports of the given IP family which
+// are expected to be installed. Port mappings without pod IP are returned
+// for both families.
+func gatherExpectedHostports(podPortMappings map[string][]*PodPortMapping, isIPv6 bool) []expectedHostport {
+	expected := []expectedHostport{}
+	for id, mappings := range podPortMappings {
+		for _, podPortMapping := range mappings {
+			if podPortMapping == nil || podPortMapping.HostNetwork {
+				continue
+			}
+			if podPortMapping.IP != nil && utilnet.IsIPv6(podPortMapping.IP) != isIPv6 {
+				continue
+			}
+			for _, pm := range gatherHostportMappings(podPortMapping, isIPv6) {
+				expected = append(expected, expectedHostport{id: id, podPortMapping: podPortMapping, portMapping: pm})
+			}
+		}
+	}
+	return expected
+}
+
+// appendPodPortMapping appends the port mapping if it is not part of the
+// slice yet.
+func appendPodPortMapping(mappings []*PodPortMapping, podPortMapping *PodPortMapping) []*PodPortMapping {
+	for _, m := range mappings {
+		if m == podPortMapping {
+			return mappings
+		}
+	}
+	return append(mappings, podPortMapping)
+}
+
+// sortedKeys returns the sorted keys of the map.
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // ensureKubeHostportChains ensures the KUBE-HOSTPORTS chain is setup correctly
 func ensureKubeHostportChains(iptables utiliptables.Interface, natInterfaceName string) error {
 	logrus.Info("Ensuring kubelet hostport chains")
@@ -22,6 +22,7 @@ import (
 	"encoding/base32"
 	"fmt"
 	"net"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -48,6 +49,11 @@ type HostPortManager interface {
 	// Remove cleans up matching port mappings
 	// Remove must be able to clean up port mappings without pod IP
 	Remove(id string, podPortMapping *PodPortMapping) error
+	// Reconcile compares the given port mappings of all pods, indexed by
+	// the pod identifier, with the installed rules. Missing rules get added
+	// again and rules which do not belong to any of the pods get removed.
+	// Port mappings without pod IP are kept as they are.
+	Reconcile(podPortMappings map[string][]*PodPortMapping) (*ReconcileResult, error)
 }
 
 type hostportManager struct {
@@ -107,14 +113,27 @@ func (hm *hostportManager) Add(id string, podPortMapping *PodPortMapping, natInt
 		hm.hostPortMap[hostport] = socket
 	}
 
+	if err := hm.addRules(id, podPortMapping, hostportMappings); err != nil {
+		// clean up opened host port if encounter any error
+		return utilerrors.NewAggregate([]error{err, hm.closeHostports(hostportMappings)})
+	}
+	return nil
+}
+
+// addRules adds the iptables rules of the hostport mappings of a pod and
+// removes the stale UDP conntrack entries afterwards.
+func (hm *hostportManager) addRules(id string, podPortMapping *PodPortMapping, hostportMappings []*PortMapping) error {
+	podFullName := getPodFullName(podPortMapping)
+	podIP := podPortMapping.IP.String()
+	isIPv6 := utilnet.IsIPv6(podPortMapping.IP)
+
 	natChains := bytes.NewBuffer(nil)
 	natRules := bytes.NewBuffer(nil)
 	writeLine(natChains, "*nat")
 
 	existingChains, existingRules, err := getExistingHostportIPTablesRules(hm.iptables)
 	if err != nil {
-		// clean up opened host port if encounter any error
-		return utilerrors.NewAggregate([]error{err, hm.closeHostports(hostportMappings)})
+		return err
 	}
 
 	newChains := []utiliptables.Chain{}
@@ -173,25 +192,29 @@ func (hm *hostportManager) Add(id string, podPortMapping *PodPortMapping, natInt
 	}
 	writeLine(natRules, "COMMIT")
 
-	if err = hm.syncIPTables(append(natChains.Bytes(), natRules.Bytes()...)); err != nil {
-		// clean up opened host port if encounter any error
-		return utilerrors.NewAggregate([]error{err, hm.closeHostports(hostportMappings)})
+	if err := hm.syncIPTables(append(natChains.Bytes(), natRules.Bytes()...)); err != nil {
+		return err
 	}
 
 	// Remove conntrack entries just after adding the new iptables rules. If the conntrack entry is removed along with
 	// the IP tables rule, it can be the case that the packets received by the node after iptables rule removal will
 	// create a new conntrack entry without any DNAT. That will result in blackhole of the traffic even after correct
 	// iptables rules have been added back.
-	logrus.Infof("Starting to delete udp conntrack entries: %v, isIPv6 - %v", conntrackPortsToRemove, isIPv6)
+	deleteUDPConntrackEntries(conntrackPortsToRemove, isIPv6)
+	return nil
+}
+
+// deleteUDPConntrackEntries removes the conntrack entries of the given UDP
+// destination ports.
+func deleteUDPConntrackEntries(ports []int, isIPv6 bool) {
+	logrus.Infof("Starting to delete udp conntrack entries: %v, isIPv6 - %v", ports, isIPv6)
 	// https://www.iana.org/assignments/protocol-numbers/protocol-numbers.xhtml
 	const protocolUDPNumber = 17
-	for _, port := range conntrackPortsToRemove {
-		err = deleteConntrackEntriesForDstPort(uint16(port), protocolUDPNumber, getNetlinkFamily(isIPv6))
-		if err != nil {
+	for _, port := range ports {
+		if err := deleteConntrackEntriesForDstPort(uint16(port), protocolUDPNumber, getNetlinkFamily(isIPv6)); err != nil {
 			logrus.Errorf("Failed to clear udp conntrack for port %d, error: %v", port, err)
 		}
 	}
-	return nil
 }
 
 func (hm *hostportManager) Remove(id string, podPortMapping *PodPortMapping) (err error) {
@@ -221,6 +244,17 @@ func (hm *hostportManager) Remove(id string, podPortMapping *PodPortMapping) (er
 		chainsToRemove = append(chainsToRemove, getHostportChain(id, pm))
 	}
 
+	if err := hm.removeChains(existingChains, existingRules, chainsToRemove); err != nil {
+		return err
+	}
+
+	// clean up opened pod host ports
+	return hm.closeHostports(hostportMappings)
+}
+
+// removeChains removes the given hostport chains and all rules referring to
+// them.
+func (hm *hostportManager) removeChains(existingChains map[utiliptables.Chain]string, existingRules []string, chainsToRemove []utiliptables.Chain) error {
 	// remove rules that consists of target chains
 	remainingRules := filterRules(existingRules, chainsToRemove)
 
@@ -233,9 +267,8 @@ func (hm *hostportManager) Remove(id string, podPortMapping *PodPortMapping) (er
 	}
 
 	// exit if there is nothing to remove
-	// don´t forget to clean up opened pod host ports
 	if len(existingChainsToRemove) == 0 {
-		return hm.closeHostports(hostportMappings)
+		return nil
 	}
 
 	natChains := bytes.NewBuffer(nil)
@@ -252,12 +285,75 @@ func (hm *hostportManager) Remove(id string, podPortMapping *PodPortMapping) (er
 	}
 	writeLine(natRules, "COMMIT")
 
-	if err := hm.syncIPTables(append(natChains.Bytes(), natRules.Bytes()...)); err != nil {
-		return err
+	return hm.syncIPTables(append(natChains.Bytes(), natRules.Bytes()...))
+}
+
+func (hm *hostportManager) Reconcile(podPortMappings map[string][]*PodPortMapping) (*ReconcileResult, error) {
+	isIPv6 := hm.iptables.IsIPv6()
+	if err := ensureKubeHostportChains(hm.iptables, ""); err != nil {
+		return nil, err
 	}
 
-	// clean up opened pod host ports
-	return hm.closeHostports(hostportMappings)
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+
+	existingChains, existingRules, err := getExistingHostportIPTablesRules(hm.iptables)
+	if err != nil {
+		return nil, err
+	}
+
+	expectedChains := make(map[utiliptables.Chain]bool)
+	missing := make(map[string][]*PodPortMapping)
+	for _, e := range gatherExpectedHostports(podPortMappings, isIPv6) {
+		chain := getHostportChain(e.id, e.portMapping)
+		expectedChains[chain] = true
+		if e.podPortMapping.IP != nil && !iptablesHostportInstalled(chain, existingRules) {
+			missing[e.id] = appendPodPortMapping(missing[e.id], e.podPortMapping)
+		}
+	}
+
+	result := &ReconcileResult{}
+	staleChains := []utiliptables.Chain{}
+	for chain := range existingChains {
+		if strings.HasPrefix(string(chain), kubeHostportChainPrefix) && !expectedChains[chain] {
+			staleChains = append(staleChains, chain)
+			result.Removed = append(result.Removed, string(chain))
+		}
+	}
+	sort.Strings(result.Removed)
+	if err := hm.removeChains(existingChains, existingRules, staleChains); err != nil {
+		return nil, err
+	}
+
+	errList := []error{}
+	for _, id := range sortedKeys(missing) {
+		readded := true
+		for _, podPortMapping := range missing[id] {
+			if err := hm.addRules(id, podPortMapping, gatherHostportMappings(podPortMapping, isIPv6)); err != nil {
+				errList = append(errList, fmt.Errorf("add hostports of pod %s: %w", getPodFullName(podPortMapping), err))
+				readded = false
+			}
+		}
+		if readded {
+			result.Readded = append(result.Readded, id)
+		}
+	}
+	return result, utilerrors.NewAggregate(errList)
+}
+
+// iptablesHostportInstalled returns true if the KUBE-HOSTPORTS chain jumps to
+// the given hostport chain and the hostport chain contains the DNAT rule.
+func iptablesHostportInstalled(chain utiliptables.Chain, rules []string) bool {
+	jumps, dnats := false, false
+	for _, rule := range rules {
+		if strings.HasPrefix(rule, fmt.Sprintf("-A %s ", kubeHostportsChain)) && strings.HasSuffix(rule, "-j "+string(chain)) {
+			jumps = true
+		}
+		if strings.HasPrefix(rule, fmt.Sprintf("-A %s ", chain)) && strings.Contains(rule, "-j DNAT") {
+			dnats = true
+		}
+	}
+	return jumps && dnats
 }
 
 // syncIPTables executes iptables-restore with given lines
@@ -274,6 +370,17 @@ func (hm *hostportManager) syncIPTables(lines []byte) error {
 // If encounter any error, clean up and return the error
 // If all ports are opened successfully, return the hostport and socket mapping
 func (hm *hostportManager) openHostports(podPortMapping *PodPortMapping) (map[hostport]closeable, error) {
+	return openHostports(hm.portOpener, podPortMapping, hm.getIPFamily())
+}
+
+// closeHostports tries to close all the listed host ports
+func (hm *hostportManager) closeHostports(hostportMappings []*PortMapping) error {
+	return closeHostports(hm.hostPortMap, hostportMappings, hm.getIPFamily())
+}
+
+// openHostports opens all hostports of the given family using the given
+// hostportOpener.
+func openHostports(portOpener hostportOpener, podPortMapping *PodPortMapping, family ipFamily) (map[hostport]closeable, error) {
 	var retErr error
 	ports := make(map[hostport]closeable)
 	for _, pm := range podPortMapping.PortMappings {
@@ -287,12 +394,12 @@ func (hm *hostportManager) openHostports(podPortMapping *PodPortMapping) (map[ho
 		}
 
 		// HostIP IP family is not handled by this port opener
-		if pm.HostIP != "" && utilnet.IsIPv6String(pm.HostIP) != hm.iptables.IsIPv6() {
+		if pm.HostIP != "" && utilnet.IsIPv6String(pm.HostIP) != (family == IPv6) {
 			continue
 		}
 
-		hp := portMappingToHostport(pm, hm.getIPFamily())
-		socket, err := hm.portOpener(&hp)
+		hp := portMappingToHostport(pm, family)
+		socket, err := portOpener(&hp)
 		if err != nil {
 			retErr = fmt.Errorf("cannot open hostport %d for pod %s: %w", pm.HostPort, getPodFullName(podPortMapping), err)
 			break
@@ -312,18 +419,19 @@ func (hm *hostportManager) openHostports(podPortMapping *PodPortMapping) (map[ho
 	return ports, nil
 }
 
-// closeHostports tries to close all the listed host ports
-func (hm *hostportManager) closeHostports(hostportMappings []*PortMapping) error {
+// closeHostports tries to close all the listed host ports of the given family
+// and removes them from the hostPortMap.
+func closeHostports(hostPortMap map[hostport]closeable, hostportMappings []*PortMapping, family ipFamily) error {
 	errList := []error{}
 	for _, pm := range hostportMappings {
-		hp := portMappingToHostport(pm, hm.getIPFamily())
-		if socket, ok := hm.hostPortMap[hp]; ok {
+		hp := portMappingToHostport(pm, family)
+		if socket, ok := hostPortMap[hp]; ok {
 			logrus.Infof("Closing host port %s", hp.String())
 			if err := socket.Close(); err != nil {
 				errList = append(errList, fmt.Errorf("failed to close host port %s: %w", hp.String(), err))
 				continue
 			}
-			delete(hm.hostPortMap, hp)
+			delete(hostPortMap, hp)
 		} else {
 			logrus.Infof("Host port %s does not have an open socket", hp.String())
 		}
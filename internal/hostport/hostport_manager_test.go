@@ -224,19 +224,15 @@ func TestOpenCloseHostports(t *testing.T) {
 	assert.Zero(t, len(manager.hostPortMap))
 }
 
-func TestHostportManager(t *testing.T) {
-	iptables := newFakeIPTables()
-	iptables.protocol = utiliptables.ProtocolIPv4
-	portOpener := newFakeSocketManager()
-	manager := &hostportManager{
-		hostPortMap: make(map[hostport]closeable),
-		iptables:    iptables,
-		portOpener:  portOpener.openFakeSocket,
-	}
-	testCases := []struct {
-		mapping     *PodPortMapping
-		expectError bool
-	}{
+type hostportManagerTestCase struct {
+	mapping     *PodPortMapping
+	expectError bool
+}
+
+// hostportManagerTestCases returns the IPv4 test cases shared by the iptables
+// and nftables hostport manager tests.
+func hostportManagerTestCases() []hostportManagerTestCase {
+	return []hostportManagerTestCase{
 		// open HostPorts 8080/TCP, 8081/UDP and 8083/SCTP
 		{
 			mapping: &PodPortMapping{
@@ -391,6 +387,18 @@ func TestHostportManager(t *testing.T) {
 			expectError: false,
 		},
 	}
+}
+
+func TestHostportManager(t *testing.T) {
+	iptables := newFakeIPTables()
+	iptables.protocol = utiliptables.ProtocolIPv4
+	portOpener := newFakeSocketManager()
+	manager := &hostportManager{
+		hostPortMap: make(map[hostport]closeable),
+		iptables:    iptables,
+		portOpener:  portOpener.openFakeSocket,
+	}
+	testCases := hostportManagerTestCases()
 
 	// Add Hostports
 	for _, tc := range testCases {
@@ -517,6 +525,64 @@ func TestHostportManager(t *testing.T) {
 	assert.Zero(t, len(manager.hostPortMap))
 }
 
+func TestHostportManagerReconcile(t *testing.T) {
+	iptables := newFakeIPTables()
+	iptables.protocol = utiliptables.ProtocolIPv4
+	manager := &hostportManager{
+		hostPortMap: make(map[hostport]closeable),
+		iptables:    iptables,
+		portOpener:  newFakeSocketManager().openFakeSocket,
+	}
+	mappings := []*PodPortMapping{}
+	for _, tc := range hostportManagerTestCases() {
+		if !tc.expectError {
+			assert.NoError(t, manager.Add("id", tc.mapping, "cbr0"))
+			mappings = append(mappings, tc.mapping)
+		}
+	}
+	stale := &PodPortMapping{
+		Name:         "stale",
+		Namespace:    "ns1",
+		IP:           net.ParseIP("10.1.1.9"),
+		PortMappings: []*PortMapping{{HostPort: 7000, ContainerPort: 70, Protocol: v1.ProtocolTCP}},
+	}
+	assert.NoError(t, manager.Add("stale", stale, ""))
+	creating := &PodPortMapping{
+		Name:         "creating",
+		Namespace:    "ns1",
+		IP:           net.ParseIP("10.1.1.10"),
+		PortMappings: []*PortMapping{{HostPort: 7001, ContainerPort: 70, Protocol: v1.ProtocolTCP}},
+	}
+	assert.NoError(t, manager.Add("creating", creating, ""))
+
+	// Flushing the hostports chain removes the rules of all pods
+	assert.NoError(t, iptables.FlushChain(utiliptables.TableNAT, kubeHostportsChain))
+	expected := map[string][]*PodPortMapping{
+		"id": mappings,
+		// the IP of the pod is not known yet
+		"creating": {{Name: creating.Name, Namespace: creating.Namespace, PortMappings: creating.PortMappings}},
+	}
+
+	result, err := manager.Reconcile(expected)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id"}, result.Readded)
+	assert.Equal(t, []string{string(getHostportChain("stale", stale.PortMappings[0]))}, result.Removed)
+
+	existingChains, existingRules, err := getExistingHostportIPTablesRules(iptables)
+	assert.NoError(t, err)
+	for _, e := range gatherExpectedHostports(map[string][]*PodPortMapping{"id": mappings}, false) {
+		assert.True(t, iptablesHostportInstalled(getHostportChain(e.id, e.portMapping), existingRules))
+	}
+	_, ok := existingChains[getHostportChain("creating", creating.PortMappings[0])]
+	assert.True(t, ok)
+
+	// nothing to do without drift
+	result, err = manager.Reconcile(expected)
+	assert.NoError(t, err)
+	assert.Empty(t, result.Readded)
+	assert.Empty(t, result.Removed)
+}
+
 func TestGetHostportChain(t *testing.T) {
 	m := make(map[string]int)
 	chain := getHostportChain("testrdma-2", &PortMapping{HostPort: 57119, Protocol: "TCP", ContainerPort: 57119})
@@ -39,6 +39,28 @@ func NewMetaHostportManager() HostPortManager {
 	return h
 }
 
+// NewNftablesMetaHostportManager creates a new HostPortManager which uses
+// nftables instead of iptables. Hostports which got added by the iptables
+// based HostPortManager get migrated to nftables.
+func NewNftablesMetaHostportManager() HostPortManager {
+	exec := utilexec.New()
+	nft := newNftables(exec)
+
+	hostportManagerv4 := newNftHostportManager(nft, IPv4)
+	if err := hostportManagerv4.migrateIPTablesRules(utiliptables.New(exec, utiliptables.ProtocolIPv4)); err != nil {
+		logrus.Warnf("Unable to migrate iptables hostport rules to nftables: %v", err)
+	}
+	hostportManagerv6 := newNftHostportManager(nft, IPv6)
+	if err := hostportManagerv6.migrateIPTablesRules(utiliptables.New(exec, utiliptables.ProtocolIPv6)); err != nil {
+		logrus.Warnf("Unable to migrate ip6tables hostport rules to nftables: %v", err)
+	}
+
+	return &metaHostportManager{
+		ipv4HostportManager: hostportManagerv4,
+		ipv6HostportManager: hostportManagerv6,
+	}
+}
+
 func (mh *metaHostportManager) Add(id string, podPortMapping *PodPortMapping, natInterfaceName string) error {
 	if utilnet.IsIPv6(podPortMapping.IP) {
 		return mh.ipv6HostportManager.Add(id, podPortMapping, natInterfaceName)
@@ -64,3 +86,24 @@ func (mh *metaHostportManager) Remove(id string, podPortMapping *PodPortMapping)
 	}
 	return nil
 }
+
+func (mh *metaHostportManager) Reconcile(podPortMappings map[string][]*PodPortMapping) (*ReconcileResult, error) {
+	// Port mappings are filtered by IP family within the managers, whereas
+	// port mappings without IP are kept by both of them.
+	result := &ReconcileResult{}
+	var errstrings []string
+	for _, manager := range []HostPortManager{mh.ipv4HostportManager, mh.ipv6HostportManager} {
+		res, err := manager.Reconcile(podPortMappings)
+		if err != nil {
+			errstrings = append(errstrings, err.Error())
+		}
+		if res != nil {
+			result.Readded = append(result.Readded, res.Readded...)
+			result.Removed = append(result.Removed, res.Removed...)
+		}
+	}
+	if len(errstrings) > 0 {
+		return result, errors.New(strings.Join(errstrings, "\n"))
+	}
+	return result, nil
+}
@@ -0,0 +1,144 @@
+package hostport
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	json "github.com/json-iterator/go"
+	"github.com/sirupsen/logrus"
+	utilexec "k8s.io/utils/exec"
+)
+
+const nftCmd = "nft"
+
+// nftables is the interface to the nftables ruleset used by the nftables
+// hostport manager.
+type nftables interface {
+	// Run applies the given nft script as a single transaction. Either all
+	// commands of the script get applied or none of them.
+	Run(script string) error
+
+	// List returns the chains and verdict maps of the given table.
+	List(family, table string) (*nftTableContent, error)
+}
+
+// nftTableContent is the content of an nftables table which is relevant for
+// the hostport manager.
+type nftTableContent struct {
+	// chains maps the chain names to their number of rules
+	chains map[string]int
+	// maps maps the verdict map names to their elements, whereas the keys
+	// use the nft script syntax and the values are the verdicts, for
+	// example "tcp . 80" and "jump hp-XYZ".
+	maps map[string]map[string]string
+}
+
+// nftRunner implements the nftables interface by using the nft binary.
+type nftRunner struct {
+	exec utilexec.Interface
+}
+
+func newNftables(exec utilexec.Interface) nftables {
+	return &nftRunner{exec: exec}
+}
+
+func (r *nftRunner) Run(script string) error {
+	logrus.Debugf("Applying nftables script: %s", script)
+	cmd := r.exec.Command(nftCmd, "-f", "-")
+	cmd.SetStdin(strings.NewReader(script))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to execute %s: %s: %w", nftCmd, strings.TrimSpace(string(out)), err)
+	}
+	return nil
+}
+
+func (r *nftRunner) List(family, table string) (*nftTableContent, error) {
+	out, err := r.exec.Command(nftCmd, "--json", "list", "table", family, table).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nftables table %s %s: %w", family, table, err)
+	}
+	return parseNftJSON(out)
+}
+
+// parseNftJSON parses the JSON output of `nft --json list table`.
+func parseNftJSON(data []byte) (*nftTableContent, error) {
+	output := struct {
+		Nftables []map[string]json.RawMessage `json:"nftables"`
+	}{}
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, fmt.Errorf("failed to parse nft output: %w", err)
+	}
+
+	content := &nftTableContent{
+		chains: make(map[string]int),
+		maps:   make(map[string]map[string]string),
+	}
+	for _, object := range output.Nftables {
+		if raw, ok := object["chain"]; ok {
+			chain := struct {
+				Name string `json:"name"`
+			}{}
+			if err := json.Unmarshal(raw, &chain); err != nil {
+				return nil, fmt.Errorf("failed to parse nft chain: %w", err)
+			}
+			content.chains[chain.Name] += 0
+		}
+		if raw, ok := object["rule"]; ok {
+			rule := struct {
+				Chain string `json:"chain"`
+			}{}
+			if err := json.Unmarshal(raw, &rule); err != nil {
+				return nil, fmt.Errorf("failed to parse nft rule: %w", err)
+			}
+			content.chains[rule.Chain]++
+		}
+		if raw, ok := object["map"]; ok {
+			verdictMap := struct {
+				Name string          `json:"name"`
+				Elem [][]interface{} `json:"elem"`
+			}{}
+			if err := json.Unmarshal(raw, &verdictMap); err != nil {
+				return nil, fmt.Errorf("failed to parse nft map: %w", err)
+			}
+			elements := make(map[string]string, len(verdictMap.Elem))
+			for _, elem := range verdictMap.Elem {
+				if len(elem) != 2 {
+					continue
+				}
+				elements[nftJSONKey(elem[0])] = nftJSONVerdict(elem[1])
+			}
+			content.maps[verdictMap.Name] = elements
+		}
+	}
+	return content, nil
+}
+
+// nftJSONKey converts a JSON set element key into the nft script syntax.
+func nftJSONKey(key interface{}) string {
+	switch k := key.(type) {
+	case map[string]interface{}:
+		if parts, ok := k["concat"].([]interface{}); ok {
+			words := make([]string, 0, len(parts))
+			for _, part := range parts {
+				words = append(words, nftJSONKey(part))
+			}
+			return strings.Join(words, " . ")
+		}
+	case float64:
+		return strconv.FormatFloat(k, 'f', -1, 64)
+	}
+	return fmt.Sprint(key)
+}
+
+// nftJSONVerdict converts a JSON verdict into the nft script syntax.
+func nftJSONVerdict(verdict interface{}) string {
+	if v, ok := verdict.(map[string]interface{}); ok {
+		for _, kind := range []string{"jump", "goto"} {
+			if target, ok := v[kind].(map[string]interface{}); ok {
+				return fmt.Sprintf("%s %v", kind, target["target"])
+			}
+		}
+	}
+	return fmt.Sprint(verdict)
+}
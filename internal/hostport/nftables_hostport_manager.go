@@ -0,0 +1,500 @@
+package hostport
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	utiliptables "k8s.io/kubernetes/pkg/util/iptables"
+	utilnet "k8s.io/utils/net"
+)
+
+const (
+	// the nftables table owned by the hostport manager
+	nftHostportsTable = "crio-hostports"
+	// the regular chain dispatching to the per hostport chains
+	nftHostportsChain = "hostports"
+	// the verdict map of hostports without a HostIP
+	nftHostportsMap = "hostports"
+	// the verdict map of hostports bound to a HostIP
+	nftHostIPHostportsMap = "hostip-hostports"
+	// the set of interfaces used to access hostports from localhost
+	nftMasqueradeInterfacesSet = "masquerade-interfaces"
+	// prefix for hostport chains
+	nftHostportChainPrefix = "hp-"
+	// the packet mark used for hairpin traffic, which matches the mark used
+	// by the KUBE-MARK-MASQ chain
+	nftMasqueradeMark = "0x4000"
+)
+
+type nftHostportManager struct {
+	hostPortMap map[hostport]closeable
+	nft         nftables
+	family      ipFamily
+	portOpener  hostportOpener
+	mu          sync.Mutex
+}
+
+func newNftHostportManager(nft nftables, family ipFamily) *nftHostportManager {
+	return &nftHostportManager{
+		hostPortMap: make(map[hostport]closeable),
+		nft:         nft,
+		family:      family,
+		portOpener:  openLocalPort,
+	}
+}
+
+func (hm *nftHostportManager) Add(id string, podPortMapping *PodPortMapping, natInterfaceName string) error {
+	if podPortMapping == nil || podPortMapping.HostNetwork {
+		return nil
+	}
+	podFullName := getPodFullName(podPortMapping)
+	// IP.To16() returns nil if IP is not a valid IPv4 or IPv6 address
+	if podPortMapping.IP.To16() == nil {
+		return fmt.Errorf("invalid or missing IP of pod %s", podFullName)
+	}
+	podIP := podPortMapping.IP.String()
+	isIPv6 := utilnet.IsIPv6(podPortMapping.IP)
+
+	// skip if there is no hostport needed
+	hostportMappings := gatherHostportMappings(podPortMapping, isIPv6)
+	if len(hostportMappings) == 0 {
+		return nil
+	}
+
+	if isIPv6 != hm.isIPv6() {
+		return fmt.Errorf("HostPortManager IP family mismatch: %v, isIPv6 - %v", podIP, isIPv6)
+	}
+
+	// Ensure atomicity for port opening and nftables operations
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+
+	// try to open hostports
+	ports, err := openHostports(hm.portOpener, podPortMapping, hm.family)
+	if err != nil {
+		return err
+	}
+	for hostport, socket := range ports {
+		hm.hostPortMap[hostport] = socket
+	}
+
+	if err := hm.addRules(id, podPortMapping, hostportMappings, natInterfaceName); err != nil {
+		// clean up opened host port if encounter any error
+		return utilerrors.NewAggregate([]error{err, closeHostports(hm.hostPortMap, hostportMappings, hm.family)})
+	}
+	return nil
+}
+
+// addRules adds the nftables rules of the hostport mappings of a pod and
+// removes the stale UDP conntrack entries afterwards.
+func (hm *nftHostportManager) addRules(id string, podPortMapping *PodPortMapping, hostportMappings []*PortMapping, natInterfaceName string) error {
+	podFullName := getPodFullName(podPortMapping)
+	podIP := podPortMapping.IP.String()
+
+	script := bytes.NewBuffer(nil)
+	hm.writeTable(script)
+	if natInterfaceName != "" && natInterfaceName != "lo" {
+		// Need to SNAT traffic from localhost
+		hm.writeLine(script, "add element", nftMasqueradeInterfacesSet, fmt.Sprintf("{ %q }", natInterfaceName))
+	}
+
+	conntrackPortsToRemove := []int{}
+	for _, pm := range hostportMappings {
+		if pm.Protocol == v1.ProtocolUDP {
+			conntrackPortsToRemove = append(conntrackPortsToRemove, int(pm.HostPort))
+		}
+		hm.writeHostport(script, getNftHostportChain(id, pm), podIP, pm,
+			fmt.Sprintf("%s hostport %d", podFullName, pm.HostPort))
+	}
+
+	if err := hm.nft.Run(script.String()); err != nil {
+		return err
+	}
+
+	// Remove conntrack entries just after adding the new nftables rules,
+	// see hostportManager.Add for the reasoning.
+	deleteUDPConntrackEntries(conntrackPortsToRemove, hm.isIPv6())
+	return nil
+}
+
+func (hm *nftHostportManager) Remove(id string, podPortMapping *PodPortMapping) error {
+	if podPortMapping == nil || podPortMapping.HostNetwork {
+		return nil
+	}
+
+	hostportMappings := gatherHostportMappings(podPortMapping, hm.isIPv6())
+	if len(hostportMappings) == 0 {
+		return nil
+	}
+
+	// Ensure atomicity for port closing and nftables operations
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+
+	errList := []error{}
+	for _, pm := range hostportMappings {
+		chain := getNftHostportChain(id, pm)
+
+		// The map element and the chain are added before deleting them,
+		// because nft fails to delete non existing objects.
+		script := bytes.NewBuffer(nil)
+		hm.writeTable(script)
+		hm.writeLine(script, "add chain", chain)
+		mapName, key := hm.hostportMapKey(pm)
+		hm.writeLine(script, "add element", mapName, fmt.Sprintf("{ %s : jump %s }", key, chain))
+		hm.writeLine(script, "delete element", mapName, fmt.Sprintf("{ %s }", key))
+		hm.writeLine(script, "flush chain", chain)
+		hm.writeLine(script, "delete chain", chain)
+		if err := hm.nft.Run(script.String()); err == nil {
+			continue
+		}
+
+		// The hostport got taken over by another chain, which means that
+		// only the chain itself has to be removed.
+		logrus.Infof("Hostport %d/%s is not mapped to chain %s, removing only the chain", pm.HostPort, pm.Protocol, chain)
+		script.Reset()
+		hm.writeTable(script)
+		hm.writeLine(script, "add chain", chain)
+		hm.writeLine(script, "flush chain", chain)
+		hm.writeLine(script, "delete chain", chain)
+		if err := hm.nft.Run(script.String()); err != nil {
+			errList = append(errList, err)
+		}
+	}
+	if len(errList) > 0 {
+		return utilerrors.NewAggregate(errList)
+	}
+
+	// clean up opened pod host ports
+	return closeHostports(hm.hostPortMap, hostportMappings, hm.family)
+}
+
+func (hm *nftHostportManager) Reconcile(podPortMappings map[string][]*PodPortMapping) (*ReconcileResult, error) {
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+
+	// Recreate the table and its base chains first, which also fixes a
+	// flushed ruleset.
+	script := bytes.NewBuffer(nil)
+	hm.writeTable(script)
+	if err := hm.nft.Run(script.String()); err != nil {
+		return nil, err
+	}
+	content, err := hm.nft.List(hm.nftFamily(), nftHostportsTable)
+	if err != nil {
+		return nil, err
+	}
+
+	expectedChains := make(map[string]bool)
+	missing := make(map[string][]*PodPortMapping)
+	for _, e := range gatherExpectedHostports(podPortMappings, hm.isIPv6()) {
+		chain := getNftHostportChain(e.id, e.portMapping)
+		expectedChains[chain] = true
+		mapName, key := hm.hostportMapKey(e.portMapping)
+		installed := content.chains[chain] > 0 && content.maps[mapName][key] == "jump "+chain
+		if e.podPortMapping.IP != nil && !installed {
+			missing[e.id] = appendPodPortMapping(missing[e.id], e.podPortMapping)
+		}
+	}
+
+	result := &ReconcileResult{}
+	script.Reset()
+	for _, mapName := range []string{nftHostportsMap, nftHostIPHostportsMap} {
+		for _, key := range sortedKeys(content.maps[mapName]) {
+			chain := strings.TrimPrefix(content.maps[mapName][key], "jump ")
+			if !expectedChains[chain] {
+				hm.writeLine(script, "delete element", mapName, fmt.Sprintf("{ %s }", key))
+			}
+		}
+	}
+	for _, chain := range sortedKeys(content.chains) {
+		if strings.HasPrefix(chain, nftHostportChainPrefix) && !expectedChains[chain] {
+			hm.writeLine(script, "flush chain", chain)
+			hm.writeLine(script, "delete chain", chain)
+			result.Removed = append(result.Removed, chain)
+		}
+	}
+	if script.Len() > 0 {
+		if err := hm.nft.Run(script.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	errList := []error{}
+	for _, id := range sortedKeys(missing) {
+		readded := true
+		for _, podPortMapping := range missing[id] {
+			if err := hm.addRules(id, podPortMapping, gatherHostportMappings(podPortMapping, hm.isIPv6()), ""); err != nil {
+				errList = append(errList, fmt.Errorf("add hostports of pod %s: %w", getPodFullName(podPortMapping), err))
+				readded = false
+			}
+		}
+		if readded {
+			result.Readded = append(result.Readded, id)
+		}
+	}
+	return result, utilerrors.NewAggregate(errList)
+}
+
+// writeTable writes the commands which ensure that the hostport table and its
+// base chains exist. The base chains are flushed and recreated, which keeps
+// the commands idempotent, whereas the maps keep their elements.
+func (hm *nftHostportManager) writeTable(buf *bytes.Buffer) {
+	ipKeyword, addrType, localhost := "ip", "ipv4_addr", "127.0.0.0/8"
+	if hm.isIPv6() {
+		ipKeyword, addrType, localhost = "ip6", "ipv6_addr", "::1"
+	}
+
+	hm.writeLine(buf, "add table")
+	hm.writeLine(buf, "add map", nftHostportsMap, "{ type inet_proto . inet_service : verdict ; }")
+	hm.writeLine(buf, "add map", nftHostIPHostportsMap, fmt.Sprintf("{ type %s . inet_proto . inet_service : verdict ; }", addrType))
+	hm.writeLine(buf, "add set", nftMasqueradeInterfacesSet, "{ type ifname ; }")
+	hm.writeLine(buf, "add chain", nftHostportsChain)
+	hm.writeLine(buf, "add chain", "prerouting", "{ type nat hook prerouting priority dstnat ; policy accept ; }")
+	hm.writeLine(buf, "add chain", "output", "{ type nat hook output priority dstnat ; policy accept ; }")
+	hm.writeLine(buf, "add chain", "postrouting", "{ type nat hook postrouting priority srcnat ; policy accept ; }")
+	for _, chain := range []string{nftHostportsChain, "prerouting", "output", "postrouting"} {
+		hm.writeLine(buf, "flush chain", chain)
+	}
+	// HostIP specific hostports are more specific, so they have to be
+	// matched first.
+	hm.writeLine(buf, "add rule", nftHostportsChain, ipKeyword, "daddr . meta l4proto . th dport vmap", "@"+nftHostIPHostportsMap)
+	hm.writeLine(buf, "add rule", nftHostportsChain, "meta l4proto . th dport vmap", "@"+nftHostportsMap)
+	hm.writeLine(buf, "add rule", "prerouting", "fib daddr type local jump", nftHostportsChain)
+	hm.writeLine(buf, "add rule", "output", "fib daddr type local jump", nftHostportsChain)
+	hm.writeLine(buf, "add rule", "postrouting", "meta mark and", nftMasqueradeMark, "==", nftMasqueradeMark, "masquerade")
+	hm.writeLine(buf, "add rule", "postrouting", ipKeyword, "saddr", localhost, "oifname", "@"+nftMasqueradeInterfacesSet, "masquerade")
+}
+
+// writeHostport writes the commands which (re)create the chain of a single
+// hostport and map the hostport to it.
+func (hm *nftHostportManager) writeHostport(buf *bytes.Buffer, chain, podIP string, pm *PortMapping, comment string) {
+	ipKeyword := "ip"
+	if hm.isIPv6() {
+		ipKeyword = "ip6"
+	}
+	protocol := strings.ToLower(string(pm.Protocol))
+	hostPortBinding := net.JoinHostPort(podIP, strconv.Itoa(int(pm.ContainerPort)))
+
+	hm.writeLine(buf, "add chain", chain)
+	hm.writeLine(buf, "flush chain", chain)
+	// SNAT if the traffic comes from the pod itself
+	hm.writeLine(buf, "add rule", chain, ipKeyword, "saddr", podIP,
+		"meta mark set meta mark or", nftMasqueradeMark, "comment", strconv.Quote(comment))
+	// DNAT to the podIP:containerPort
+	hm.writeLine(buf, "add rule", chain, "meta l4proto", protocol,
+		"dnat to", hostPortBinding, "comment", strconv.Quote(comment))
+
+	mapName, key := hm.hostportMapKey(pm)
+	hm.writeLine(buf, "add element", mapName, fmt.Sprintf("{ %s : jump %s }", key, chain))
+}
+
+// hostportMapKey returns the verdict map and the key of the hostport.
+func (hm *nftHostportManager) hostportMapKey(pm *PortMapping) (mapName, key string) {
+	protocol := strings.ToLower(string(pm.Protocol))
+	if pm.HostIP == "" || pm.HostIP == "0.0.0.0" || pm.HostIP == "::" {
+		return nftHostportsMap, fmt.Sprintf("%s . %d", protocol, pm.HostPort)
+	}
+	return nftHostIPHostportsMap, fmt.Sprintf("%s . %s . %d", pm.HostIP, protocol, pm.HostPort)
+}
+
+// writeLine writes a single nft command for an object of the hostport table.
+func (hm *nftHostportManager) writeLine(buf *bytes.Buffer, command string, args ...string) {
+	words := append([]string{command, hm.nftFamily(), nftHostportsTable}, args...)
+	buf.WriteString(strings.Join(words, " "))
+	buf.WriteByte('\n')
+}
+
+// nftFamily returns the nftables family of the hostport table.
+func (hm *nftHostportManager) nftFamily() string {
+	if hm.isIPv6() {
+		return "ip6"
+	}
+	return "ip"
+}
+
+func (hm *nftHostportManager) isIPv6() bool {
+	return hm.family == IPv6
+}
+
+// getNftHostportChain returns the nftables chain of the hostport. It uses the
+// same hash as getHostportChain, which allows to migrate iptables chains.
+func getNftHostportChain(id string, pm *PortMapping) string {
+	return iptablesToNftHostportChain(string(getHostportChain(id, pm)))
+}
+
+func iptablesToNftHostportChain(chain string) string {
+	return nftHostportChainPrefix + strings.TrimPrefix(chain, kubeHostportChainPrefix)
+}
+
+// migrateIPTablesRules moves the hostports managed by the iptables hostport
+// manager into the nftables table and removes the iptables chains afterwards.
+// The nftables hostport chains use the same hash like the iptables ones, which
+// means that the migrated hostports can be removed as usual.
+func (hm *nftHostportManager) migrateIPTablesRules(iptables utiliptables.Interface) error {
+	if iptables.IsIPv6() != hm.isIPv6() {
+		return fmt.Errorf("HostPortManager IP family mismatch: isIPv6 - %v", iptables.IsIPv6())
+	}
+
+	existingChains, existingRules, err := getExistingHostportIPTablesRules(iptables)
+	if err != nil {
+		return err
+	}
+	hostportChains := []utiliptables.Chain{}
+	for chain := range existingChains {
+		if strings.HasPrefix(string(chain), kubeHostportChainPrefix) {
+			hostportChains = append(hostportChains, chain)
+		}
+	}
+	if len(hostportChains) == 0 {
+		return nil
+	}
+
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+
+	script := bytes.NewBuffer(nil)
+	hm.writeTable(script)
+	migratedChains := []utiliptables.Chain{}
+	skippedChains := map[utiliptables.Chain]bool{}
+	for _, chain := range hostportChains {
+		pm, podIP, comment, err := parseIPTablesHostportRules(chain, existingRules)
+		if err != nil {
+			logrus.Warnf("Skipping migration of iptables hostport chain %s: %v", chain, err)
+			skippedChains[chain] = true
+			continue
+		}
+		logrus.Infof("Migrating iptables hostport chain %s (%s) to nftables", chain, comment)
+		hm.writeHostport(script, iptablesToNftHostportChain(string(chain)), podIP, pm, comment)
+		migratedChains = append(migratedChains, chain)
+	}
+	if len(migratedChains) == 0 {
+		return nil
+	}
+	if err := hm.nft.Run(script.String()); err != nil {
+		return err
+	}
+
+	// Declaring the chains flushes them, which allows to delete the migrated
+	// hostport chains afterwards. The jumps to the skipped chains are added
+	// back, so that their hostports keep working via iptables.
+	natRules := bytes.NewBuffer(nil)
+	writeLine(natRules, "*nat")
+	if line, ok := existingChains[kubeHostportsChain]; ok {
+		writeLine(natRules, line)
+	}
+	for _, chain := range migratedChains {
+		writeLine(natRules, existingChains[chain])
+	}
+	for _, rule := range existingRules {
+		args := splitIPTablesRule(rule)
+		if len(args) >= 2 && args[0] == "-A" && args[1] == string(kubeHostportsChain) && jumpsToChain(args, skippedChains) {
+			writeLine(natRules, rule)
+		}
+	}
+	for _, chain := range migratedChains {
+		writeLine(natRules, "-X", string(chain))
+	}
+	writeLine(natRules, "COMMIT")
+	logrus.Infof("Removing migrated iptables rules: %s", natRules.Bytes())
+	if err := iptables.RestoreAll(natRules.Bytes(), utiliptables.NoFlushTables, utiliptables.RestoreCounters); err != nil {
+		return fmt.Errorf("failed to execute iptables-restore: %w", err)
+	}
+	return nil
+}
+
+// jumpsToChain returns true if the iptables rule jumps to one of the chains.
+func jumpsToChain(args []string, chains map[utiliptables.Chain]bool) bool {
+	for i := 0; i+1 < len(args); i++ {
+		if args[i] == "-j" && chains[utiliptables.Chain(args[i+1])] {
+			return true
+		}
+	}
+	return false
+}
+
+// parseIPTablesHostportRules reconstructs the port mapping of an iptables
+// hostport chain from the rules written by the iptables hostport manager.
+// nolint:gocritic // unnamedResult: consider giving a name to these results
+func parseIPTablesHostportRules(chain utiliptables.Chain, rules []string) (*PortMapping, string, string, error) {
+	pm := &PortMapping{}
+	var podIP, comment string
+	for _, rule := range rules {
+		args := splitIPTablesRule(rule)
+		if len(args) < 2 || args[0] != "-A" {
+			continue
+		}
+		values := map[string]string{}
+		for i := 2; i+1 < len(args); i++ {
+			if strings.HasPrefix(args[i], "-") {
+				values[args[i]] = args[i+1]
+			}
+			if value, ok := strings.CutPrefix(args[i], "--to-destination="); ok {
+				values["--to-destination"] = value
+			}
+		}
+
+		switch {
+		case args[1] == string(kubeHostportsChain) && values["-j"] == string(chain):
+			port, err := strconv.ParseInt(values["--dport"], 10, 32)
+			if err != nil {
+				return nil, "", "", fmt.Errorf("invalid hostport rule %q: %w", rule, err)
+			}
+			pm.HostPort = int32(port)
+			pm.Protocol = v1.Protocol(strings.ToUpper(values["-p"]))
+
+		case args[1] == string(chain) && values["-j"] == "DNAT":
+			host, port, err := net.SplitHostPort(values["--to-destination"])
+			if err != nil {
+				return nil, "", "", fmt.Errorf("invalid hostport rule %q: %w", rule, err)
+			}
+			containerPort, err := strconv.ParseInt(port, 10, 32)
+			if err != nil {
+				return nil, "", "", fmt.Errorf("invalid hostport rule %q: %w", rule, err)
+			}
+			podIP = host
+			pm.ContainerPort = int32(containerPort)
+			if hostIP, ok := values["-d"]; ok {
+				pm.HostIP = strings.TrimSuffix(strings.TrimSuffix(hostIP, "/32"), "/128")
+			}
+			comment = strings.Trim(values["--comment"], `"`)
+		}
+	}
+	if pm.HostPort == 0 || pm.Protocol == "" || podIP == "" {
+		return nil, "", "", fmt.Errorf("incomplete rules of chain %s", chain)
+	}
+	return pm, podIP, comment, nil
+}
+
+// splitIPTablesRule splits an iptables-save rule into its arguments, whereas
+// double quoted arguments are kept together.
+func splitIPTablesRule(rule string) []string {
+	args := []string{}
+	quoted := false
+	current := strings.Builder{}
+	for _, r := range rule {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			current.WriteRune(r)
+		case r == ' ' && !quoted:
+			if current.Len() > 0 {
+				args = append(args, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		args = append(args, current.String())
+	}
+	return args
+}
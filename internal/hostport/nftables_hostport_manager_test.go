@@ -0,0 +1,319 @@
+package hostport
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	utiliptables "k8s.io/kubernetes/pkg/util/iptables"
+)
+
+func newFakeNftHostportManager(nft *fakeNftables, family ipFamily, portOpener *fakeSocketManager) *nftHostportManager {
+	manager := newNftHostportManager(nft, family)
+	manager.portOpener = portOpener.openFakeSocket
+	return manager
+}
+
+func TestNftablesHostportManager(t *testing.T) {
+	nft := newFakeNftables()
+	portOpener := newFakeSocketManager()
+	manager := newFakeNftHostportManager(nft, IPv4, portOpener)
+	testCases := hostportManagerTestCases()
+
+	// Add Hostports
+	for _, tc := range testCases {
+		err := manager.Add("id", tc.mapping, "cbr0")
+		if tc.expectError {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+	}
+
+	// Check port opened
+	expectedPorts := []hostport{
+		{IPv4, "", 8080, "tcp"},
+		{IPv4, "", 8081, "udp"},
+		{IPv4, "", 8443, "tcp"},
+		{IPv4, "127.0.0.1", 8888, "tcp"},
+		{IPv4, "127.0.0.2", 8888, "tcp"},
+		{IPv4, "", 9999, "tcp"},
+		{IPv4, "", 9999, "udp"},
+	}
+	openedPorts := make(map[hostport]bool)
+	for hp, port := range portOpener.mem {
+		if !port.closed {
+			openedPorts[hp] = true
+		}
+	}
+	assert.EqualValues(t, len(openedPorts), len(expectedPorts))
+	for _, hp := range expectedPorts {
+		_, ok := openedPorts[hp]
+		assert.EqualValues(t, true, ok)
+	}
+
+	// Check the nftables table after adding hostports
+	table, ok := nft.tables["ip "+nftHostportsTable]
+	require.True(t, ok)
+	assert.Equal(t, map[string]string{
+		"tcp . 8080":  "jump hp-IJHALPHTORMHHPPK",
+		"udp . 8081":  "jump hp-63UPIDJXVRSZGSUZ",
+		"sctp . 8083": "jump hp-XU6AWMMJYOZOFTFZ",
+		"tcp . 8443":  "jump hp-WFBOALXEP42XEMJK",
+		"tcp . 9999":  "jump hp-WFUNFVXVDLD5ZVXN",
+		"udp . 9999":  "jump hp-4MFWH2F2NAOMYD6A",
+	}, table.sets[nftHostportsMap])
+	assert.Equal(t, map[string]string{
+		"127.0.0.1 . tcp . 8888": "jump hp-TUKTZ736U5JD5UTK",
+		"127.0.0.2 . tcp . 8888": "jump hp-CAAJ45HDITK7ARGM",
+	}, table.sets[nftHostIPHostportsMap])
+	assert.Equal(t, map[string]string{`"cbr0"`: ""}, table.sets[nftMasqueradeInterfacesSet])
+	assert.Equal(t, []string{
+		`ip saddr 10.1.1.2 meta mark set meta mark or 0x4000 comment "pod1_ns1 hostport 8080"`,
+		`meta l4proto tcp dnat to 10.1.1.2:80 comment "pod1_ns1 hostport 8080"`,
+	}, table.chains["hp-IJHALPHTORMHHPPK"])
+	assert.Equal(t, []string{
+		`ip saddr 10.1.1.5 meta mark set meta mark or 0x4000 comment "pod5_ns5 hostport 8888"`,
+		`meta l4proto tcp dnat to 10.1.1.5:443 comment "pod5_ns5 hostport 8888"`,
+	}, table.chains["hp-TUKTZ736U5JD5UTK"])
+	assert.Len(t, table.chains["prerouting"], 1)
+	assert.Len(t, table.chains["output"], 1)
+	assert.Len(t, table.chains["postrouting"], 2)
+
+	// Remove all added hostports
+	for _, tc := range testCases {
+		if !tc.expectError {
+			err := manager.Remove("id", tc.mapping)
+			assert.NoError(t, err)
+		}
+	}
+
+	// Check the nftables table after deleting hostports
+	table = nft.tables["ip "+nftHostportsTable]
+	for chain := range table.chains {
+		assert.False(t, strings.HasPrefix(chain, nftHostportChainPrefix), chain)
+	}
+	assert.Empty(t, table.sets[nftHostportsMap])
+	assert.Empty(t, table.sets[nftHostIPHostportsMap])
+
+	// check if all ports are closed
+	for _, port := range portOpener.mem {
+		assert.EqualValues(t, true, port.closed)
+	}
+	// Clear all elements in hostPortMap
+	assert.Zero(t, len(manager.hostPortMap))
+}
+
+func TestNftablesHostportManagerIPv6(t *testing.T) {
+	nft := newFakeNftables()
+	portOpener := newFakeSocketManager()
+	manager := newFakeNftHostportManager(nft, IPv6, portOpener)
+	mapping := &PodPortMapping{
+		Name:      "pod1",
+		Namespace: "ns1",
+		IP:        net.ParseIP("2001:beef::2"),
+		PortMappings: []*PortMapping{
+			{HostPort: 8080, ContainerPort: 80, Protocol: v1.ProtocolTCP},
+			{HostPort: 8081, ContainerPort: 81, Protocol: v1.ProtocolUDP, HostIP: "::1"},
+			// skipped because of the different IP family
+			{HostPort: 8082, ContainerPort: 82, Protocol: v1.ProtocolUDP, HostIP: "127.0.0.1"},
+		},
+	}
+
+	// the IPv6 manager does not handle IPv4 pods
+	err := manager.Add("id", &PodPortMapping{
+		Name:         "pod2",
+		Namespace:    "ns1",
+		IP:           net.ParseIP("10.1.1.2"),
+		PortMappings: []*PortMapping{{HostPort: 8080, ContainerPort: 80, Protocol: v1.ProtocolTCP}},
+	}, "")
+	assert.Error(t, err)
+
+	err = manager.Add("id", mapping, "")
+	require.NoError(t, err)
+
+	table, ok := nft.tables["ip6 "+nftHostportsTable]
+	require.True(t, ok)
+	assert.Len(t, table.sets[nftHostportsMap], 1)
+	assert.Equal(t, map[string]string{
+		"::1 . udp . 8081": "jump " + getNftHostportChain("id", mapping.PortMappings[1]),
+	}, table.sets[nftHostIPHostportsMap])
+	assert.Contains(t, table.chains[getNftHostportChain("id", mapping.PortMappings[0])],
+		`meta l4proto tcp dnat to [2001:beef::2]:80 comment "pod1_ns1 hostport 8080"`)
+
+	err = manager.Remove("id", mapping)
+	require.NoError(t, err)
+	assert.Empty(t, nft.tables["ip6 "+nftHostportsTable].sets[nftHostportsMap])
+	assert.Empty(t, nft.tables["ip6 "+nftHostportsTable].sets[nftHostIPHostportsMap])
+	assert.Zero(t, len(manager.hostPortMap))
+}
+
+func TestNftablesHostportManagerRemoveTakenOverHostport(t *testing.T) {
+	nft := newFakeNftables()
+	manager := newFakeNftHostportManager(nft, IPv4, newFakeSocketManager())
+	mapping := func(name string) *PodPortMapping {
+		return &PodPortMapping{
+			Name:         name,
+			Namespace:    "ns1",
+			IP:           net.ParseIP("10.1.1.2"),
+			PortMappings: []*PortMapping{{HostPort: 7777, ContainerPort: 77, Protocol: v1.ProtocolSCTP}},
+		}
+	}
+
+	// SCTP ports are not opened, which means that a conflict is only
+	// detected by nftables.
+	require.NoError(t, manager.Add("id1", mapping("pod1"), ""))
+	assert.Error(t, manager.Add("id2", mapping("pod2"), ""))
+
+	// the stale chain of the second pod gets removed, whereas the hostport
+	// of the first pod is kept
+	chain := getNftHostportChain("id1", mapping("pod1").PortMappings[0])
+	staleChain := getNftHostportChain("id2", mapping("pod2").PortMappings[0])
+	require.NoError(t, nft.Run("add chain ip "+nftHostportsTable+" "+staleChain))
+	assert.NoError(t, manager.Remove("id2", mapping("pod2")))
+
+	table := nft.tables["ip "+nftHostportsTable]
+	assert.NotContains(t, table.chains, staleChain)
+	assert.Contains(t, table.chains, chain)
+	assert.Equal(t, "jump "+chain, table.sets[nftHostportsMap]["sctp . 7777"])
+}
+
+func TestNftablesHostportManagerMigration(t *testing.T) {
+	iptables := newFakeIPTables()
+	iptables.protocol = utiliptables.ProtocolIPv4
+	iptablesManager := &hostportManager{
+		hostPortMap: make(map[hostport]closeable),
+		iptables:    iptables,
+		portOpener:  newFakeSocketManager().openFakeSocket,
+	}
+	testCases := hostportManagerTestCases()
+	for _, tc := range testCases {
+		if !tc.expectError {
+			require.NoError(t, iptablesManager.Add("id", tc.mapping, "cbr0"))
+		}
+	}
+
+	nft := newFakeNftables()
+	portOpener := newFakeSocketManager()
+	manager := newFakeNftHostportManager(nft, IPv4, portOpener)
+	require.NoError(t, manager.migrateIPTablesRules(iptables))
+
+	// all iptables hostport chains got removed
+	existingChains, existingRules, err := getExistingHostportIPTablesRules(iptables)
+	require.NoError(t, err)
+	for chain := range existingChains {
+		assert.False(t, strings.HasPrefix(string(chain), kubeHostportChainPrefix), chain)
+	}
+	assert.Empty(t, existingRules)
+
+	// and got migrated to nftables
+	table, ok := nft.tables["ip "+nftHostportsTable]
+	require.True(t, ok)
+	assert.Len(t, table.sets[nftHostportsMap], 6)
+	assert.Equal(t, map[string]string{
+		"127.0.0.1 . tcp . 8888": "jump hp-TUKTZ736U5JD5UTK",
+		"127.0.0.2 . tcp . 8888": "jump hp-CAAJ45HDITK7ARGM",
+	}, table.sets[nftHostIPHostportsMap])
+	assert.Equal(t, []string{
+		`ip saddr 10.1.1.2 meta mark set meta mark or 0x4000 comment "pod1_ns1 hostport 8081"`,
+		`meta l4proto udp dnat to 10.1.1.2:81 comment "pod1_ns1 hostport 8081"`,
+	}, table.chains["hp-63UPIDJXVRSZGSUZ"])
+
+	// the migrated hostports can be removed as usual
+	for _, tc := range testCases {
+		if !tc.expectError {
+			assert.NoError(t, manager.Remove("id", tc.mapping))
+		}
+	}
+	assert.Empty(t, nft.tables["ip "+nftHostportsTable].sets[nftHostportsMap])
+	assert.Empty(t, nft.tables["ip "+nftHostportsTable].sets[nftHostIPHostportsMap])
+}
+
+func TestNftablesHostportManagerMigrationSkipsIncompleteChains(t *testing.T) {
+	iptables := newFakeIPTables()
+	iptables.protocol = utiliptables.ProtocolIPv4
+	iptablesManager := &hostportManager{
+		hostPortMap: make(map[hostport]closeable),
+		iptables:    iptables,
+		portOpener:  newFakeSocketManager().openFakeSocket,
+	}
+	require.NoError(t, iptablesManager.Add("id", &PodPortMapping{
+		Name:         "pod1",
+		Namespace:    "ns1",
+		IP:           net.ParseIP("10.1.1.2"),
+		PortMappings: []*PortMapping{{HostPort: 8080, ContainerPort: 80, Protocol: v1.ProtocolTCP}},
+	}, "cbr0"))
+
+	// a chain without DNAT rule cannot be migrated
+	const brokenChain = utiliptables.Chain(kubeHostportChainPrefix + "BROKEN")
+	_, err := iptables.EnsureChain(utiliptables.TableNAT, brokenChain)
+	require.NoError(t, err)
+	jump := "-p tcp --dport 9090 -j " + string(brokenChain)
+	_, err = iptables.EnsureRule(utiliptables.Append, utiliptables.TableNAT, kubeHostportsChain, strings.Split(jump, " ")...)
+	require.NoError(t, err)
+
+	nft := newFakeNftables()
+	manager := newFakeNftHostportManager(nft, IPv4, newFakeSocketManager())
+	require.NoError(t, manager.migrateIPTablesRules(iptables))
+
+	// only the skipped chain and its jump are left in iptables
+	existingChains, existingRules, err := getExistingHostportIPTablesRules(iptables)
+	require.NoError(t, err)
+	hostportChains := []utiliptables.Chain{}
+	for chain := range existingChains {
+		if strings.HasPrefix(string(chain), kubeHostportChainPrefix) {
+			hostportChains = append(hostportChains, chain)
+		}
+	}
+	assert.Equal(t, []utiliptables.Chain{brokenChain}, hostportChains)
+	assert.Equal(t, []string{"-A " + string(kubeHostportsChain) + " " + jump}, existingRules)
+
+	// and the complete chain got migrated to nftables
+	table, ok := nft.tables["ip "+nftHostportsTable]
+	require.True(t, ok)
+	assert.Len(t, table.sets[nftHostportsMap], 1)
+}
+
+func TestNftablesHostportManagerReconcile(t *testing.T) {
+	nft := newFakeNftables()
+	manager := newFakeNftHostportManager(nft, IPv4, newFakeSocketManager())
+	mappings := []*PodPortMapping{}
+	for _, tc := range hostportManagerTestCases() {
+		if !tc.expectError {
+			require.NoError(t, manager.Add("id", tc.mapping, "cbr0"))
+			mappings = append(mappings, tc.mapping)
+		}
+	}
+	stale := &PodPortMapping{
+		Name:         "stale",
+		Namespace:    "ns1",
+		IP:           net.ParseIP("10.1.1.9"),
+		PortMappings: []*PortMapping{{HostPort: 7000, ContainerPort: 70, Protocol: v1.ProtocolTCP}},
+	}
+	require.NoError(t, manager.Add("stale", stale, ""))
+
+	// Drop the rules of a single hostport and the whole hostports map
+	chain := getNftHostportChain("id", mappings[0].PortMappings[0])
+	require.NoError(t, nft.Run("flush chain ip "+nftHostportsTable+" "+chain))
+	nft.tables["ip "+nftHostportsTable].sets[nftHostIPHostportsMap] = map[string]string{}
+
+	result, err := manager.Reconcile(map[string][]*PodPortMapping{"id": mappings})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id"}, result.Readded)
+	assert.Equal(t, []string{getNftHostportChain("stale", stale.PortMappings[0])}, result.Removed)
+
+	table := nft.tables["ip "+nftHostportsTable]
+	assert.Len(t, table.chains[chain], 2)
+	assert.Len(t, table.sets[nftHostportsMap], 6)
+	assert.Len(t, table.sets[nftHostIPHostportsMap], 2)
+	assert.NotContains(t, table.sets[nftHostportsMap], "tcp . 7000")
+
+	// nothing to do without drift
+	result, err = manager.Reconcile(map[string][]*PodPortMapping{"id": mappings})
+	require.NoError(t, err)
+	assert.Empty(t, result.Readded)
+	assert.Empty(t, result.Removed)
+}
@@ -0,0 +1,33 @@
+package hostport
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNftJSON(t *testing.T) {
+	output := `{"nftables": [
+  {"metainfo": {"version": "1.0.6", "release_name": "Lester Gooch #5", "json_schema_version": 1}},
+  {"table": {"family": "ip", "name": "crio-hostports", "handle": 3}},
+  {"map": {"family": "ip", "name": "hostports", "table": "crio-hostports", "type": ["inet_proto", "inet_service"], "handle": 1, "map": "verdict",
+    "elem": [[{"concat": ["tcp", 8080]}, {"jump": {"target": "hp-IJHALPHTORMHHPPK"}}]]}},
+  {"map": {"family": "ip", "name": "hostip-hostports", "table": "crio-hostports", "type": ["ipv4_addr", "inet_proto", "inet_service"], "handle": 2, "map": "verdict"}},
+  {"chain": {"family": "ip", "table": "crio-hostports", "name": "hp-IJHALPHTORMHHPPK", "handle": 4}},
+  {"chain": {"family": "ip", "table": "crio-hostports", "name": "hp-63UPIDJXVRSZGSUZ", "handle": 5}},
+  {"rule": {"family": "ip", "table": "crio-hostports", "chain": "hp-IJHALPHTORMHHPPK", "handle": 6, "expr": []}},
+  {"rule": {"family": "ip", "table": "crio-hostports", "chain": "hp-IJHALPHTORMHHPPK", "handle": 7, "expr": []}}
+]}`
+
+	content, err := parseNftJSON([]byte(output))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{
+		"hp-IJHALPHTORMHHPPK": 2,
+		"hp-63UPIDJXVRSZGSUZ": 0,
+	}, content.chains)
+	assert.Equal(t, map[string]map[string]string{
+		nftHostportsMap:       {"tcp . 8080": "jump hp-IJHALPHTORMHHPPK"},
+		nftHostIPHostportsMap: {},
+	}, content.maps)
+}
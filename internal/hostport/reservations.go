@@ -0,0 +1,153 @@
+package hostport
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// Reservation is a host port reserved by a pod.
+type Reservation struct {
+	HostPort int32
+	Protocol v1.Protocol
+	// HostIP is the IP the host port is bound to, whereas an empty string
+	// refers to all IPs of both IP families.
+	HostIP  string
+	PodID   string
+	PodName string
+}
+
+// ConflictError is returned if a host port is already reserved by another
+// pod.
+type ConflictError struct {
+	// Requested is the reservation which could not be made.
+	Requested Reservation
+	// Owner is the existing reservation of the other pod.
+	Owner Reservation
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("host port %s is already reserved by pod %s(%s)",
+		e.Requested.String(), e.Owner.PodName, e.Owner.PodID)
+}
+
+// String returns the host port in the form `HOSTIP:PORT/PROTOCOL`.
+func (r *Reservation) String() string {
+	hostIP := r.HostIP
+	if hostIP == "" {
+		hostIP = "*"
+	}
+	return fmt.Sprintf("%s/%s", net.JoinHostPort(hostIP, fmt.Sprint(r.HostPort)), r.Protocol)
+}
+
+// conflicts returns true if both reservations cannot be bound at the same
+// time.
+func (r *Reservation) conflicts(other *Reservation) bool {
+	if r.HostPort != other.HostPort || r.Protocol != other.Protocol {
+		return false
+	}
+	if r.HostIP == "" || other.HostIP == "" {
+		return true
+	}
+	ip, otherIP := net.ParseIP(r.HostIP), net.ParseIP(other.HostIP)
+	if ip == nil || otherIP == nil {
+		return r.HostIP == other.HostIP
+	}
+	if (ip.To4() == nil) != (otherIP.To4() == nil) {
+		return false
+	}
+	return ip.IsUnspecified() || otherIP.IsUnspecified() || ip.Equal(otherIP)
+}
+
+// Reservations is an index of the host ports reserved by pods. It is used to
+// detect conflicting host ports before setting up the network of a pod.
+type Reservations struct {
+	mu sync.Mutex
+	// byPod maps the pod IDs to their reservations
+	byPod map[string][]*Reservation
+}
+
+// NewReservations creates an empty host port reservation index.
+func NewReservations() *Reservations {
+	return &Reservations{byPod: make(map[string][]*Reservation)}
+}
+
+// Reserve reserves the host ports of the port mappings for the pod. Either all
+// host ports get reserved or none of them, whereas a *ConflictError is
+// returned if one of them is already reserved by another pod. Reserving the
+// host ports of a pod again replaces its previous reservations.
+func (r *Reservations) Reserve(podID, podName string, portMappings []*PortMapping) error {
+	requested := make([]*Reservation, 0, len(portMappings))
+	for _, pm := range portMappings {
+		if pm.HostPort <= 0 {
+			continue
+		}
+		protocol := pm.Protocol
+		if protocol == "" {
+			protocol = v1.ProtocolTCP
+		}
+		requested = append(requested, &Reservation{
+			HostPort: pm.HostPort,
+			Protocol: protocol,
+			HostIP:   pm.HostIP,
+			PodID:    podID,
+			PodName:  podName,
+		})
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, reservations := range r.byPod {
+		if id == podID {
+			continue
+		}
+		for _, owner := range reservations {
+			for _, req := range requested {
+				if req.conflicts(owner) {
+					return &ConflictError{Requested: *req, Owner: *owner}
+				}
+			}
+		}
+	}
+
+	if len(requested) == 0 {
+		delete(r.byPod, podID)
+		return nil
+	}
+	r.byPod[podID] = requested
+	return nil
+}
+
+// Release releases all host ports reserved by the pod.
+func (r *Reservations) Release(podID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.byPod, podID)
+}
+
+// List returns all reservations sorted by host port, protocol and host IP.
+func (r *Reservations) List() []Reservation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	res := []Reservation{}
+	for _, reservations := range r.byPod {
+		for _, reservation := range reservations {
+			res = append(res, *reservation)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].HostPort != res[j].HostPort {
+			return res[i].HostPort < res[j].HostPort
+		}
+		if res[i].Protocol != res[j].Protocol {
+			return res[i].Protocol < res[j].Protocol
+		}
+		return res[i].HostIP < res[j].HostIP
+	})
+	return res
+}
@@ -0,0 +1,93 @@
+package hostport
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestReservations(t *testing.T) {
+	r := NewReservations()
+
+	require.NoError(t, r.Reserve("id1", "pod1", []*PortMapping{
+		{HostPort: 8080, ContainerPort: 80, Protocol: v1.ProtocolTCP},
+		{HostPort: 8081, ContainerPort: 81, Protocol: v1.ProtocolUDP, HostIP: "127.0.0.1"},
+		{HostPort: 8082, ContainerPort: 82, Protocol: v1.ProtocolTCP, HostIP: "::1"},
+	}))
+
+	testCases := []struct {
+		name         string
+		portMappings []*PortMapping
+		conflict     bool
+	}{
+		{
+			name:         "same port on all IPs",
+			portMappings: []*PortMapping{{HostPort: 8080, Protocol: v1.ProtocolTCP}},
+			conflict:     true,
+		},
+		{
+			name:         "same port on a specific IP",
+			portMappings: []*PortMapping{{HostPort: 8080, Protocol: v1.ProtocolTCP, HostIP: "10.0.0.1"}},
+			conflict:     true,
+		},
+		{
+			name:         "same port with another protocol",
+			portMappings: []*PortMapping{{HostPort: 8080, Protocol: v1.ProtocolUDP}},
+		},
+		{
+			name:         "same port on another IP",
+			portMappings: []*PortMapping{{HostPort: 8081, Protocol: v1.ProtocolUDP, HostIP: "127.0.0.2"}},
+		},
+		{
+			name:         "same port on the IPv4 wildcard",
+			portMappings: []*PortMapping{{HostPort: 8081, Protocol: v1.ProtocolUDP, HostIP: "0.0.0.0"}},
+			conflict:     true,
+		},
+		{
+			name:         "same port on the IPv4 wildcard with an IPv6 reservation",
+			portMappings: []*PortMapping{{HostPort: 8082, Protocol: v1.ProtocolTCP, HostIP: "0.0.0.0"}},
+		},
+		{
+			name:         "same port on the IPv6 wildcard",
+			portMappings: []*PortMapping{{HostPort: 8082, Protocol: v1.ProtocolTCP, HostIP: "::"}},
+			conflict:     true,
+		},
+	}
+	for _, tc := range testCases {
+		err := r.Reserve("id2", "pod2", tc.portMappings)
+		if !tc.conflict {
+			assert.NoError(t, err, tc.name)
+			r.Release("id2")
+			continue
+		}
+		var conflictErr *ConflictError
+		require.True(t, errors.As(err, &conflictErr), tc.name)
+		assert.Equal(t, "id1", conflictErr.Owner.PodID, tc.name)
+		assert.Equal(t, "pod1", conflictErr.Owner.PodName, tc.name)
+		assert.Contains(t, err.Error(), "pod1(id1)", tc.name)
+	}
+
+	// Conflicting reservations are rejected as a whole
+	assert.Error(t, r.Reserve("id2", "pod2", []*PortMapping{
+		{HostPort: 9090, Protocol: v1.ProtocolTCP},
+		{HostPort: 8080, Protocol: v1.ProtocolTCP},
+	}))
+	assert.Len(t, r.List(), 3)
+
+	// Reserving the same pod again replaces its reservations
+	require.NoError(t, r.Reserve("id1", "pod1", []*PortMapping{
+		{HostPort: 8080, ContainerPort: 80},
+	}))
+	assert.Equal(t, []Reservation{
+		{HostPort: 8080, Protocol: v1.ProtocolTCP, PodID: "id1", PodName: "pod1"},
+	}, r.List())
+
+	r.Release("id1")
+	assert.Empty(t, r.List())
+	assert.NoError(t, r.Reserve("id2", "pod2", []*PortMapping{
+		{HostPort: 8080, Protocol: v1.ProtocolTCP},
+	}))
+}
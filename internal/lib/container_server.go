@@ -13,6 +13,7 @@ import (
 	cstorage "github.com/containers/storage"
 	"github.com/containers/storage/pkg/ioutils"
 	"github.com/containers/storage/pkg/truncindex"
+	"github.com/cri-o/cri-o/internal/config/mcs"
 	"github.com/cri-o/cri-o/internal/hostport"
 	"github.com/cri-o/cri-o/internal/lib/sandbox"
 	statsserver "github.com/cri-o/cri-o/internal/lib/stats"
@@ -24,6 +25,7 @@ import (
 	libconfig "github.com/cri-o/cri-o/pkg/config"
 	json "github.com/json-iterator/go"
 	rspec "github.com/opencontainers/runtime-spec/specs-go"
+	selinux "github.com/opencontainers/selinux/go-selinux"
 	"github.com/opencontainers/selinux/go-selinux/label"
 	"github.com/sirupsen/logrus"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
@@ -50,6 +52,8 @@ type ContainerServer struct {
 	stateLock sync.Locker
 	state     *containerServerState
 	config    *libconfig.Config
+
+	mcsAllocator *mcs.Allocator
 }
 
 // Runtime returns the oci runtime for the ContainerServer
@@ -138,6 +142,12 @@ func New(ctx context.Context, configIface libconfig.Iface) (*ContainerServer, er
 		},
 		config: config,
 	}
+	if config.SELinux && config.SELinuxMCSAllocationFile != "" && selinux.GetEnabled() {
+		c.mcsAllocator, err = mcs.New(config.SELinuxMCSAllocationFile)
+		if err != nil {
+			return nil, err
+		}
+	}
 	c.StatsServer = statsserver.New(c)
 	return c, nil
 }
@@ -205,6 +215,7 @@ func (c *ContainerServer) LoadSandbox(ctx context.Context, id string) (sb *sandb
 		return nil, err
 	}
 	sb.AddHostnamePath(m.Annotations[annotations.HostnamePath])
+	sb.AddHostsPath(m.Annotations[crioann.HostsPathAnnotation])
 	sb.SetSeccompProfilePath(spp)
 	sb.SetNamespaceOptions(&nsOpts)
 
@@ -318,6 +329,7 @@ func (c *ContainerServer) LoadSandbox(ctx context.Context, id string) (sb *sandb
 	if err := label.ReserveLabel(processLabel); err != nil {
 		return sb, err
 	}
+	c.ReserveMCSLevel(ctx, id, processLabel)
 
 	if err := c.ctrIDIndex.Add(scontainer.ID()); err != nil {
 		return sb, err
@@ -578,6 +590,9 @@ func (c *ContainerServer) AddContainer(ctx context.Context, ctr *oci.Container)
 	}
 	newSandbox.AddContainer(ctx, ctr)
 	c.state.containers.Add(ctr.ID(), ctr)
+	if spec := ctr.Spec(); spec.Process != nil && spec.Process.ApparmorProfile != "" {
+		c.config.AppArmor().AddProfileUser(ctr.ID(), spec.Process.ApparmorProfile)
+	}
 }
 
 // AddInfraContainer adds a container to the container state store
@@ -617,6 +632,7 @@ func (c *ContainerServer) RemoveContainer(ctx context.Context, ctr *oci.Containe
 		log.Errorf(ctx, "Failed to remove container %s PID namespace: %v", ctr.ID(), err)
 	}
 	c.state.containers.Delete(ctr.ID())
+	c.config.AppArmor().RemoveProfileUser(ctr.ID())
 }
 
 // RemoveInfraContainer removes a container from the container state store
@@ -0,0 +1,32 @@
+package lib
+
+import (
+	"context"
+
+	"github.com/cri-o/cri-o/internal/config/mcs"
+	"github.com/cri-o/cri-o/internal/log"
+	selinux "github.com/opencontainers/selinux/go-selinux"
+)
+
+// MCSAllocator returns the allocator of the SELinux MCS levels of pods, which
+// is nil if the levels are allocated by the SELinux library.
+func (c *ContainerServer) MCSAllocator() *mcs.Allocator {
+	return c.mcsAllocator
+}
+
+// ReserveMCSLevel records the MCS level of the process label of a sandbox,
+// which has not been allocated by the MCS allocator, and reports conflicts
+// with allocated levels.
+func (c *ContainerServer) ReserveMCSLevel(ctx context.Context, id, processLabel string) {
+	if c.mcsAllocator == nil || processLabel == "" {
+		return
+	}
+	context, err := selinux.NewContext(processLabel)
+	if err != nil {
+		log.Warnf(ctx, "Unable to parse process label of sandbox %s: %v", id, err)
+		return
+	}
+	if err := c.mcsAllocator.Reserve(id, context["level"]); err != nil {
+		log.Warnf(ctx, "Unable to reserve MCS level of sandbox %s: %v", id, err)
+	}
+}
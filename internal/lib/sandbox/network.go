@@ -0,0 +1,123 @@
+package sandbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	cnicurrent "github.com/containernetworking/cni/pkg/types/100"
+	"github.com/cri-o/cri-o/internal/log"
+)
+
+// sbNetworkResultsFilename is the file in the infra container's persistent
+// dir which caches the CNI results of the sandbox over restarts
+var sbNetworkResultsFilename = "network-results.json"
+
+// NetworkResult is the CNI result of a network attachment of the sandbox.
+type NetworkResult struct {
+	// Network is the name of the CNI network.
+	Network string `json:"network"`
+	// Interface is the name of the interface inside the sandbox.
+	Interface string `json:"interface"`
+	// Result is the result returned by the CNI plugin.
+	Result *cnicurrent.Result `json:"result"`
+}
+
+// networkInterface converts the CNI result into the network interface inside
+// the sandbox.
+func (r *NetworkResult) networkInterface() NetworkInterface {
+	networkInterface := NetworkInterface{
+		Network:   r.Network,
+		Interface: r.Interface,
+	}
+	if r.Result == nil {
+		return networkInterface
+	}
+	for _, iface := range r.Result.Interfaces {
+		if iface.Name == r.Interface && iface.Sandbox != "" {
+			networkInterface.Mac = iface.Mac
+			break
+		}
+	}
+	for _, ipConfig := range r.Result.IPs {
+		networkInterface.IPs = append(networkInterface.IPs, ipConfig.Address.IP.String())
+		if ipConfig.Gateway != nil {
+			networkInterface.Gateways = append(networkInterface.Gateways, ipConfig.Gateway.String())
+		}
+	}
+	return networkInterface
+}
+
+// SetNetworkResults sets the CNI results of the sandbox and the network
+// interfaces derived from them, whereas the first result belongs to the
+// default network
+func (s *Sandbox) SetNetworkResults(results []NetworkResult) {
+	s.networkResults = results
+	networkInterfaces := make([]NetworkInterface, 0, len(results))
+	for i := range results {
+		networkInterfaces = append(networkInterfaces, results[i].networkInterface())
+	}
+	s.networkInterfaces = networkInterfaces
+}
+
+// NetworkResults returns the CNI results of the sandbox
+func (s *Sandbox) NetworkResults() []NetworkResult {
+	return s.networkResults
+}
+
+// SaveNetworkResults writes the CNI results of the sandbox into the infra
+// container's persistent dir, which allows restoring them without calling the
+// CNI plugin again after a restart
+func (s *Sandbox) SaveNetworkResults(ctx context.Context) error {
+	_, span := log.StartSpan(ctx)
+	defer span.End()
+	infra := s.InfraContainer()
+	if infra == nil {
+		return fmt.Errorf("sandbox %s has no infra container", s.ID())
+	}
+	data, err := json.Marshal(s.networkResults)
+	if err != nil {
+		return fmt.Errorf("marshal network results: %w", err)
+	}
+	// write to a temporary file first to never leave a truncated cache behind
+	path := filepath.Join(infra.Dir(), sbNetworkResultsFilename)
+	if err := os.WriteFile(path+".tmp", data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// RestoreNetworkResults reads the CNI results written by SaveNetworkResults.
+// An error satisfying errors.Is(err, os.ErrNotExist) is returned if the
+// results were never saved.
+func (s *Sandbox) RestoreNetworkResults() error {
+	infra := s.InfraContainer()
+	if infra == nil {
+		return fmt.Errorf("sandbox %s has no infra container: %w", s.ID(), os.ErrNotExist)
+	}
+	data, err := os.ReadFile(filepath.Join(infra.Dir(), sbNetworkResultsFilename))
+	if err != nil {
+		return err
+	}
+	results := []NetworkResult{}
+	if err := json.Unmarshal(data, &results); err != nil {
+		return fmt.Errorf("unmarshal network results: %w", err)
+	}
+	s.SetNetworkResults(results)
+	return nil
+}
+
+// removeNetworkResults removes the cached CNI results of the sandbox
+func (s *Sandbox) removeNetworkResults() error {
+	s.networkResults = nil
+	infra := s.InfraContainer()
+	if infra == nil {
+		return nil
+	}
+	if err := os.Remove(filepath.Join(infra.Dir(), sbNetworkResultsFilename)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
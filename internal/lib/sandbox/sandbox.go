@@ -49,13 +49,17 @@ type Sandbox struct {
 	runtimeHandler string
 	resolvPath     string
 	hostnamePath   string
+	hostsPath      string
 	hostname       string
 	// ipv4 or ipv6 cache
 	ips                []string
+	networkInterfaces  []NetworkInterface
+	networkResults     []NetworkResult
 	seccompProfilePath string
 	infraContainer     *oci.Container
 	nsOpts             *types.NamespaceOption
 	dnsConfig          *types.DNSConfig
+	dnsWarnings        []string
 	stopMutex          sync.RWMutex
 	created            bool
 	stopped            bool
@@ -66,6 +70,21 @@ type Sandbox struct {
 	containerEnvPath   string
 }
 
+// NetworkInterface is a network interface of the sandbox attached to a CNI
+// network.
+type NetworkInterface struct {
+	// Network is the name of the CNI network.
+	Network string `json:"network"`
+	// Interface is the name of the interface inside the sandbox.
+	Interface string `json:"interface"`
+	// IPs are the IPs assigned to the interface.
+	IPs []string `json:"ips,omitempty"`
+	// Gateways are the gateways of the IPs.
+	Gateways []string `json:"gateways,omitempty"`
+	// Mac is the hardware address of the interface.
+	Mac string `json:"mac,omitempty"`
+}
+
 // DefaultShmSize is the default shm size
 const DefaultShmSize = 64 * 1024 * 1024
 
@@ -137,6 +156,12 @@ func (s *Sandbox) AddIPs(ips []string) {
 	s.ips = ips
 }
 
+// NetworkInterfaces returns the network interfaces of the sandbox, whereas
+// the first one is attached to the default network
+func (s *Sandbox) NetworkInterfaces() []NetworkInterface {
+	return s.networkInterfaces
+}
+
 // SetNamespaceOptions sets whether the pod is running using host network
 func (s *Sandbox) SetNamespaceOptions(nsOpts *types.NamespaceOption) {
 	s.nsOpts = nsOpts
@@ -157,6 +182,18 @@ func (s *Sandbox) DNSConfig() *types.DNSConfig {
 	return s.dnsConfig
 }
 
+// SetDNSWarnings sets the warnings about the adjustments made to the DNS
+// config of the sandbox
+func (s *Sandbox) SetDNSWarnings(warnings []string) {
+	s.dnsWarnings = warnings
+}
+
+// DNSWarnings returns the warnings about the adjustments made to the DNS
+// config of the sandbox
+func (s *Sandbox) DNSWarnings() []string {
+	return s.dnsWarnings
+}
+
 // StopMutex returns the mutex to use when stopping the sandbox
 func (s *Sandbox) StopMutex() *sync.RWMutex {
 	return &s.stopMutex
@@ -271,6 +308,17 @@ func (s *Sandbox) HostnamePath() string {
 	return s.hostnamePath
 }
 
+// AddHostsPath adds the hosts file path to the sandbox
+func (s *Sandbox) AddHostsPath(hostsPath string) {
+	s.hostsPath = hostsPath
+}
+
+// HostsPath retrieves the hosts file path from a sandbox, which is empty if
+// the sandbox uses the hosts file of the host
+func (s *Sandbox) HostsPath() string {
+	return s.hostsPath
+}
+
 // ContainerEnvPath retrieves the .containerenv path from a sandbox
 func (s *Sandbox) ContainerEnvPath() string {
 	return s.containerEnvPath
@@ -370,7 +418,7 @@ func (s *Sandbox) NetworkStopped() bool {
 // This should be set after a network stop operation succeeds,
 // so we don't double stop the network
 // if createFile is true, it creates a "network-stopped" file
-// in the infra container's persistent dir
+// in the infra container's persistent dir and removes the cached CNI results
 // this is used to track the network is stopped over reboots
 // returns an error if an error occurred when creating the network-stopped file
 func (s *Sandbox) SetNetworkStopped(ctx context.Context, createFile bool) error {
@@ -384,6 +432,9 @@ func (s *Sandbox) SetNetworkStopped(ctx context.Context, createFile bool) error
 		if err := s.createFileInInfraDir(ctx, sbNetworkStoppedFilename); err != nil {
 			return fmt.Errorf("failed to create state file in container directory. Restores may fail: %w", err)
 		}
+		if err := s.removeNetworkResults(); err != nil {
+			log.Warnf(ctx, "Failed to remove cached network results of sandbox %s: %v", s.ID(), err)
+		}
 	}
 	return nil
 }
@@ -2,8 +2,11 @@ package sandbox_test
 
 import (
 	"context"
+	"net"
+	"os"
 	"time"
 
+	cnicurrent "github.com/containernetworking/cni/pkg/types/100"
 	"github.com/cri-o/cri-o/internal/hostport"
 	"github.com/cri-o/cri-o/internal/lib/sandbox"
 	"github.com/cri-o/cri-o/internal/oci"
@@ -101,6 +104,105 @@ var _ = t.Describe("Sandbox", func() {
 		})
 	})
 
+	t.Describe("NetworkResults", func() {
+		var networkResults []sandbox.NetworkResult
+
+		BeforeEach(func() {
+			_, ipv4, err := net.ParseCIDR("10.0.0.2/24")
+			Expect(err).To(BeNil())
+			_, ipv6, err := net.ParseCIDR("fd00::2/64")
+			Expect(err).To(BeNil())
+			ipv4.IP = net.ParseIP("10.0.0.2")
+			ipv6.IP = net.ParseIP("fd00::2")
+			networkResults = []sandbox.NetworkResult{{
+				Network:   "default",
+				Interface: "eth0",
+				Result: &cnicurrent.Result{
+					CNIVersion: cnicurrent.ImplementedSpecVersion,
+					Interfaces: []*cnicurrent.Interface{
+						{Name: "veth1234", Mac: "aa:bb:cc:dd:ee:00"},
+						{Name: "eth0", Mac: "aa:bb:cc:dd:ee:ff", Sandbox: "/var/run/netns/ns"},
+					},
+					IPs: []*cnicurrent.IPConfig{
+						{Address: *ipv4, Gateway: net.ParseIP("10.0.0.1")},
+						{Address: *ipv6},
+					},
+				},
+			}}
+		})
+
+		It("should derive the network interfaces", func() {
+			// Given
+			Expect(testSandbox.NetworkResults()).To(BeEmpty())
+
+			// When
+			testSandbox.SetNetworkResults(networkResults)
+
+			// Then
+			Expect(testSandbox.NetworkResults()).To(Equal(networkResults))
+			Expect(testSandbox.NetworkInterfaces()).To(Equal([]sandbox.NetworkInterface{{
+				Network:   "default",
+				Interface: "eth0",
+				IPs:       []string{"10.0.0.2", "fd00::2"},
+				Gateways:  []string{"10.0.0.1"},
+				Mac:       "aa:bb:cc:dd:ee:ff",
+			}}))
+		})
+
+		It("should save and restore the results", func() {
+			ctx := context.TODO()
+			// Given
+			infra, err := oci.NewContainer("testid", "testname", "",
+				"/container/logs", map[string]string{},
+				map[string]string{}, map[string]string{}, "image",
+				"imageName", "imageRef", &types.ContainerMetadata{},
+				"testsandboxid", false, false, false, "",
+				t.MustTempDir("network-results"), time.Now(), "SIGKILL")
+			Expect(err).To(BeNil())
+			Expect(testSandbox.SetInfraContainer(infra)).To(BeNil())
+			Expect(testSandbox.RestoreNetworkResults()).To(MatchError(os.ErrNotExist))
+			testSandbox.SetNetworkResults(networkResults)
+			Expect(testSandbox.SaveNetworkResults(ctx)).To(BeNil())
+
+			// When
+			restored, err := sandbox.New("sandboxID", "", "", "", "",
+				make(map[string]string), make(map[string]string), "", "",
+				&types.PodSandboxMetadata{}, "", "", false, "", "", "",
+				[]*hostport.PortMapping{}, false, time.Now(), "")
+			Expect(err).To(BeNil())
+			Expect(restored.SetInfraContainer(infra)).To(BeNil())
+			err = restored.RestoreNetworkResults()
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(restored.NetworkInterfaces()).To(Equal(testSandbox.NetworkInterfaces()))
+			Expect(restored.NetworkResults()[0].Result.IPs[0].Address.String()).To(Equal("10.0.0.2/24"))
+		})
+
+		It("should remove the results if the network gets stopped", func() {
+			ctx := context.TODO()
+			// Given
+			infra, err := oci.NewContainer("testid", "testname", "",
+				"/container/logs", map[string]string{},
+				map[string]string{}, map[string]string{}, "image",
+				"imageName", "imageRef", &types.ContainerMetadata{},
+				"testsandboxid", false, false, false, "",
+				t.MustTempDir("network-results"), time.Now(), "SIGKILL")
+			Expect(err).To(BeNil())
+			Expect(testSandbox.SetInfraContainer(infra)).To(BeNil())
+			testSandbox.SetNetworkResults(networkResults)
+			Expect(testSandbox.SaveNetworkResults(ctx)).To(BeNil())
+			testSandbox.SetCreated()
+
+			// When
+			Expect(testSandbox.SetNetworkStopped(ctx, true)).To(BeNil())
+
+			// Then
+			Expect(testSandbox.NetworkResults()).To(BeEmpty())
+			Expect(testSandbox.RestoreNetworkResults()).To(MatchError(os.ErrNotExist))
+		})
+	})
+
 	t.Describe("Stopped", func() {
 		It("should succeed", func() {
 			ctx := context.TODO()
@@ -215,14 +215,19 @@ func (ss *StatsServer) populateNetworkUsage(stats *types.PodSandboxStats, sb *sa
 		stats.Linux.Network = &types.NetworkUsage{
 			Interfaces: make([]*types.NetworkInterfaceUsage, 0, len(links)-1),
 		}
+		// the interface of the default network is known from the CNI
+		// results, whereas the first link is used as a fallback
+		defaultInterfaceName := ""
+		if networkInterfaces := sb.NetworkInterfaces(); len(networkInterfaces) > 0 {
+			defaultInterfaceName = networkInterfaces[0].Interface
+		}
 		for i := range links {
 			iface, err := linkToInterface(links[i])
 			if err != nil {
 				logrus.Errorf("Failed to %v for pod %s", err, sb.ID())
 				continue
 			}
-			// TODO FIXME or DefaultInterfaceName?
-			if i == 0 {
+			if (defaultInterfaceName == "" && i == 0) || iface.Name == defaultInterfaceName {
 				stats.Linux.Network.DefaultInterface = iface
 			} else {
 				stats.Linux.Network.Interfaces = append(stats.Linux.Network.Interfaces, iface)
@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -19,6 +20,7 @@ import (
 	"github.com/cri-o/cri-o/internal/config/nsmgr"
 	"github.com/cri-o/cri-o/internal/log"
 	ann "github.com/cri-o/cri-o/pkg/annotations"
+	"github.com/cri-o/cri-o/pkg/config"
 	json "github.com/json-iterator/go"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/sirupsen/logrus"
@@ -79,6 +81,10 @@ type Container struct {
 	restore            bool
 	restoreArchive     string
 	restoreIsOCIImage  bool
+
+	// stopSignalChainStep is the string of the last executed step of the
+	// stop signal escalation chain, which does not require the opLock.
+	stopSignalChainStep atomic.Value
 }
 
 func (c *Container) CRIAttributes() *types.ContainerAttributes {
@@ -240,6 +246,44 @@ func (c *Container) StopSignal() syscall.Signal {
 	return s
 }
 
+// StopSignalChain returns the stop signal escalation chain of the container,
+// which is empty if the stop signal should be used.
+func (c *Container) StopSignalChain() config.StopSignalChain {
+	chain, err := config.ParseStopSignalChain(c.crioAnnotations[ann.StopSignalChainAnnotation])
+	if err != nil {
+		logrus.Warnf("Ignoring invalid stop signal chain of container %s: %v", c.ID(), err)
+		return nil
+	}
+	return chain
+}
+
+// IDMappingStrategy returns the strategy used for making the volumes of the
+// container accessible from its user namespace, which is empty if the
+// container does not run in a user namespace.
+func (c *Container) IDMappingStrategy() string {
+	return c.crioAnnotations[ann.IDMappingStrategyAnnotation]
+}
+
+// WritablePaths returns the writable paths which got tmpfs mounts in the
+// container, in the form of the "io.kubernetes.cri-o.WritablePaths"
+// annotation.
+func (c *Container) WritablePaths() string {
+	return c.crioAnnotations[ann.AppliedWritablePathsAnnotation]
+}
+
+// StopSignalChainStep returns the last executed step of the stop signal
+// escalation chain, or an empty string if none has been executed.
+func (c *Container) StopSignalChainStep() string {
+	step, _ := c.stopSignalChainStep.Load().(string)
+	return step
+}
+
+// setStopSignalChainStep records the currently executed step of the stop
+// signal escalation chain.
+func (c *Container) setStopSignalChainStep(idx int, chain config.StopSignalChain) {
+	c.stopSignalChainStep.Store(fmt.Sprintf("%d/%d %s", idx+1, len(chain), chain[idx].String()))
+}
+
 // FromDisk restores container's state from disk
 // Calls to FromDisk should always be preceded by call to Runtime.UpdateContainerStatus.
 // This is because FromDisk() initializes the InitStartTime for the saved container state
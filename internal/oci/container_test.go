@@ -6,10 +6,12 @@ import (
 	"os"
 	"path"
 	"strconv"
+	"syscall"
 	"time"
 
 	"github.com/containers/storage/pkg/idtools"
 	"github.com/cri-o/cri-o/internal/oci"
+	crioann "github.com/cri-o/cri-o/pkg/annotations"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/opencontainers/runtime-spec/specs-go"
@@ -228,6 +230,63 @@ var _ = t.Describe("Container", func() {
 		Expect(signal).To(Equal("5"))
 	})
 
+	It("should succeed get the stop signal chain", func() {
+		// Given
+		container, err := oci.NewContainer("", "", "", "",
+			map[string]string{},
+			map[string]string{crioann.StopSignalChainAnnotation: "SIGTERM:10s,SIGINT"},
+			map[string]string{},
+			"", "", "", &types.ContainerMetadata{}, "",
+			false, false, false, "", "", time.Now(), "")
+		Expect(err).To(BeNil())
+		Expect(container).NotTo(BeNil())
+
+		// When
+		chain := container.StopSignalChain()
+
+		// Then
+		Expect(chain).To(HaveLen(2))
+		Expect(chain[0].Signal).To(Equal(syscall.SIGTERM))
+		Expect(chain[0].Timeout).To(Equal(10 * time.Second))
+		Expect(chain[1].Signal).To(Equal(syscall.SIGINT))
+	})
+
+	It("should succeed to get the stop signal chain step", func() {
+		// Given
+		container, err := oci.NewContainer("", "", "", "",
+			map[string]string{},
+			map[string]string{crioann.StopSignalChainAnnotation: "SIGTERM:10s,SIGINT"},
+			map[string]string{},
+			"", "", "", &types.ContainerMetadata{}, "",
+			false, false, false, "", "", time.Now(), "")
+		Expect(err).To(BeNil())
+		Expect(container.StopSignalChainStep()).To(BeEmpty())
+
+		// When
+		container.SetStopSignalChainStep(1, container.StopSignalChain())
+
+		// Then
+		Expect(container.StopSignalChainStep()).To(HavePrefix("2/2 "))
+	})
+
+	It("should ignore an invalid stop signal chain", func() {
+		// Given
+		container, err := oci.NewContainer("", "", "", "",
+			map[string]string{},
+			map[string]string{crioann.StopSignalChainAnnotation: "SIGKILL,SIGTERM"},
+			map[string]string{},
+			"", "", "", &types.ContainerMetadata{}, "",
+			false, false, false, "", "", time.Now(), "")
+		Expect(err).To(BeNil())
+		Expect(container).NotTo(BeNil())
+
+		// When
+		chain := container.StopSignalChain()
+
+		// Then
+		Expect(chain).To(BeEmpty())
+	})
+
 	t.Describe("FromDisk", func() {
 		BeforeEach(func() {
 			Expect(os.MkdirAll(sut.Dir(), 0o755)).To(BeNil())
@@ -6,6 +6,8 @@
 
 package oci
 
+import "github.com/cri-o/cri-o/pkg/config"
+
 // SetState sets the container state
 func (c *Container) SetState(state *ContainerState) {
 	c.state = state
@@ -24,3 +26,8 @@ func (c *Container) SetStateAndSpoofPid(state *ContainerState) {
 	}
 	c.state = state
 }
+
+// SetStopSignalChainStep records the currently executed stop signal chain step
+func (c *Container) SetStopSignalChainStep(idx int, chain config.StopSignalChain) {
+	c.setStopSignalChainStep(idx, chain)
+}
@@ -0,0 +1,97 @@
+package oci
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/server/metrics"
+)
+
+const (
+	execSyncStdout = "stdout"
+	execSyncStderr = "stderr"
+)
+
+// execSyncLogSizeFactor is the factor by which the exec sync log written by
+// conmon may exceed the output limit. The log contains both streams in the CRI
+// log format, which adds a timestamp and stream prefix to every line.
+const execSyncLogSizeFactor = 4
+
+// execSyncLogSizeMax returns the maximum size of the exec sync log written by
+// conmon for the output limit.
+func execSyncLogSizeMax(limit int64) int64 {
+	if limit > math.MaxInt64/execSyncLogSizeFactor {
+		return math.MaxInt64
+	}
+	return limit * execSyncLogSizeFactor
+}
+
+// execSyncTruncatedMarker returns the marker appended to exec sync output
+// which exceeded the provided limit.
+func execSyncTruncatedMarker(limit int64) []byte {
+	return []byte(fmt.Sprintf("\n[output truncated by CRI-O: exceeded %d bytes]\n", limit))
+}
+
+// execSyncBuffer is a writer which buffers up to limit bytes and silently
+// discards the rest. Write never fails, so the exec'd process is not blocked
+// or killed by a short write and still finishes with its own exit code.
+type execSyncBuffer struct {
+	buf       bytes.Buffer
+	limit     int64
+	truncated bool
+}
+
+func newExecSyncBuffer(limit int64) *execSyncBuffer {
+	return &execSyncBuffer{limit: limit}
+}
+
+// Write implements io.Writer.
+func (b *execSyncBuffer) Write(p []byte) (int, error) {
+	remaining := b.limit - int64(b.buf.Len())
+	if int64(len(p)) > remaining {
+		b.truncated = true
+		if remaining > 0 {
+			b.buf.Write(p[:remaining])
+		}
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+// Close implements io.Closer.
+func (b *execSyncBuffer) Close() error {
+	return nil
+}
+
+// Truncated returns true if output got discarded.
+func (b *execSyncBuffer) Truncated() bool {
+	return b.truncated
+}
+
+// Bytes returns the buffered output including the truncation marker if output
+// got discarded.
+func (b *execSyncBuffer) Bytes() []byte {
+	if b.truncated {
+		return append(b.buf.Bytes(), execSyncTruncatedMarker(b.limit)...)
+	}
+	return b.buf.Bytes()
+}
+
+// truncateExecSyncOutput limits the output to limit bytes and appends the
+// truncation marker if required. It returns true if the output got truncated.
+func truncateExecSyncOutput(output []byte, limit int64) ([]byte, bool) {
+	if int64(len(output)) <= limit {
+		return output, false
+	}
+	return append(output[:limit:limit], execSyncTruncatedMarker(limit)...), true
+}
+
+// reportExecSyncTruncation logs and records the truncation of an exec sync
+// output stream of the container.
+func reportExecSyncTruncation(ctx context.Context, c *Container, stream string, limit int64) {
+	log.Warnf(ctx, "Truncated exec sync %s of container %s to %d bytes", stream, c.ID(), limit)
+	metrics.Instance().MetricContainersExecSyncOutputTruncatedInc(c.Name(), stream)
+}
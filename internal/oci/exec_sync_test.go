@@ -0,0 +1,98 @@
+package oci_test
+
+import (
+	"math"
+
+	"github.com/cri-o/cri-o/internal/oci"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = t.Describe("ExecSync", func() {
+	t.Describe("TruncateExecSyncOutput", func() {
+		It("should not truncate output within the limit", func() {
+			// Given
+			output := []byte("abcd")
+
+			// When
+			res, truncated := oci.TruncateExecSyncOutput(output, 4)
+
+			// Then
+			Expect(truncated).To(BeFalse())
+			Expect(res).To(Equal(output))
+		})
+
+		It("should truncate output exceeding the limit", func() {
+			// Given
+			output := []byte("abcdef")
+
+			// When
+			res, truncated := oci.TruncateExecSyncOutput(output, 3)
+
+			// Then
+			Expect(truncated).To(BeTrue())
+			Expect(string(res)).To(HavePrefix("abc\n"))
+			Expect(string(res)).To(ContainSubstring("output truncated"))
+			Expect(string(output)).To(Equal("abcdef"))
+		})
+	})
+
+	t.Describe("ExecSyncBuffer", func() {
+		It("should buffer output within the limit", func() {
+			// Given
+			sut := oci.NewExecSyncBuffer(8)
+
+			// When
+			n, err := sut.Write([]byte("abcd"))
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(n).To(Equal(4))
+			Expect(sut.Truncated()).To(BeFalse())
+			Expect(sut.Bytes()).To(Equal([]byte("abcd")))
+		})
+
+		It("should discard output exceeding the limit without failing", func() {
+			// Given
+			sut := oci.NewExecSyncBuffer(6)
+
+			// When
+			_, err := sut.Write([]byte("abcd"))
+			Expect(err).To(BeNil())
+			n, err := sut.Write([]byte("efgh"))
+			Expect(err).To(BeNil())
+			_, err = sut.Write([]byte("ijkl"))
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(n).To(Equal(4))
+			Expect(sut.Truncated()).To(BeTrue())
+			Expect(string(sut.Bytes())).To(HavePrefix("abcdef\n"))
+			Expect(string(sut.Bytes())).To(ContainSubstring("output truncated"))
+		})
+	})
+
+	t.Describe("ExecSyncLogSizeMax", func() {
+		It("should leave room for the log format of both streams", func() {
+			// Given
+			const limit = 8192
+
+			// When
+			res := oci.ExecSyncLogSizeMax(limit)
+
+			// Then
+			Expect(res).To(BeNumerically(">", 2*limit))
+		})
+
+		It("should not overflow", func() {
+			// Given
+			const limit = math.MaxInt64 / 2
+
+			// When
+			res := oci.ExecSyncLogSizeMax(limit)
+
+			// Then
+			Expect(res).To(BeEquivalentTo(math.MaxInt64))
+		})
+	})
+})
@@ -0,0 +1,28 @@
+//go:build test
+// +build test
+
+// All *_inject.go files are meant to be used by tests only. Purpose of this
+// files is to provide a way to inject mocked data into the current setup.
+
+package oci
+
+import "io"
+
+// TruncateExecSyncOutput truncates the exec sync output to the limit
+func TruncateExecSyncOutput(output []byte, limit int64) ([]byte, bool) {
+	return truncateExecSyncOutput(output, limit)
+}
+
+// NewExecSyncBuffer creates a new exec sync output buffer with the limit
+func NewExecSyncBuffer(limit int64) interface {
+	io.WriteCloser
+	Bytes() []byte
+	Truncated() bool
+} {
+	return newExecSyncBuffer(limit)
+}
+
+// ExecSyncLogSizeMax returns the maximum size of the conmon exec sync log
+func ExecSyncLogSizeMax(limit int64) int64 {
+	return execSyncLogSizeMax(limit)
+}
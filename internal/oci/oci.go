@@ -34,11 +34,6 @@ const (
 	// killContainerTimeout is the timeout that we wait for the container to
 	// be SIGKILLed.
 	killContainerTimeout = 2 * time.Minute
-
-	// maxExecSyncSize is the maximum size of exec sync output CRI-O will process.
-	// It is set to the amount of logs allowed in the dockershim implementation:
-	// https://github.com/kubernetes/kubernetes/pull/82514
-	maxExecSyncSize = 16 * 1024 * 1024
 )
 
 // Runtime is the generic structure holding both global and specific
@@ -157,6 +152,19 @@ func (r *Runtime) AllowedAnnotations(handler string) ([]string, error) {
 	return rh.AllowedAnnotations, nil
 }
 
+// ValidateSpecForRuntimeHandler returns an error if the provided spec relies on
+// features not supported by the runtime handler.
+func (r *Runtime) ValidateSpecForRuntimeHandler(handler string, spec *rspec.Spec) error {
+	rh, err := r.getRuntimeHandler(handler)
+	if err != nil {
+		return err
+	}
+	if err := rh.RuntimeFeatures().ValidateSpec(spec); err != nil {
+		return fmt.Errorf("runtime handler %q: %w", handler, err)
+	}
+	return nil
+}
+
 // RuntimeType returns the type of runtimeHandler
 // This is needed when callers need to do specific work for oci vs vm
 // containers, like monitor an oci container's conmon.
@@ -176,7 +184,7 @@ func (r *Runtime) newRuntimeImpl(c *Container) (RuntimeImpl, error) {
 	}
 
 	if rh.RuntimeType == config.RuntimeTypeVM {
-		return newRuntimeVM(rh.RuntimePath, rh.RuntimeRoot, rh.RuntimeConfigPath, r.config.RuntimeConfig.ContainerExitsDir), nil
+		return newRuntimeVM(rh.RuntimePath, rh.RuntimeRoot, rh.RuntimeConfigPath, r.config.RuntimeConfig.ContainerExitsDir, r.config.ExecSyncOutputSizeMax), nil
 	}
 
 	if rh.RuntimeType == config.RuntimeTypePod {
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net"
 	"os"
 	"os/exec"
@@ -538,7 +539,7 @@ func (r *runtimeOCI) ExecSyncContainer(ctx context.Context, c *Container, comman
 		args = append(args, "--sync")
 	}
 	if r.config.ConmonSupportsLogGlobalSizeMax() {
-		args = append(args, "--log-global-size-max", strconv.Itoa(maxExecSyncSize))
+		args = append(args, "--log-global-size-max", strconv.FormatInt(execSyncLogSizeMax(r.config.ExecSyncOutputSizeMax), 10))
 	}
 	if c.terminal {
 		args = append(args, "-t")
@@ -718,7 +719,9 @@ func (r *runtimeOCI) ExecSyncContainer(ctx context.Context, c *Container, comman
 	// ExecSyncResponse we have to read the logfile.
 	// XXX: Currently runC dups the same console over both stdout and stderr,
 	//      so we can't differentiate between the two.
-	logBytes, err := TruncateAndReadFile(ctx, logPath, maxExecSyncSize)
+	limit := r.config.ExecSyncOutputSizeMax
+	logSizeMax := execSyncLogSizeMax(limit)
+	logBytes, err := TruncateAndReadFile(ctx, logPath, logSizeMax)
 	if err != nil {
 		return nil, &ExecSyncError{
 			Stdout:   stdoutBuf,
@@ -730,6 +733,23 @@ func (r *runtimeOCI) ExecSyncContainer(ctx context.Context, c *Container, comman
 
 	// We have to parse the log output into {stdout, stderr} buffers.
 	stdoutBytes, stderrBytes := parseLog(ctx, logBytes)
+
+	stdoutBytes, stdoutTruncated := truncateExecSyncOutput(stdoutBytes, limit)
+	stderrBytes, stderrTruncated := truncateExecSyncOutput(stderrBytes, limit)
+
+	// The log file is capped by conmon or by truncating it, which means that
+	// reaching its limit indicates that output got lost. It is not possible to
+	// tell which stream exceeded, so the marker gets added to stdout.
+	if !stdoutTruncated && int64(len(logBytes)) >= logSizeMax {
+		stdoutBytes = append(stdoutBytes, execSyncTruncatedMarker(limit)...)
+		stdoutTruncated = true
+	}
+	if stdoutTruncated {
+		reportExecSyncTruncation(ctx, c, execSyncStdout, limit)
+	}
+	if stderrTruncated {
+		reportExecSyncTruncation(ctx, c, execSyncStderr, limit)
+	}
 	return &types.ExecSyncResponse{
 		Stdout:   stdoutBytes,
 		Stderr:   stderrBytes,
@@ -904,10 +924,19 @@ func (r *runtimeOCI) StopContainer(ctx context.Context, c *Container, timeout in
 	}
 
 	if timeout > 0 {
-		if _, err := r.runtimeCmd("kill", c.ID(), c.GetStopSignal()); err != nil {
-			checkProcessGone(c)
+		var err error
+		if chain := c.StopSignalChain(); len(chain) > 0 {
+			// release the lock while running the chain, which may exec
+			// commands in the container and should not block status requests
+			c.opLock.Unlock()
+			err = r.runStopSignalChain(ctx, c, chain, time.Duration(timeout)*time.Second)
+			c.opLock.Lock()
+		} else {
+			if _, err := r.runtimeCmd("kill", c.ID(), c.GetStopSignal()); err != nil {
+				checkProcessGone(c)
+			}
+			err = WaitContainerStop(ctx, c, time.Duration(timeout)*time.Second, true)
 		}
-		err := WaitContainerStop(ctx, c, time.Duration(timeout)*time.Second, true)
 		if err == nil {
 			return nil
 		}
@@ -921,6 +950,98 @@ func (r *runtimeOCI) StopContainer(ctx context.Context, c *Container, timeout in
 	return WaitContainerStop(ctx, c, killContainerTimeout, false)
 }
 
+// runStopSignalChain carries out the stop signal escalation chain of the
+// container within the grace period. A shorter grace period sent along the
+// stopTimeoutChan is respected. It returns an error if the container did not
+// exit before the chain got exhausted.
+func (r *runtimeOCI) runStopSignalChain(ctx context.Context, c *Container, chain config.StopSignalChain, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for i := range chain {
+		step := &chain[i]
+		c.setStopSignalChainStep(i, chain)
+		log.Debugf(ctx, "Stopping container %s with step %s", c.ID(), c.StopSignalChainStep())
+
+		stepEnd := deadline
+		if step.Timeout > 0 {
+			if end := time.Now().Add(step.Timeout); end.Before(stepEnd) {
+				stepEnd = end
+			}
+		}
+
+		if len(step.Exec) > 0 {
+			// Use the generic runtime to pick the right exec implementation,
+			// for example conmon-rs for pod runtimes.
+			res, err := r.Runtime.ExecSyncContainer(ctx, c, step.Exec, stopSignalChainExecTimeout(stepEnd))
+			if err != nil {
+				log.Warnf(ctx, "Failed to run stop command %v in container %s: %v", step.Exec, c.ID(), err)
+			} else if res != nil && res.ExitCode != 0 {
+				log.Warnf(ctx, "Stop command %v in container %s exited with %d", step.Exec, c.ID(), res.ExitCode)
+			}
+		} else if _, err := r.runtimeCmd("kill", c.ID(), strconv.Itoa(int(step.Signal))); err != nil {
+			checkProcessGone(c)
+		}
+
+		exited, err := waitContainerStopUntil(ctx, c, stepEnd, &deadline)
+		if err != nil {
+			return err
+		}
+		if exited {
+			return nil
+		}
+		if !time.Now().Before(deadline) {
+			break
+		}
+	}
+	return fmt.Errorf("stop signal chain %s exhausted without the container process exiting", chain)
+}
+
+// waitContainerStopUntil waits for the container process to exit until the
+// step end or the deadline is reached, whichever is earlier. New timeouts sent
+// along the stopTimeoutChan can move the deadline forward.
+func waitContainerStopUntil(ctx context.Context, c *Container, stepEnd time.Time, deadline *time.Time) (exited bool, err error) {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		if err := c.verifyPid(); err != nil {
+			// The initial container process either doesn't exist, or isn't ours.
+			if !errors.Is(err, ErrNotFound) {
+				log.Warnf(ctx, "Failed to find process for container %s: %v", c.ID(), err)
+			}
+			return true, nil
+		}
+
+		end := stepEnd
+		if deadline.Before(end) {
+			end = *deadline
+		}
+		if !time.Now().Before(end) {
+			return false, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case newTimeout := <-c.stopTimeoutChan:
+			// Only accept earlier deadlines, like WaitContainerStop does.
+			if newDeadline := time.Now().Add(newTimeout); newDeadline.Before(*deadline) {
+				*deadline = newDeadline
+			}
+		case <-ticker.C:
+		}
+	}
+}
+
+// stopSignalChainExecTimeout returns the timeout in seconds of a stop signal
+// chain exec step ending at the provided time. It is at least one second,
+// because zero disables the timeout.
+func stopSignalChainExecTimeout(end time.Time) int64 {
+	timeout := int64(math.Ceil(time.Until(end).Seconds()))
+	if timeout < 1 {
+		return 1
+	}
+	return timeout
+}
+
 func checkProcessGone(c *Container) {
 	if err := c.verifyPid(); err != nil {
 		// The initial container process either doesn't exist, or isn't ours.
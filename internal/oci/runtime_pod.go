@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
 	"path/filepath"
 	"syscall"
 
@@ -60,7 +61,7 @@ func newRuntimePod(r *Runtime, handler *config.RuntimeHandler, c *Container) (Ru
 	client, err := conmonClient.New(&conmonClient.ConmonServerConfig{
 		ConmonServerPath: handler.MonitorPath,
 		LogLevel:         conmonClient.FromLogrusLevel(logrus.GetLevel()),
-		LogDriver:        conmonClient.LogDriverSystemd,
+		LogDriver:        serverLogDriver(),
 		Runtime:          handler.RuntimePath,
 		ServerRunDir:     c.dir,
 		RuntimeRoot:      runRoot,
@@ -76,7 +77,8 @@ func newRuntimePod(r *Runtime, handler *config.RuntimeHandler, c *Container) (Ru
 	}
 	logrus.Debugf("Running conmonrs with PID: %d", client.PID())
 
-	// TODO FIXME we need to move conmon-rs to the new cgroup
+	// conmon-rs gets moved into the configured monitor cgroup (or the pod
+	// cgroup) as soon as the infra container is created, see CreateContainer.
 	return &runtimePod{
 		oci: &runtimeOCI{
 			Runtime: r,
@@ -88,6 +90,20 @@ func newRuntimePod(r *Runtime, handler *config.RuntimeHandler, c *Container) (Ru
 	}, nil
 }
 
+// journaldSocketPath is the socket used by conmon-rs for its systemd log
+// driver.
+const journaldSocketPath = "/run/systemd/journal/socket"
+
+// serverLogDriver returns the log driver for the conmon-rs server itself. It
+// logs to the journal if available and falls back to CRI-O's stdout
+// otherwise, for example if CRI-O runs inside a container.
+func serverLogDriver() conmonClient.LogDriver {
+	if _, err := os.Stat(journaldSocketPath); err == nil {
+		return conmonClient.LogDriverSystemd
+	}
+	return conmonClient.LogDriverStdout
+}
+
 func (r *runtimePod) CreateContainer(ctx context.Context, c *Container, cgroupParent string, restore bool) error {
 	// If this container is the infra container or spoofed,
 	// then it is the pod container and we move conmonrs
@@ -108,18 +124,19 @@ func (r *runtimePod) CreateContainer(ctx context.Context, c *Container, cgroupPa
 			v.Version, v.Tag, v.Commit, v.BuildDate, v.Target, v.RustVersion, v.CargoVersion,
 		)
 
-		// Platform specific container setup
+		// Platform specific container setup. This moves conmon-rs into the
+		// monitor cgroup configured for the runtime handler, which is the
+		// pod cgroup if `monitor_cgroup = "pod"`.
 		if err := r.oci.createContainerPlatform(c, cgroupParent, int(v.ProcessID)); err != nil {
+			if shutdownErr := r.client.Shutdown(); shutdownErr != nil {
+				log.Warnf(ctx, "Unable to shutdown conmonrs after failed cgroup setup: %v", shutdownErr)
+			}
 			return fmt.Errorf("create container for platform: %w", err)
 		}
 	}
 	if c.Spoofed() {
 		return nil
 	}
-	var maxSize uint64
-	if r.oci.config.LogSizeMax >= 0 {
-		maxSize = uint64(r.oci.config.LogSizeMax)
-	}
 	createConfig := &conmonClient.CreateContainerConfig{
 		ID:           c.ID(),
 		BundlePath:   c.bundlePath,
@@ -127,13 +144,10 @@ func (r *runtimePod) CreateContainer(ctx context.Context, c *Container, cgroupPa
 		Stdin:        c.stdin,
 		ExitPaths:    []string{filepath.Join(r.oci.config.ContainerExitsDir, c.ID()), c.exitFilePath()},
 		OOMExitPaths: []string{filepath.Join(c.bundlePath, "oom")}, // Keep in sync with location in oci.UpdateContainerStatus()
-		LogDrivers: []conmonClient.ContainerLogDriver{
-			{
-				Type:    conmonClient.LogDriverTypeContainerRuntimeInterface,
-				Path:    c.logPath,
-				MaxSize: maxSize,
-			},
-		},
+		LogDrivers:   r.containerLogDrivers(c),
+	}
+	if r.oci.config.NoPivot {
+		createConfig.CommandArgs = append(createConfig.CommandArgs, "--no-pivot")
 	}
 	resp, err := r.client.CreateContainer(ctx, createConfig)
 	// TODO FIXME do we need to cleanup the container?
@@ -147,6 +161,25 @@ func (r *runtimePod) CreateContainer(ctx context.Context, c *Container, cgroupPa
 	return nil
 }
 
+// containerLogDrivers returns the conmon-rs log drivers for the provided
+// container, matching the logging behavior of conmon.
+func (r *runtimePod) containerLogDrivers(c *Container) []conmonClient.ContainerLogDriver {
+	var maxSize uint64
+	if r.oci.config.LogSizeMax >= 0 {
+		maxSize = uint64(r.oci.config.LogSizeMax)
+	}
+	// runtime handlers of the pod runtime type are not available with
+	// log_to_journald, because the conmon-rs client provides only the CRI
+	// log driver.
+	return []conmonClient.ContainerLogDriver{
+		{
+			Type:    conmonClient.LogDriverTypeContainerRuntimeInterface,
+			Path:    c.logPath,
+			MaxSize: maxSize,
+		},
+	}
+}
+
 func (r *runtimePod) StartContainer(ctx context.Context, c *Container) error {
 	return r.oci.StartContainer(ctx, c)
 }
@@ -196,10 +229,20 @@ func (r *runtimePod) ExecSyncContainer(ctx context.Context, c *Container, cmd []
 			ExitCode: -1,
 		}, nil
 	}
+	// conmon-rs has no output limit, which is why it gets enforced here.
+	limit := r.oci.config.ExecSyncOutputSizeMax
+	stdout, truncated := truncateExecSyncOutput(res.Stdout, limit)
+	if truncated {
+		reportExecSyncTruncation(ctx, c, execSyncStdout, limit)
+	}
+	stderr, truncated := truncateExecSyncOutput(res.Stderr, limit)
+	if truncated {
+		reportExecSyncTruncation(ctx, c, execSyncStderr, limit)
+	}
 	return &types.ExecSyncResponse{
 		ExitCode: res.ExitCode,
-		Stdout:   res.Stdout,
-		Stderr:   res.Stderr,
+		Stdout:   stdout,
+		Stderr:   stderr,
 	}, nil
 }
 
@@ -293,6 +336,9 @@ func (r *runtimePod) PortForwardContainer(ctx context.Context, c *Container, net
 }
 
 func (r *runtimePod) ReopenContainerLog(ctx context.Context, c *Container) error {
+	if c.Spoofed() {
+		return nil
+	}
 	return r.client.ReopenLogContainer(ctx, &conmonClient.ReopenLogContainerConfig{
 		ID: c.ID(),
 	})
@@ -1,7 +1,6 @@
 package oci
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
 	"io"
@@ -29,6 +28,7 @@ import (
 	conmonconfig "github.com/containers/conmon/runner/config"
 	"github.com/cri-o/cri-o/internal/config/cgmgr"
 	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/pkg/config"
 	"github.com/cri-o/cri-o/server/metrics"
 	"github.com/cri-o/cri-o/utils"
 	"github.com/cri-o/cri-o/utils/errdefs"
@@ -40,7 +40,6 @@ import (
 	"k8s.io/client-go/tools/remotecommand"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 	kubecontainer "k8s.io/kubernetes/pkg/kubelet/container"
-	kioutil "k8s.io/kubernetes/pkg/kubelet/util/ioutils"
 	utilexec "k8s.io/utils/exec"
 )
 
@@ -52,8 +51,13 @@ type runtimeVM struct {
 	configPath string
 	exitsPath  string
 	ctx        context.Context
-	client     *ttrpc.Client
-	task       task.TaskService
+
+	// execSyncOutputSizeMax is the maximum size of each exec sync output
+	// stream.
+	execSyncOutputSizeMax int64
+
+	client *ttrpc.Client
+	task   task.TaskService
 
 	sync.Mutex
 	ctrs map[string]containerInfo
@@ -69,7 +73,7 @@ const (
 )
 
 // newRuntimeVM creates a new runtimeVM instance
-func newRuntimeVM(path, root, configPath, exitsPath string) RuntimeImpl {
+func newRuntimeVM(path, root, configPath, exitsPath string, execSyncOutputSizeMax int64) RuntimeImpl {
 	logrus.Debug("oci.newRuntimeVM() start")
 	defer logrus.Debug("oci.newRuntimeVM() end")
 
@@ -92,6 +96,8 @@ func newRuntimeVM(path, root, configPath, exitsPath string) RuntimeImpl {
 		fifoDir:    filepath.Join(root, "crio", "fifo"),
 		ctx:        context.Background(),
 		ctrs:       make(map[string]containerInfo),
+
+		execSyncOutputSizeMax: execSyncOutputSizeMax,
 	}
 }
 
@@ -311,9 +317,8 @@ func (r *runtimeVM) ExecSyncContainer(ctx context.Context, c *Container, command
 	log.Debugf(ctx, "RuntimeVM.ExecSyncContainer() start")
 	defer log.Debugf(ctx, "RuntimeVM.ExecSyncContainer() end")
 
-	var stdoutBuf, stderrBuf bytes.Buffer
-	stdout := kioutil.WriteCloserWrapper(kioutil.LimitWriter(&stdoutBuf, maxExecSyncSize))
-	stderr := kioutil.WriteCloserWrapper(kioutil.LimitWriter(&stderrBuf, maxExecSyncSize))
+	stdout := newExecSyncBuffer(r.execSyncOutputSizeMax)
+	stderr := newExecSyncBuffer(r.execSyncOutputSizeMax)
 
 	exitCode, err := r.execContainerCommon(ctx, c, command, timeout, nil, stdout, stderr, c.terminal, nil)
 	if err != nil {
@@ -328,9 +333,16 @@ func (r *runtimeVM) ExecSyncContainer(ctx context.Context, c *Container, command
 		}, nil
 	}
 
+	if stdout.Truncated() {
+		reportExecSyncTruncation(ctx, c, execSyncStdout, r.execSyncOutputSizeMax)
+	}
+	if stderr.Truncated() {
+		reportExecSyncTruncation(ctx, c, execSyncStderr, r.execSyncOutputSizeMax)
+	}
+
 	return &types.ExecSyncResponse{
-		Stdout:   stdoutBuf.Bytes(),
-		Stderr:   stderrBuf.Bytes(),
+		Stdout:   stdout.Bytes(),
+		Stderr:   stderr.Bytes(),
 		ExitCode: exitCode,
 	}, nil
 }
@@ -534,15 +546,24 @@ func (r *runtimeVM) StopContainer(ctx context.Context, c *Container, timeout int
 	var sig syscall.Signal
 
 	if timeout > 0 {
-		sig = c.StopSignal()
-		// Send a stopping signal to the container
-		if err := r.kill(c.ID(), "", sig, false); err != nil {
-			return err
-		}
-
 		timeoutDuration := time.Duration(timeout) * time.Second
 
-		err := r.waitCtrTerminate(sig, stopCh, timeoutDuration)
+		var err error
+		if chain := c.StopSignalChain(); len(chain) > 0 {
+			// release the lock while running the chain, which may exec
+			// commands in the container and should not block status requests
+			c.opLock.Unlock()
+			err = r.runStopSignalChain(ctx, c, chain, stopCh, timeoutDuration)
+			c.opLock.Lock()
+		} else {
+			sig = c.StopSignal()
+			// Send a stopping signal to the container
+			if err := r.kill(c.ID(), "", sig, false); err != nil {
+				return err
+			}
+
+			err = r.waitCtrTerminate(sig, stopCh, timeoutDuration)
+		}
 		if err == nil {
 			c.state.Finished = time.Now()
 			return nil
@@ -565,6 +586,55 @@ func (r *runtimeVM) StopContainer(ctx context.Context, c *Container, timeout int
 	return nil
 }
 
+// runStopSignalChain carries out the stop signal escalation chain of the
+// container within the grace period. It returns an error if the container did
+// not exit before the chain got exhausted.
+func (r *runtimeVM) runStopSignalChain(ctx context.Context, c *Container, chain config.StopSignalChain, stopCh chan error, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for i := range chain {
+		step := &chain[i]
+		c.setStopSignalChainStep(i, chain)
+		log.Debugf(ctx, "Stopping container %s with step %s", c.ID(), c.StopSignalChainStep())
+
+		stepStart := time.Now()
+		stepEnd := deadline
+		if step.Timeout > 0 {
+			if end := stepStart.Add(step.Timeout); end.Before(stepEnd) {
+				stepEnd = end
+			}
+		}
+
+		if len(step.Exec) > 0 {
+			if _, err := r.ExecSyncContainer(ctx, c, step.Exec, stopSignalChainExecTimeout(stepEnd)); err != nil {
+				log.Warnf(ctx, "Failed to run stop command %v in container %s: %v", step.Exec, c.ID(), err)
+			}
+		} else if err := r.kill(c.ID(), "", step.Signal, false); err != nil {
+			return err
+		}
+
+		err := waitCtrTerminateStep(step, stopCh, stepEnd.Sub(stepStart), time.Until(stepEnd))
+		if err == nil {
+			return nil
+		}
+		log.Debugf(ctx, "%v", err)
+		if !time.Now().Before(deadline) {
+			break
+		}
+	}
+	return fmt.Errorf("stop signal chain %s exhausted without the container exiting", chain)
+}
+
+// waitCtrTerminateStep waits for the container to exit for the remaining time
+// of the stop signal chain step, which got the timeout in total.
+func waitCtrTerminateStep(step *config.StopSignalChainStep, stopCh chan error, timeout, remaining time.Duration) error {
+	select {
+	case err := <-stopCh:
+		return err
+	case <-time.After(remaining):
+		return fmt.Errorf("StopContainer with step %s timed out after (%v)", step, timeout)
+	}
+}
+
 func (r *runtimeVM) waitCtrTerminate(sig syscall.Signal, stopCh chan error, timeout time.Duration) error {
 	select {
 	case err := <-stopCh:
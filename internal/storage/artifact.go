@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/containers/image/v5/image"
+	"github.com/containers/image/v5/manifest"
+	"github.com/containers/image/v5/pkg/blobinfocache/none"
+	"github.com/containers/image/v5/signature"
+	"github.com/containers/image/v5/types"
+	digest "github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+)
+
+// Artifact is the content of an OCI artifact with a single layer.
+type Artifact struct {
+	// Digest is the digest of the manifest of the artifact.
+	Digest digest.Digest
+	// MediaType is the media type of the layer of the artifact.
+	MediaType string
+	// Data is the content of the layer of the artifact.
+	Data []byte
+}
+
+// artifactTagTTL is the duration for which the resolved digest of an artifact
+// referenced by tag is reused before the manifest is fetched again.
+const artifactTagTTL = 5 * time.Minute
+
+// artifactTag is the digest a tagged artifact reference resolved to.
+type artifactTag struct {
+	digest   digest.Digest
+	resolved time.Time
+}
+
+// cachedArtifact returns the already pulled artifact of imageName, if it is
+// referenced by digest or its tag has been resolved recently.
+func (svc *imageService) cachedArtifact(imageName string) (*Artifact, bool) {
+	var dgst digest.Digest
+	if _, d, found := strings.Cut(imageName, "@"); found {
+		// artifacts referenced by digest do not change
+		dgst = digest.Digest(d)
+	} else if tag, ok := svc.artifactTags.Load(imageName); ok && time.Since(tag.(artifactTag).resolved) < artifactTagTTL {
+		dgst = tag.(artifactTag).digest
+	} else {
+		return nil, false
+	}
+	artifact, ok := svc.artifacts.Load(dgst)
+	if !ok {
+		return nil, false
+	}
+	return artifact.(*Artifact), true
+}
+
+func (svc *imageService) PullArtifact(ctx context.Context, systemContext *types.SystemContext, imageName string, maxSize int64) (*Artifact, error) {
+	if artifact, ok := svc.cachedArtifact(imageName); ok {
+		return artifact, nil
+	}
+
+	srcSystemContext, srcRef, err := svc.lookup.prepareReference(systemContext, imageName)
+	if err != nil {
+		return nil, err
+	}
+	src, err := srcRef.NewImageSource(ctx, srcSystemContext)
+	if err != nil {
+		return nil, fmt.Errorf("open artifact %s: %w", imageName, err)
+	}
+	defer src.Close()
+
+	policy, err := signature.DefaultPolicy(srcSystemContext)
+	if err != nil {
+		return nil, err
+	}
+	policyContext, err := signature.NewPolicyContext(policy)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := policyContext.Destroy(); err != nil {
+			logrus.Warnf("Unable to destroy signature policy context: %v", err)
+		}
+	}()
+
+	unparsed := image.UnparsedInstance(src, nil)
+	if allowed, err := policyContext.IsRunningImageAllowed(ctx, unparsed); !allowed || err != nil {
+		return nil, fmt.Errorf("artifact %s rejected by signature policy: %w", imageName, err)
+	}
+
+	manifestBytes, mimeType, err := unparsed.Manifest(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get manifest of artifact %s: %w", imageName, err)
+	}
+	manifestDigest, err := manifest.Digest(manifestBytes)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(imageName, "@") {
+		svc.artifactTags.Store(imageName, artifactTag{digest: manifestDigest, resolved: time.Now()})
+	}
+	if artifact, ok := svc.artifacts.Load(manifestDigest); ok {
+		return artifact.(*Artifact), nil
+	}
+
+	if mimeType != specs.MediaTypeImageManifest {
+		return nil, fmt.Errorf("artifact %s has unsupported manifest type %s", imageName, mimeType)
+	}
+	m, err := manifest.OCI1FromManifest(manifestBytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse manifest of artifact %s: %w", imageName, err)
+	}
+	if len(m.Layers) != 1 {
+		return nil, fmt.Errorf("artifact %s has %d instead of 1 layers", imageName, len(m.Layers))
+	}
+	layer := m.Layers[0]
+	if layer.Size > maxSize {
+		return nil, fmt.Errorf("layer of artifact %s exceeds the maximum size of %d bytes", imageName, maxSize)
+	}
+
+	blob, _, err := src.GetBlob(ctx, types.BlobInfo{Digest: layer.Digest, Size: layer.Size}, none.NoCache)
+	if err != nil {
+		return nil, fmt.Errorf("get layer of artifact %s: %w", imageName, err)
+	}
+	defer blob.Close()
+	data, err := io.ReadAll(io.LimitReader(blob, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read layer of artifact %s: %w", imageName, err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("layer of artifact %s exceeds the maximum size of %d bytes", imageName, maxSize)
+	}
+	if err := layer.Digest.Validate(); err != nil {
+		return nil, err
+	}
+	if actual := layer.Digest.Algorithm().FromBytes(data); actual != layer.Digest {
+		return nil, fmt.Errorf("layer of artifact %s has digest %s instead of %s", imageName, actual, layer.Digest)
+	}
+
+	artifact := &Artifact{
+		Digest:    manifestDigest,
+		MediaType: layer.MediaType,
+		Data:      data,
+	}
+	svc.artifacts.Store(manifestDigest, artifact)
+	logrus.Infof("Pulled artifact %s@%s", imageName, manifestDigest)
+	return artifact, nil
+}
@@ -95,6 +95,8 @@ type imageService struct {
 	store          storage.Store
 	imageCache     imageCache
 	imageCacheLock sync.Mutex
+	artifacts      sync.Map
+	artifactTags   sync.Map
 	ctx            context.Context
 }
 
@@ -139,6 +141,10 @@ type ImageServer interface {
 	// ResolveNames takes an image reference and if it's unqualified (w/o hostname),
 	// it uses crio's default registries to qualify it.
 	ResolveNames(systemContext *types.SystemContext, imageName string) ([]string, error)
+	// PullArtifact returns the content of the single layer of an OCI
+	// artifact, which has to be allowed by the signature policy. Artifacts
+	// are cached by their digest and must not exceed maxSize bytes.
+	PullArtifact(ctx context.Context, systemContext *types.SystemContext, imageName string, maxSize int64) (*Artifact, error)
 }
 
 func (svc *imageService) getRef(name string) (types.ImageReference, error) {
@@ -50,6 +50,42 @@ const (
 
 	// SeccompNotifierActionStop indicates that a container should be stopped if used via the SeccompNotifierActionAnnotation key.
 	SeccompNotifierActionStop = "stop"
+
+	// SeccompNotifierActionRecord indicates that the syscalls of a container should be recorded to build a seccomp profile if used via the SeccompNotifierActionAnnotation key.
+	SeccompNotifierActionRecord = "record"
+
+	// SeccompNotifierActionAudit indicates that the blocked syscalls of a container should be allowed and counted if used via the SeccompNotifierActionAnnotation key.
+	SeccompNotifierActionAudit = "audit"
+
+	// StopSignalChainAnnotation sets the stop signal escalation chain of the containers in a pod.
+	StopSignalChainAnnotation = "io.kubernetes.cri-o.StopSignalChain"
+
+	// NetworksAnnotation is a comma separated list of additional CNI networks
+	// in the form `NETWORK[@INTERFACE]` to attach the pod to.
+	NetworksAnnotation = "io.kubernetes.cri-o.Networks"
+
+	// HostAliasesAnnotation adds entries to the hosts file of the pod. The
+	// value is a JSON list of Kubernetes HostAliases, like
+	// `[{"ip": "10.0.0.1", "hostnames": ["foo.local", "bar.local"]}]`.
+	HostAliasesAnnotation = "io.kubernetes.cri-o.HostAliases"
+
+	// HostsPathAnnotation is the path to the hosts file of the sandbox, which
+	// is set by CRI-O.
+	HostsPathAnnotation = "io.kubernetes.cri-o.HostsPath"
+
+	// IDMappingStrategyAnnotation is the strategy used for making the volumes
+	// of a container in a user namespace accessible, which is set by CRI-O.
+	IDMappingStrategyAnnotation = "io.kubernetes.cri-o.IDMappingStrategy"
+
+	// WritablePathsAnnotation is a comma separated list of paths in the form
+	// `PATH[:SIZE]`, which get noexec tmpfs mounts in containers with a
+	// read-only rootfs. The annotation can be suffixed with `.$CTR_NAME` to
+	// only apply to a single container.
+	WritablePathsAnnotation = "io.kubernetes.cri-o.WritablePaths"
+
+	// AppliedWritablePathsAnnotation are the writable paths which got tmpfs
+	// mounts in a container, which is set by CRI-O.
+	AppliedWritablePathsAnnotation = "io.kubernetes.cri-o.AppliedWritablePaths"
 )
 
 var AllAllowedAnnotations = []string{
@@ -67,4 +103,8 @@ var AllAllowedAnnotations = []string{
 	CPUCStatesAnnotation,
 	CPUFreqGovernorAnnotation,
 	SeccompNotifierActionAnnotation,
+	StopSignalChainAnnotation,
+	NetworksAnnotation,
+	HostAliasesAnnotation,
+	WritablePathsAnnotation,
 }
@@ -9,7 +9,9 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/container-orchestrated-devices/container-device-interface/pkg/cdi"
@@ -19,6 +21,7 @@ import (
 	"github.com/containers/image/v5/types"
 	"github.com/containers/podman/v4/pkg/rootless"
 	"github.com/containers/storage"
+	"github.com/cri-o/cri-o/internal/audit"
 	"github.com/cri-o/cri-o/internal/config/apparmor"
 	"github.com/cri-o/cri-o/internal/config/blockio"
 	"github.com/cri-o/cri-o/internal/config/capabilities"
@@ -26,13 +29,16 @@ import (
 	"github.com/cri-o/cri-o/internal/config/cnimgr"
 	"github.com/cri-o/cri-o/internal/config/conmonmgr"
 	"github.com/cri-o/cri-o/internal/config/device"
+	"github.com/cri-o/cri-o/internal/config/mcs"
 	"github.com/cri-o/cri-o/internal/config/node"
 	"github.com/cri-o/cri-o/internal/config/nri"
 	"github.com/cri-o/cri-o/internal/config/nsmgr"
 	"github.com/cri-o/cri-o/internal/config/rdt"
 	"github.com/cri-o/cri-o/internal/config/seccomp"
 	"github.com/cri-o/cri-o/internal/config/ulimits"
+	"github.com/cri-o/cri-o/internal/config/userns"
 	"github.com/cri-o/cri-o/pkg/annotations"
+	criotypes "github.com/cri-o/cri-o/pkg/types"
 	"github.com/cri-o/cri-o/server/otel-collector/collectors"
 	"github.com/cri-o/cri-o/server/useragent"
 	"github.com/cri-o/cri-o/utils"
@@ -46,20 +52,33 @@ import (
 
 // Defaults if none are specified
 const (
-	defaultRuntime             = "runc"
-	DefaultRuntimeType         = "oci"
-	DefaultRuntimeRoot         = "/run/runc"
-	defaultGRPCMaxMsgSize      = 80 * 1024 * 1024
-	OCIBufSize                 = 8192
-	RuntimeTypeVM              = "vm"
-	RuntimeTypePod             = "pod"
-	defaultCtrStopTimeout      = 30 // seconds
-	defaultNamespacesDir       = "/var/run"
-	RuntimeTypeVMBinaryPattern = "containerd-shim-([a-zA-Z0-9\\-\\+])+-v2"
-	tasksetBinary              = "taskset"
-	defaultMonitorCgroup       = "system.slice"
-	MonitorExecCgroupDefault   = ""
-	MonitorExecCgroupContainer = "container"
+	defaultRuntime              = "runc"
+	DefaultRuntimeType          = "oci"
+	DefaultRuntimeRoot          = "/run/runc"
+	defaultGRPCMaxMsgSize       = 80 * 1024 * 1024
+	OCIBufSize                  = 8192
+	RuntimeTypeVM               = "vm"
+	RuntimeTypePod              = "pod"
+	defaultCtrStopTimeout       = 30 // seconds
+	defaultNamespacesDir        = "/var/run"
+	defaultSeccompRecordDir     = "/var/lib/crio/seccomp"
+	defaultApparmorProfilesDir  = "/etc/crio/apparmor.d"
+	defaultUsernsAllocationFile = "/var/lib/crio/userns-allocations.json"
+	defaultMCSAllocationFile    = "/var/lib/crio/selinux-mcs-allocations.json"
+	RuntimeTypeVMBinaryPattern  = "containerd-shim-([a-zA-Z0-9\\-\\+])+-v2"
+	tasksetBinary               = "taskset"
+	defaultMonitorCgroup        = "system.slice"
+	MonitorExecCgroupDefault    = ""
+	MonitorExecCgroupContainer  = "container"
+)
+
+const (
+	// CapabilityPolicyReject rejects containers using capabilities which are
+	// not allowed.
+	CapabilityPolicyReject = "reject"
+	// CapabilityPolicyStrip removes the capabilities which are not allowed
+	// from containers.
+	CapabilityPolicyStrip = "strip"
 )
 
 // Config represents the entire set of configuration values that can be set for
@@ -77,6 +96,7 @@ type Config struct {
 	MetricsConfig
 	TracingConfig
 	StatsConfig
+	AuditConfig
 	NRI           *nri.Config
 	SystemContext *types.SystemContext
 }
@@ -114,6 +134,19 @@ const (
 	// DefaultLogSizeMax is the default value for the maximum log size
 	// allowed for a container. Negative values mean that no limit is imposed.
 	DefaultLogSizeMax = -1
+
+	// DefaultExecSyncOutputSizeMax is the default value for the maximum size
+	// of the output of a single exec sync request. It is set to the amount of
+	// logs allowed in the dockershim implementation:
+	// https://github.com/kubernetes/kubernetes/pull/82514
+	DefaultExecSyncOutputSizeMax = 16 * 1024 * 1024
+)
+
+const (
+	// HostPortBackendIPTables manages the hostports of pods with iptables
+	HostPortBackendIPTables = "iptables"
+	// HostPortBackendNFTables manages the hostports of pods with nftables
+	HostPortBackendNFTables = "nftables"
 )
 
 const (
@@ -213,6 +246,52 @@ type RuntimeHandler struct {
 
 	// MonitorExecCgroup indicates whether to move exec probes to the container's cgroup.
 	MonitorExecCgroup string `toml:"monitor_exec_cgroup,omitempty"`
+
+	// The following fields override the corresponding `crio.runtime` options
+	// for containers using this runtime handler. An unset field means that
+	// the global value is used.
+	DefaultCapabilities capabilities.Capabilities `toml:"default_capabilities,omitempty"`
+	DefaultSysctls      []string                  `toml:"default_sysctls,omitempty"`
+	DefaultUlimits      []string                  `toml:"default_ulimits,omitempty"`
+	SeccompProfile      string                    `toml:"seccomp_profile,omitempty"`
+	ApparmorProfile     string                    `toml:"apparmor_profile,omitempty"`
+	DefaultMountsFile   string                    `toml:"default_mounts_file,omitempty"`
+
+	// AllowedCapabilities are the capabilities which containers of this
+	// runtime handler are allowed to use. All capabilities are allowed if
+	// unset.
+	AllowedCapabilities capabilities.Capabilities `toml:"allowed_capabilities,omitempty"`
+
+	// PidsLimit overrides the global pids_limit if not zero.
+	PidsLimit int64 `toml:"pids_limit,omitempty"`
+
+	// CtrStopTimeout is the grace period in seconds used when CRI-O stops
+	// the containers of the runtime handler on its own, for example when
+	// removing a pod. CRI-O uses 10 seconds if it is zero.
+	CtrStopTimeout int64 `toml:"ctr_stop_timeout,omitempty"`
+
+	// StopSignalChain is the stop signal escalation chain used to stop
+	// containers of this runtime handler, for example
+	// "SIGTERM:10s,SIGINT". It can be overridden by the
+	// "io.kubernetes.cri-o.StopSignalChain" pod annotation.
+	StopSignalChain string `toml:"stop_signal_chain,omitempty"`
+
+	// MCSCategories is the range of MCS categories in the form "cMIN.cMAX",
+	// from which the SELinux levels of pods of this runtime handler are
+	// allocated.
+	MCSCategories string `toml:"mcs_categories,omitempty"`
+
+	// features are the discovered features of the runtime, nil if unknown.
+	features *criotypes.RuntimeFeatures
+
+	// seccompConfig, apparmorConfig and ulimitsConfig are only set if the
+	// corresponding option is overridden.
+	seccompConfig  *seccomp.Config
+	apparmorConfig *apparmor.Config
+	ulimitsConfig  *ulimits.Config
+
+	// mcsRange is only set if MCSCategories is set.
+	mcsRange *mcs.Range
 }
 
 // Multiple runtime Handlers in a map
@@ -224,12 +303,21 @@ type RuntimeConfig struct {
 	// should be used when an empty one is specified.
 	SeccompUseDefaultWhenEmpty bool `toml:"seccomp_use_default_when_empty"`
 
+	// SeccompRecordDir is the directory where the seccomp profiles recorded
+	// by the seccomp notifier get written to.
+	SeccompRecordDir string `toml:"seccomp_record_dir"`
+
 	// NoPivot instructs the runtime to not use `pivot_root`, but instead use `MS_MOVE`
 	NoPivot bool `toml:"no_pivot"`
 
 	// SELinux determines whether or not SELinux is used for pod separation.
 	SELinux bool `toml:"selinux"`
 
+	// SELinuxMCSAllocationFile is the file where the MCS levels allocated to
+	// pods are persisted. The levels are allocated by the SELinux library
+	// without persistence if it is empty.
+	SELinuxMCSAllocationFile string `toml:"selinux_mcs_allocation_file"`
+
 	// Whether container output should be logged to journald in addition
 	// to the kubernetes log file
 	LogToJournald bool `toml:"log_to_journald"`
@@ -260,6 +348,15 @@ type RuntimeConfig struct {
 	// This can cause a regression with non-root users not getting capabilities as they previously did.
 	AddInheritableCapabilities bool `toml:"add_inheritable_capabilities"`
 
+	// CapabilityPolicy defines if containers using capabilities which are
+	// not allowed for their runtime handler or Kubernetes namespace get
+	// rejected ("reject") or if the capabilities get removed ("strip").
+	CapabilityPolicy string `toml:"capability_policy"`
+
+	// NamespaceAllowedCapabilities are the capabilities which containers of a
+	// Kubernetes namespace are allowed to use.
+	NamespaceAllowedCapabilities map[string]capabilities.Capabilities `toml:"namespace_allowed_capabilities"`
+
 	// Additional environment variables to set for all the
 	// containers. These are overridden if set in the
 	// container image spec or in the container runtime configuration.
@@ -307,6 +404,10 @@ type RuntimeConfig struct {
 	// default for the runtime.
 	ApparmorProfile string `toml:"apparmor_profile"`
 
+	// ApparmorProfilesDir is the directory of AppArmor profile files which
+	// get loaded by CRI-O.
+	ApparmorProfilesDir string `toml:"apparmor_profiles_dir"`
+
 	// BlockIOConfigFile is the path to the blockio class configuration
 	// file for configuring the cgroup blockio controller.
 	BlockIOConfigFile string `toml:"blockio_config_file"`
@@ -356,6 +457,20 @@ type RuntimeConfig struct {
 	// to us via CRI, for a pod that isn't to be run as UID 0.
 	MinimumMappableGID int64 `toml:"minimum_mappable_gid"`
 
+	// UsernsAllocationUser is the user whose subordinate ID ranges in
+	// /etc/subuid and /etc/subgid are used for allocating the ID ranges of
+	// pods requesting a user namespace without ID mappings.
+	UsernsAllocationUser string `toml:"userns_allocation_user"`
+
+	// UsernsAllocationRanges are the host ID ranges in the form HOSTID:SIZE
+	// used for allocating the UID and GID ranges of pods requesting a user
+	// namespace without ID mappings.
+	UsernsAllocationRanges []string `toml:"userns_allocation_ranges"`
+
+	// UsernsAllocationFile is the file where the allocated user namespace ID
+	// ranges are persisted.
+	UsernsAllocationFile string `toml:"userns_allocation_file"`
+
 	// LogLevel determines the verbosity of the logs based on the level it is set to.
 	// Options are fatal, panic, error (default), warn, info, debug, and trace.
 	LogLevel string `toml:"log_level"`
@@ -367,6 +482,11 @@ type RuntimeConfig struct {
 	// gets tracked
 	NamespacesDir string `toml:"namespaces_dir"`
 
+	// NamespacePoolSize is the number of pre-created network, IPC and UTS
+	// namespaces kept in the NamespacesDir for fast pod startup. The pool
+	// is disabled if set to 0.
+	NamespacePoolSize int `toml:"namespace_pool_size"`
+
 	// PinNSPath is the path to find the pinns binary, which is needed
 	// to manage namespace lifecycle
 	PinnsPath string `toml:"pinns_path"`
@@ -395,6 +515,13 @@ type RuntimeConfig struct {
 	// Negative values indicate that the log file won't be truncated.
 	LogSizeMax int64 `toml:"log_size_max"`
 
+	// ExecSyncOutputSizeMax is the maximum number of bytes of stdout and
+	// stderr buffered for a single exec sync request. Exceeding output is
+	// dropped and replaced by a truncation marker. The output of the pod
+	// runtime type is truncated after conmon-rs returned it completely. It
+	// must be >= 8192 to match/exceed conmon's read buffer.
+	ExecSyncOutputSizeMax int64 `toml:"exec_sync_output_size_max"`
+
 	// CtrStopTimeout specifies the time to wait before to generate an
 	// error because the container state is still tagged as "running".
 	CtrStopTimeout int64 `toml:"ctr_stop_timeout"`
@@ -494,6 +621,31 @@ type NetworkConfig struct {
 	// PluginDirs is where CNI plugin binaries are stored.
 	PluginDirs []string `toml:"plugin_dirs"`
 
+	// HostPortBackend is the backend used to map hostports of pods, either
+	// "iptables" or "nftables".
+	HostPortBackend string `toml:"hostport_backend"`
+
+	// HostPortReconcileInterval is the interval in which the installed
+	// hostport rules get compared with the port mappings of the running
+	// sandboxes. A value of "0" disables the periodic reconciliation.
+	HostPortReconcileInterval string `toml:"hostport_reconcile_interval"`
+
+	// EnableBandwidthFallback enables limiting the bandwidth of pods with
+	// traffic control rules on their host side veth, if the default CNI
+	// network does not handle the bandwidth capability.
+	EnableBandwidthFallback bool `toml:"enable_bandwidth_fallback"`
+
+	// DNSOptions is the node-wide template of resolver options, like
+	// "ndots:5", which gets applied to the resolv.conf of all pods. Options
+	// of the DNS config of a pod take precedence.
+	DNSOptions []string `toml:"dns_options"`
+
+	// DNSUpstreamResolvConf is the resolv.conf containing the upstream
+	// servers of a local DNS stub resolver, like the one of systemd-resolved.
+	// The upstream servers replace the stub resolvers in the resolv.conf of
+	// pods which are not running in the host network.
+	DNSUpstreamResolvConf string `toml:"dns_upstream_resolv_conf"`
+
 	// cniManager manages the internal ocicni plugin
 	cniManager *cnimgr.CNIManager
 }
@@ -576,6 +728,33 @@ type StatsConfig struct {
 	StatsCollectionPeriod int `toml:"stats_collection_period"`
 }
 
+// AuditConfig specifies all necessary configuration for the audit log of
+// privileged container operations.
+type AuditConfig struct {
+	// AuditLogPath is the path of the audit log file. If empty, the audit log
+	// is not written to a file.
+	AuditLogPath string `toml:"audit_log_path"`
+
+	// AuditLogToJournald specifies if the audit log should be sent to journald.
+	AuditLogToJournald bool `toml:"audit_log_to_journald"`
+
+	// AuditLogMaxSize is the size in megabytes at which the audit log file
+	// gets rotated.
+	AuditLogMaxSize int `toml:"audit_log_max_size"`
+
+	// AuditLogMaxBackups is the number of rotated audit log files to retain.
+	// If set to 0, all rotated files are retained.
+	AuditLogMaxBackups int `toml:"audit_log_max_backups"`
+
+	// AuditLogMaxAge is the number of days to retain rotated audit log files.
+	// If set to 0, rotated files are not removed based on their age.
+	AuditLogMaxAge int `toml:"audit_log_max_age"`
+
+	// AuditRedactPatterns are regular expressions matching the parts of
+	// exec command arguments, which get redacted in the audit log.
+	AuditRedactPatterns []string `toml:"audit_redact_patterns"`
+}
+
 // tomlConfig is another way of looking at a Config, which is
 // TOML-friendly (it has all of the explicit tables). It's just used for
 // conversions.
@@ -589,6 +768,7 @@ type tomlConfig struct {
 		Metrics struct{ MetricsConfig } `toml:"metrics"`
 		Tracing struct{ TracingConfig } `toml:"tracing"`
 		Stats   struct{ StatsConfig }   `toml:"stats"`
+		Audit   struct{ AuditConfig }   `toml:"audit"`
 		NRI     struct{ *nri.Config }   `toml:"nri"`
 	} `toml:"crio"`
 }
@@ -608,6 +788,7 @@ func (t *tomlConfig) toConfig(c *Config) {
 	c.MetricsConfig = t.Crio.Metrics.MetricsConfig
 	c.TracingConfig = t.Crio.Tracing.TracingConfig
 	c.StatsConfig = t.Crio.Stats.StatsConfig
+	c.AuditConfig = t.Crio.Audit.AuditConfig
 	c.NRI = t.Crio.NRI.Config
 	t.SetSystemContext(c)
 }
@@ -621,6 +802,7 @@ func (t *tomlConfig) fromConfig(c *Config) {
 	t.Crio.Metrics.MetricsConfig = c.MetricsConfig
 	t.Crio.Tracing.TracingConfig = c.TracingConfig
 	t.Crio.Stats.StatsConfig = c.StatsConfig
+	t.Crio.Audit.AuditConfig = c.AuditConfig
 	t.Crio.NRI.Config = c.NRI
 }
 
@@ -799,7 +981,9 @@ func DefaultConfig() (*Config, error) {
 				defaultRuntime: defaultRuntimeHandler(),
 			},
 			SELinux:                     selinuxEnabled(),
+			SELinuxMCSAllocationFile:    defaultMCSAllocationFile,
 			ApparmorProfile:             apparmor.DefaultProfile,
+			ApparmorProfilesDir:         defaultApparmorProfilesDir,
 			BlockIOConfigFile:           DefaultBlockIOConfigFile,
 			IrqBalanceConfigFile:        DefaultIrqBalanceConfigFile,
 			RdtConfigFile:               rdt.DefaultRdtConfigFile,
@@ -809,15 +993,19 @@ func DefaultConfig() (*Config, error) {
 			ContainerAttachSocketDir:    conmonconfig.ContainerAttachSocketDir,
 			MinimumMappableUID:          -1,
 			MinimumMappableGID:          -1,
+			UsernsAllocationFile:        defaultUsernsAllocationFile,
 			LogSizeMax:                  DefaultLogSizeMax,
+			ExecSyncOutputSizeMax:       DefaultExecSyncOutputSizeMax,
 			CtrStopTimeout:              defaultCtrStopTimeout,
 			DefaultCapabilities:         capabilities.Default(),
+			CapabilityPolicy:            CapabilityPolicyReject,
 			LogLevel:                    "info",
 			HooksDir:                    []string{hooks.DefaultDir},
 			CDISpecDirs:                 cdi.DefaultSpecDirs,
 			NamespacesDir:               defaultNamespacesDir,
 			DropInfraCtr:                true,
 			SeccompUseDefaultWhenEmpty:  seccompConfig.UseDefaultWhenEmpty(),
+			SeccompRecordDir:            defaultSeccompRecordDir,
 			IrqBalanceConfigRestoreFile: DefaultIrqBalanceConfigRestoreFile,
 			seccompConfig:               seccomp.New(),
 			apparmorConfig:              apparmor.New(),
@@ -835,8 +1023,11 @@ func DefaultConfig() (*Config, error) {
 			ImageVolumes:     ImageVolumesMkdir,
 		},
 		NetworkConfig: NetworkConfig{
-			NetworkDir: cniConfigDir,
-			PluginDirs: []string{cniBinDir},
+			NetworkDir:                cniConfigDir,
+			PluginDirs:                []string{cniBinDir},
+			HostPortBackend:           HostPortBackendIPTables,
+			HostPortReconcileInterval: "5m",
+			DNSUpstreamResolvConf:     "/run/systemd/resolve/resolv.conf",
 		},
 		MetricsConfig: MetricsConfig{
 			MetricsPort:       9090,
@@ -847,6 +1038,11 @@ func DefaultConfig() (*Config, error) {
 			TracingSamplingRatePerMillion: 0,
 			EnableTracing:                 false,
 		},
+		AuditConfig: AuditConfig{
+			AuditLogMaxSize:     100,
+			AuditLogMaxBackups:  10,
+			AuditRedactPatterns: []string{},
+		},
 		NRI: nri.New(),
 	}, nil
 }
@@ -881,6 +1077,7 @@ func (c *Config) Validate(onExecution bool) error {
 	c.RuntimeConfig.seccompConfig.SetNotifierPath(
 		filepath.Join(filepath.Dir(c.Listen), "seccomp"),
 	)
+	c.RuntimeConfig.propagateSeccompSettings()
 
 	if err := c.NetworkConfig.Validate(onExecution); err != nil {
 		return fmt.Errorf("validating network config: %w", err)
@@ -890,6 +1087,10 @@ func (c *Config) Validate(onExecution bool) error {
 		return fmt.Errorf("validating api config: %w", err)
 	}
 
+	if err := c.AuditConfig.Validate(); err != nil {
+		return fmt.Errorf("validating audit config: %w", err)
+	}
+
 	if !c.SELinux {
 		selinux.SetDisabled()
 	}
@@ -920,6 +1121,27 @@ func (c *APIConfig) Validate(onExecution bool) error {
 	return nil
 }
 
+// Validate is the main entry point for audit configuration validation. It
+// returns an `error` on validation failure, otherwise `nil`.
+func (c *AuditConfig) Validate() error {
+	if c.AuditLogPath != "" && !filepath.IsAbs(c.AuditLogPath) {
+		return fmt.Errorf("audit log path %q is not absolute", c.AuditLogPath)
+	}
+	if c.AuditLogMaxSize <= 0 {
+		return fmt.Errorf("audit log max size %d is not positive", c.AuditLogMaxSize)
+	}
+	if c.AuditLogMaxBackups < 0 {
+		return fmt.Errorf("audit log max backups %d is negative", c.AuditLogMaxBackups)
+	}
+	if c.AuditLogMaxAge < 0 {
+		return fmt.Errorf("audit log max age %d is negative", c.AuditLogMaxAge)
+	}
+	if _, err := audit.CompileRedactPatterns(c.AuditRedactPatterns); err != nil {
+		return err
+	}
+	return nil
+}
+
 // RemoveUnusedSocket first ensures that the path to the socket exists and
 // removes unused socket connections if available.
 func RemoveUnusedSocket(path string) error {
@@ -995,6 +1217,10 @@ func (c *RuntimeConfig) Validate(systemContext *types.SystemContext, onExecution
 		return fmt.Errorf("log size max should be negative or >= %d", OCIBufSize)
 	}
 
+	if c.ExecSyncOutputSizeMax < OCIBufSize {
+		return fmt.Errorf("exec sync output size max should be >= %d, got %d", OCIBufSize, c.ExecSyncOutputSizeMax)
+	}
+
 	// We need to ensure the container termination will be properly waited
 	// for by defining a minimal timeout value. This will prevent timeout
 	// value defined in the configuration file to be too low.
@@ -1003,6 +1229,10 @@ func (c *RuntimeConfig) Validate(systemContext *types.SystemContext, onExecution
 		logrus.Warnf("Forcing ctr_stop_timeout to lowest possible value of %ds", c.CtrStopTimeout)
 	}
 
+	if c.NamespacePoolSize < 0 {
+		return fmt.Errorf("namespace pool size should not be negative, got %d", c.NamespacePoolSize)
+	}
+
 	if _, err := c.Sysctls(); err != nil {
 		return fmt.Errorf("invalid default_sysctls: %w", err)
 	}
@@ -1011,6 +1241,24 @@ func (c *RuntimeConfig) Validate(systemContext *types.SystemContext, onExecution
 		return fmt.Errorf("invalid capabilities: %w", err)
 	}
 
+	if c.UsernsAllocationUser != "" && len(c.UsernsAllocationRanges) > 0 {
+		return errors.New("userns_allocation_user and userns_allocation_ranges are mutually exclusive")
+	}
+
+	if _, err := userns.ParseRanges(c.UsernsAllocationRanges); err != nil {
+		return fmt.Errorf("invalid userns_allocation_ranges: %w", err)
+	}
+
+	if c.CapabilityPolicy != CapabilityPolicyReject && c.CapabilityPolicy != CapabilityPolicyStrip {
+		return fmt.Errorf("invalid capability_policy %q, has to be %q or %q", c.CapabilityPolicy, CapabilityPolicyReject, CapabilityPolicyStrip)
+	}
+
+	for namespace, allowed := range c.NamespaceAllowedCapabilities {
+		if err := allowed.ValidateAllowed(); err != nil {
+			return fmt.Errorf("invalid namespace_allowed_capabilities for namespace %q: %w", namespace, err)
+		}
+	}
+
 	if c.InfraCtrCPUSet != "" {
 		set, err := cpuset.Parse(c.InfraCtrCPUSet)
 		if err != nil {
@@ -1107,6 +1355,10 @@ func (c *RuntimeConfig) Validate(systemContext *types.SystemContext, onExecution
 			return fmt.Errorf("unable to load AppArmor profile: %w", err)
 		}
 
+		if err := c.apparmorConfig.LoadProfilesDir(c.ApparmorProfilesDir); err != nil {
+			return fmt.Errorf("unable to load AppArmor profiles directory: %w", err)
+		}
+
 		if err := c.blockioConfig.Load(c.BlockIOConfigFile); err != nil {
 			return fmt.Errorf("blockio configuration: %w", err)
 		}
@@ -1168,7 +1420,12 @@ func (c *RuntimeConfig) ValidateRuntimes() error {
 
 	// Validate if runtime_path does exist for each runtime
 	for name, handler := range c.Runtimes {
-		if err := handler.Validate(name); err != nil {
+		err := handler.Validate(name)
+		// conmon-rs provides only the CRI log driver
+		if err == nil && c.LogToJournald && handler.RuntimeType == RuntimeTypePod {
+			err = fmt.Errorf("log_to_journald is not supported by runtime handler %s of runtime_type %q", name, RuntimeTypePod)
+		}
+		if err != nil {
 			if c.DefaultRuntime == name {
 				return err
 			}
@@ -1330,6 +1587,21 @@ func validateExecutablePath(executable, currentPath string) (string, error) {
 // execution checks. It returns an `error` on validation failure, otherwise
 // `nil`.
 func (c *NetworkConfig) Validate(onExecution bool) error {
+	switch c.HostPortBackend {
+	case HostPortBackendIPTables, HostPortBackendNFTables:
+	default:
+		return fmt.Errorf("invalid hostport_backend %q, must be %q or %q",
+			c.HostPortBackend, HostPortBackendIPTables, HostPortBackendNFTables)
+	}
+
+	if _, err := c.HostPortReconcileIntervalDuration(); err != nil {
+		return err
+	}
+
+	if err := validateDNSOptions(c.DNSOptions); err != nil {
+		return err
+	}
+
 	if onExecution {
 		err := utils.IsDirectory(c.NetworkDir)
 		if err != nil {
@@ -1386,7 +1658,13 @@ func (r *RuntimeHandler) Validate(name string) error {
 	if err := r.ValidateRuntimeAllowedAnnotations(); err != nil {
 		return err
 	}
-	return r.ValidateRuntimeType(name)
+	if err := r.ValidateRuntimeType(name); err != nil {
+		return err
+	}
+	if err := r.ValidateRuntimeDefaults(name); err != nil {
+		return err
+	}
+	return r.ValidateRuntimeFeatures(name)
 }
 
 func (r *RuntimeHandler) ValidateRuntimeVMBinaryPattern() bool {
@@ -1441,6 +1719,93 @@ func (r *RuntimeHandler) ValidateRuntimeType(name string) error {
 	return nil
 }
 
+// ValidateRuntimeDefaults validates the options overriding the global
+// `crio.runtime` values and loads the required profiles.
+func (r *RuntimeHandler) ValidateRuntimeDefaults(name string) error {
+	if r.DefaultCapabilities != nil {
+		if err := r.DefaultCapabilities.Validate(); err != nil {
+			return fmt.Errorf("invalid default_capabilities for runtime %q: %w", name, err)
+		}
+	}
+
+	if r.AllowedCapabilities != nil {
+		if err := r.AllowedCapabilities.ValidateAllowed(); err != nil {
+			return fmt.Errorf("invalid allowed_capabilities for runtime %q: %w", name, err)
+		}
+	}
+
+	if _, err := parseSysctls(r.DefaultSysctls); err != nil {
+		return fmt.Errorf("invalid default_sysctls for runtime %q: %w", name, err)
+	}
+
+	r.mcsRange = nil
+	if r.MCSCategories != "" {
+		mcsRange, err := mcs.ParseRange(r.MCSCategories)
+		if err != nil {
+			return fmt.Errorf("invalid mcs_categories for runtime %q: %w", name, err)
+		}
+		r.mcsRange = &mcsRange
+	}
+
+	r.ulimitsConfig = nil
+	if len(r.DefaultUlimits) > 0 {
+		r.ulimitsConfig = ulimits.New()
+		if err := r.ulimitsConfig.LoadUlimits(r.DefaultUlimits); err != nil {
+			return fmt.Errorf("invalid default_ulimits for runtime %q: %w", name, err)
+		}
+	}
+
+	if r.PidsLimit < -1 {
+		return fmt.Errorf("invalid pids_limit %d for runtime %q: must be -1 or greater", r.PidsLimit, name)
+	}
+
+	if r.CtrStopTimeout < 0 {
+		return fmt.Errorf("invalid ctr_stop_timeout %d for runtime %q: must not be negative", r.CtrStopTimeout, name)
+	}
+
+	if _, err := ParseStopSignalChain(r.StopSignalChain); err != nil {
+		return fmt.Errorf("invalid stop_signal_chain for runtime %q: %w", name, err)
+	}
+
+	if r.DefaultMountsFile != "" {
+		if _, err := os.Stat(r.DefaultMountsFile); err != nil {
+			return fmt.Errorf("invalid default_mounts_file for runtime %q: %w", name, err)
+		}
+	}
+
+	r.seccompConfig = nil
+	if r.SeccompProfile != "" {
+		r.seccompConfig = seccomp.New()
+		if err := r.seccompConfig.LoadProfile(r.SeccompProfile); err != nil {
+			return fmt.Errorf("unable to load seccomp profile for runtime %q: %w", name, err)
+		}
+	}
+
+	r.apparmorConfig = nil
+	if r.ApparmorProfile != "" {
+		r.apparmorConfig = apparmor.New()
+		if err := r.apparmorConfig.LoadProfile(r.ApparmorProfile); err != nil {
+			return fmt.Errorf("unable to load AppArmor profile for runtime %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// ValidateRuntimeFeatures discovers the features of the runtime and checks
+// that they are sufficient for the node. Runtimes which are not able to report
+// their features are assumed to support everything.
+func (r *RuntimeHandler) ValidateRuntimeFeatures(name string) error {
+	if err := r.LoadRuntimeFeatures(name); err != nil {
+		logrus.Infof("Unable to discover features of runtime %q, assuming full support: %v", name, err)
+		return nil
+	}
+	if node.CgroupIsV2() && !r.features.SupportsCgroupV2() {
+		return fmt.Errorf("runtime %q does not support cgroup v2", name)
+	}
+	return nil
+}
+
 // ValidateRuntimeConfigPath checks if the `RuntimeConfigPath` exists.
 func (r *RuntimeHandler) ValidateRuntimeConfigPath(name string) error {
 	if r.RuntimeConfigPath == "" {
@@ -1501,11 +1866,57 @@ func (c *NetworkConfig) CNIPluginAddWatcher() chan bool {
 	return c.cniManager.AddWatcher()
 }
 
+// CNIManagerSetStatusHandler sets the handler which is called whenever the
+// readiness of the CNI plugin changes
+func (c *NetworkConfig) CNIManagerSetStatusHandler(handler func(error)) {
+	if c.cniManager != nil {
+		c.cniManager.SetStatusHandler(handler)
+	}
+}
+
 // CNIManagerShutdown shuts down the CNI Manager
 func (c *NetworkConfig) CNIManagerShutdown() {
 	c.cniManager.Shutdown()
 }
 
+// validateDNSOptions validates the resolver options of the dns_options
+// template.
+func validateDNSOptions(options []string) error {
+	for _, option := range options {
+		if option == "" || strings.ContainsAny(option, " \t\n") {
+			return fmt.Errorf("invalid dns_options entry %q", option)
+		}
+		name, value, hasValue := strings.Cut(option, ":")
+		switch name {
+		case "ndots", "timeout", "attempts":
+			if !hasValue {
+				return fmt.Errorf("invalid dns_options entry %q: %s requires a value", option, name)
+			}
+			if _, err := strconv.ParseUint(value, 10, 8); err != nil {
+				return fmt.Errorf("invalid dns_options entry %q: %w", option, err)
+			}
+		}
+	}
+	return nil
+}
+
+// HostPortReconcileIntervalDuration returns the parsed hostport reconcile
+// interval. A zero duration means that the periodic reconciliation is
+// disabled.
+func (c *NetworkConfig) HostPortReconcileIntervalDuration() (time.Duration, error) {
+	if c.HostPortReconcileInterval == "" {
+		return 0, nil
+	}
+	interval, err := time.ParseDuration(c.HostPortReconcileInterval)
+	if err != nil {
+		return 0, fmt.Errorf("invalid hostport_reconcile_interval %q: %w", c.HostPortReconcileInterval, err)
+	}
+	if interval < 0 {
+		return 0, fmt.Errorf("invalid hostport_reconcile_interval %q: must not be negative", c.HostPortReconcileInterval)
+	}
+	return interval, nil
+}
+
 // SetSingleConfigPath set single config path for config
 func (c *Config) SetSingleConfigPath(singleConfigPath string) {
 	c.singleConfigPath = singleConfigPath
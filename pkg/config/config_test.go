@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 
 	"github.com/containers/storage"
+	"github.com/cri-o/cri-o/internal/config/capabilities"
 	crioann "github.com/cri-o/cri-o/pkg/annotations"
 	"github.com/cri-o/cri-o/pkg/config"
 	"github.com/cri-o/cri-o/utils/cmdrunner"
@@ -188,6 +189,83 @@ var _ = t.Describe("Config", func() {
 		})
 	})
 
+	t.Describe("ValidateAuditConfig", func() {
+		It("should succeed with default config", func() {
+			// Given
+			// When
+			err := sut.AuditConfig.Validate()
+
+			// Then
+			Expect(err).To(BeNil())
+		})
+
+		It("should succeed with audit log path and redaction patterns", func() {
+			// Given
+			sut.AuditLogPath = "/var/log/crio/audit.log"
+			sut.AuditRedactPatterns = []string{`password=\S+`}
+
+			// When
+			err := sut.AuditConfig.Validate()
+
+			// Then
+			Expect(err).To(BeNil())
+		})
+
+		It("should fail with relative audit log path", func() {
+			// Given
+			sut.AuditLogPath = "audit.log"
+
+			// When
+			err := sut.AuditConfig.Validate()
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with non positive audit log max size", func() {
+			// Given
+			sut.AuditLogMaxSize = 0
+
+			// When
+			err := sut.AuditConfig.Validate()
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with negative audit log max backups and age", func() {
+			// Given
+			sut.AuditLogMaxBackups = -1
+
+			// When
+			err := sut.AuditConfig.Validate()
+
+			// Then
+			Expect(err).NotTo(BeNil())
+
+			// Given
+			sut.AuditLogMaxBackups = 0
+			sut.AuditLogMaxAge = -1
+
+			// When
+			err = sut.AuditConfig.Validate()
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with invalid redaction pattern", func() {
+			// Given
+			sut.AuditRedactPatterns = []string{"("}
+
+			// When
+			err := sut.AuditConfig.Validate()
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
 	t.Describe("ValidateRuntimeConfig", func() {
 		It("should succeed with default config", func() {
 			// Given
@@ -384,6 +462,86 @@ var _ = t.Describe("Config", func() {
 			Expect(err).NotTo(BeNil())
 		})
 
+		It("should fail wrong max exec sync output size", func() {
+			// Given
+			sut.ExecSyncOutputSizeMax = 0
+
+			// When
+			err := sut.Validate(false)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail max exec sync output size smaller than the conmon buffer", func() {
+			// Given
+			sut.ExecSyncOutputSizeMax = config.OCIBufSize - 1
+
+			// When
+			err := sut.Validate(false)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with negative namespace pool size", func() {
+			// Given
+			sut.NamespacePoolSize = -1
+
+			// When
+			err := sut.Validate(false)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with userns allocation user and ranges", func() {
+			// Given
+			sut.UsernsAllocationUser = "containers"
+			sut.UsernsAllocationRanges = []string{"100000:65536"}
+
+			// When
+			err := sut.Validate(false)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with invalid userns allocation ranges", func() {
+			// Given
+			sut.UsernsAllocationRanges = []string{invalid}
+
+			// When
+			err := sut.Validate(false)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with invalid capability policy", func() {
+			// Given
+			sut.CapabilityPolicy = invalid
+
+			// When
+			err := sut.Validate(false)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with invalid namespace allowed capabilities", func() {
+			// Given
+			sut.NamespaceAllowedCapabilities = map[string]capabilities.Capabilities{
+				"default": {"CHOWN", "WRONG"},
+			}
+
+			// When
+			err := sut.Validate(false)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
 		It("should succeed without defaultRuntime set", func() {
 			// Given
 			sut.DefaultRuntime = ""
@@ -608,6 +766,39 @@ var _ = t.Describe("Config", func() {
 			Expect(err).NotTo(BeNil())
 		})
 
+		It("should ignore a runtime handler of pod runtime_type with log_to_journald", func() {
+			// Given
+			sut.LogToJournald = true
+			sut.Runtimes["runc"] = &config.RuntimeHandler{RuntimePath: validFilePath}
+			sut.Runtimes["conmonrs"] = &config.RuntimeHandler{
+				RuntimePath: validFilePath,
+				RuntimeType: config.RuntimeTypePod,
+			}
+
+			// When
+			err := sut.RuntimeConfig.ValidateRuntimes()
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(sut.Runtimes).NotTo(HaveKey("conmonrs"))
+		})
+
+		It("should fail with log_to_journald and default runtime of pod runtime_type", func() {
+			// Given
+			sut.LogToJournald = true
+			sut.DefaultRuntime = "conmonrs"
+			sut.Runtimes["conmonrs"] = &config.RuntimeHandler{
+				RuntimePath: validFilePath,
+				RuntimeType: config.RuntimeTypePod,
+			}
+
+			// When
+			err := sut.RuntimeConfig.ValidateRuntimes()
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
 		It("should fail with wrong allowed_annotation", func() {
 			// Given
 			sut.Runtimes["runc"] = &config.RuntimeHandler{
@@ -750,6 +941,74 @@ var _ = t.Describe("Config", func() {
 			Expect(err).NotTo(BeNil())
 		})
 
+		It("should succeed with the nftables hostport backend", func() {
+			// Given
+			sut.NetworkConfig.HostPortBackend = config.HostPortBackendNFTables
+
+			// When
+			err := sut.NetworkConfig.Validate(false)
+
+			// Then
+			Expect(err).To(BeNil())
+		})
+
+		It("should fail on invalid hostport backend", func() {
+			// Given
+			sut.NetworkConfig.HostPortBackend = "ipvs"
+
+			// When
+			err := sut.NetworkConfig.Validate(false)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should succeed with disabled hostport reconciliation", func() {
+			// Given
+			sut.NetworkConfig.HostPortReconcileInterval = "0"
+
+			// When
+			err := sut.NetworkConfig.Validate(false)
+
+			// Then
+			Expect(err).To(BeNil())
+		})
+
+		It("should fail on invalid hostport reconcile interval", func() {
+			// Given
+			sut.NetworkConfig.HostPortReconcileInterval = "-1m"
+
+			// When
+			err := sut.NetworkConfig.Validate(false)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should succeed with DNS options", func() {
+			// Given
+			sut.NetworkConfig.DNSOptions = []string{"ndots:5", "timeout:2", "rotate"}
+
+			// When
+			err := sut.NetworkConfig.Validate(false)
+
+			// Then
+			Expect(err).To(BeNil())
+		})
+
+		It("should fail on invalid DNS options", func() {
+			for _, option := range []string{"", "ndots", "timeout:x", "edns0 rotate"} {
+				// Given
+				sut.NetworkConfig.DNSOptions = []string{option}
+
+				// When
+				err := sut.NetworkConfig.Validate(false)
+
+				// Then
+				Expect(err).NotTo(BeNil())
+			}
+		})
+
 		It("should succeed on having PluginDir", func() {
 			// Given
 			sut.NetworkConfig.NetworkDir = validDirPath
@@ -82,6 +82,9 @@ func (c *Config) Reload() error {
 	if err := c.ReloadAppArmorProfile(newConfig); err != nil {
 		return err
 	}
+	if err := c.ReloadAppArmorProfilesDir(newConfig); err != nil {
+		return err
+	}
 	if err := c.ReloadBlockIOConfig(newConfig); err != nil {
 		return err
 	}
@@ -217,6 +220,21 @@ func (c *Config) ReloadAppArmorProfile(newConfig *Config) error {
 	return nil
 }
 
+// ReloadAppArmorProfilesDir reloads the AppArmor profiles of the profiles
+// directory from the new config.
+func (c *Config) ReloadAppArmorProfilesDir(newConfig *Config) error {
+	// Reload the profiles in any case because the content of the directory
+	// could have changed as well
+	if err := c.AppArmor().LoadProfilesDir(newConfig.ApparmorProfilesDir); err != nil {
+		return fmt.Errorf("unable to reload apparmor_profiles_dir: %w", err)
+	}
+	if c.ApparmorProfilesDir != newConfig.ApparmorProfilesDir {
+		c.ApparmorProfilesDir = newConfig.ApparmorProfilesDir
+		logConfig("apparmor_profiles_dir", c.ApparmorProfilesDir)
+	}
+	return nil
+}
+
 // ReloadBlockIOConfig reloads the blockio configuration from the new config
 func (c *Config) ReloadBlockIOConfig(newConfig *Config) error {
 	if c.BlockIOConfigFile != newConfig.BlockIOConfigFile {
@@ -266,6 +284,7 @@ func (c *Config) ReloadRuntimes(newConfig *Config) error {
 	if err := c.ValidateRuntimes(); err != nil {
 		return fmt.Errorf("unabled to reload runtimes: %w", err)
 	}
+	c.propagateSeccompSettings()
 
 	return nil
 }
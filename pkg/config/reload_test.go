@@ -2,6 +2,7 @@ package config_test
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/containers/common/pkg/apparmor"
@@ -327,6 +328,21 @@ var _ = t.Describe("Config", func() {
 		})
 	})
 
+	t.Describe("ReloadAppArmorProfilesDir", func() {
+		It("should succeed with not existing directory", func() {
+			// Given
+			newConfig := defaultConfig()
+			newConfig.ApparmorProfilesDir = filepath.Join(t.MustTempDir("apparmor"), "not-existing")
+
+			// When
+			err := sut.ReloadAppArmorProfilesDir(newConfig)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(sut.ApparmorProfilesDir).To(Equal(newConfig.ApparmorProfilesDir))
+		})
+	})
+
 	t.Describe("ReloadRuntimes", func() {
 		It("should succeed without any config change", func() {
 			// Given
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"github.com/cri-o/cri-o/internal/config/apparmor"
+	"github.com/cri-o/cri-o/internal/config/capabilities"
+	"github.com/cri-o/cri-o/internal/config/mcs"
+	"github.com/cri-o/cri-o/internal/config/seccomp"
+	"github.com/cri-o/cri-o/internal/config/ulimits"
+	criotypes "github.com/cri-o/cri-o/pkg/types"
+)
+
+// runtimeHandler returns the runtime handler for the provided name or the
+// default runtime handler if the name is empty. It returns nil if the runtime
+// handler does not exist.
+func (c *RuntimeConfig) runtimeHandler(name string) *RuntimeHandler {
+	if name == "" {
+		name = c.DefaultRuntime
+	}
+	return c.Runtimes[name]
+}
+
+// DefaultCapabilitiesForHandler returns the default capabilities of the
+// provided runtime handler, falling back to the global ones.
+func (c *RuntimeConfig) DefaultCapabilitiesForHandler(name string) capabilities.Capabilities {
+	if rh := c.runtimeHandler(name); rh != nil && rh.DefaultCapabilities != nil {
+		return rh.DefaultCapabilities
+	}
+	return c.DefaultCapabilities
+}
+
+// AllowedCapabilitiesFor returns the lists of capabilities which containers
+// of the provided runtime handler and Kubernetes namespace are allowed to use.
+// A capability has to be allowed by every list. There are no restrictions if
+// the returned slice is empty.
+func (c *RuntimeConfig) AllowedCapabilitiesFor(handler, namespace string) []capabilities.Capabilities {
+	allowed := []capabilities.Capabilities{}
+	if rh := c.runtimeHandler(handler); rh != nil && rh.AllowedCapabilities != nil {
+		allowed = append(allowed, rh.AllowedCapabilities)
+	}
+	if caps, ok := c.NamespaceAllowedCapabilities[namespace]; ok {
+		allowed = append(allowed, caps)
+	}
+	return allowed
+}
+
+// SysctlsForHandler returns the parsed default sysctls of the provided runtime
+// handler, falling back to the global ones.
+func (c *RuntimeConfig) SysctlsForHandler(name string) ([]Sysctl, error) {
+	if rh := c.runtimeHandler(name); rh != nil && len(rh.DefaultSysctls) > 0 {
+		return parseSysctls(rh.DefaultSysctls)
+	}
+	return c.Sysctls()
+}
+
+// UlimitsForHandler returns the default ulimits of the provided runtime
+// handler, falling back to the global ones.
+func (c *RuntimeConfig) UlimitsForHandler(name string) []ulimits.Ulimit {
+	if rh := c.runtimeHandler(name); rh != nil && rh.ulimitsConfig != nil {
+		return rh.ulimitsConfig.Ulimits()
+	}
+	return c.Ulimits()
+}
+
+// SeccompForHandler returns the seccomp configuration of the provided runtime
+// handler, falling back to the global one.
+func (c *RuntimeConfig) SeccompForHandler(name string) *seccomp.Config {
+	if rh := c.runtimeHandler(name); rh != nil && rh.seccompConfig != nil {
+		return rh.seccompConfig
+	}
+	return c.Seccomp()
+}
+
+// AppArmorForHandler returns the AppArmor configuration of the provided
+// runtime handler, falling back to the global one.
+func (c *RuntimeConfig) AppArmorForHandler(name string) *apparmor.Config {
+	if rh := c.runtimeHandler(name); rh != nil && rh.apparmorConfig != nil {
+		return rh.apparmorConfig
+	}
+	return c.AppArmor()
+}
+
+// PidsLimitForHandler returns the pids limit of the provided runtime handler,
+// falling back to the global one.
+func (c *RuntimeConfig) PidsLimitForHandler(name string) int64 {
+	if rh := c.runtimeHandler(name); rh != nil && rh.PidsLimit != 0 {
+		return rh.PidsLimit
+	}
+	return c.PidsLimit
+}
+
+// DefaultMountsFileForHandler returns the default mounts file of the provided
+// runtime handler, falling back to the global one.
+func (c *RuntimeConfig) DefaultMountsFileForHandler(name string) string {
+	if rh := c.runtimeHandler(name); rh != nil && rh.DefaultMountsFile != "" {
+		return rh.DefaultMountsFile
+	}
+	return c.DefaultMountsFile
+}
+
+// CtrStopTimeoutForHandler returns the grace period in seconds CRI-O uses when
+// stopping containers of the provided runtime handler on its own, or zero if
+// not configured.
+func (c *RuntimeConfig) CtrStopTimeoutForHandler(name string) int64 {
+	if rh := c.runtimeHandler(name); rh != nil {
+		return rh.CtrStopTimeout
+	}
+	return 0
+}
+
+// StopSignalChainForHandler returns the stop signal escalation chain of the
+// provided runtime handler, or an empty string if not configured.
+func (c *RuntimeConfig) StopSignalChainForHandler(name string) string {
+	if rh := c.runtimeHandler(name); rh != nil {
+		return rh.StopSignalChain
+	}
+	return ""
+}
+
+// propagateSeccompSettings applies the global seccomp settings to the seccomp
+// configurations of the runtime handlers overriding the profile.
+func (c *RuntimeConfig) propagateSeccompSettings() {
+	for _, rh := range c.Runtimes {
+		if rh.seccompConfig == nil {
+			continue
+		}
+		rh.seccompConfig.SetUseDefaultWhenEmpty(c.SeccompUseDefaultWhenEmpty)
+		rh.seccompConfig.SetNotifierPath(c.seccompConfig.NotifierPath())
+		rh.seccompConfig.SetArtifactPuller(c.seccompConfig.ArtifactPuller())
+	}
+}
+
+// SetSeccompArtifactPuller sets the puller for seccomp profiles referenced as
+// OCI artifacts of the global and the runtime handler seccomp configurations.
+func (c *RuntimeConfig) SetSeccompArtifactPuller(puller seccomp.ArtifactPuller) {
+	c.seccompConfig.SetArtifactPuller(puller)
+	c.propagateSeccompSettings()
+}
+
+// RuntimeFeaturesForHandler returns the discovered features of the provided
+// runtime handler or nil if they are unknown.
+func (c *RuntimeConfig) RuntimeFeaturesForHandler(name string) *criotypes.RuntimeFeatures {
+	if rh := c.runtimeHandler(name); rh != nil {
+		return rh.RuntimeFeatures()
+	}
+	return nil
+}
+
+// MCSRangeForHandler returns the range of MCS categories from which the
+// SELinux levels of pods of the provided runtime handler are allocated,
+// falling back to all categories.
+func (c *RuntimeConfig) MCSRangeForHandler(name string) mcs.Range {
+	if rh := c.runtimeHandler(name); rh != nil && rh.mcsRange != nil {
+		return *rh.mcsRange
+	}
+	return mcs.DefaultRange()
+}
@@ -0,0 +1,167 @@
+package config_test
+
+import (
+	"github.com/cri-o/cri-o/internal/config/capabilities"
+	"github.com/cri-o/cri-o/internal/config/mcs"
+	"github.com/cri-o/cri-o/pkg/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// The actual test suite
+var _ = t.Describe("RuntimeDefaults", func() {
+	BeforeEach(beforeEach)
+
+	t.Describe("ValidateRuntimeDefaults", func() {
+		It("should succeed without overrides", func() {
+			// Given
+			handler := &config.RuntimeHandler{}
+
+			// When
+			err := handler.ValidateRuntimeDefaults("runtime")
+
+			// Then
+			Expect(err).To(BeNil())
+		})
+
+		It("should fail with invalid capabilities", func() {
+			// Given
+			handler := &config.RuntimeHandler{
+				DefaultCapabilities: capabilities.Capabilities{"NOT_A_CAP"},
+			}
+
+			// When
+			err := handler.ValidateRuntimeDefaults("runtime")
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with invalid sysctls", func() {
+			// Given
+			handler := &config.RuntimeHandler{DefaultSysctls: []string{"invalid"}}
+
+			// When
+			err := handler.ValidateRuntimeDefaults("runtime")
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with invalid ulimits", func() {
+			// Given
+			handler := &config.RuntimeHandler{DefaultUlimits: []string{"invalid"}}
+
+			// When
+			err := handler.ValidateRuntimeDefaults("runtime")
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with invalid pids limit", func() {
+			// Given
+			handler := &config.RuntimeHandler{PidsLimit: -2}
+
+			// When
+			err := handler.ValidateRuntimeDefaults("runtime")
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with negative stop timeout", func() {
+			// Given
+			handler := &config.RuntimeHandler{CtrStopTimeout: -1}
+
+			// When
+			err := handler.ValidateRuntimeDefaults("runtime")
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should succeed with a stop timeout lower than the global one", func() {
+			// Given
+			handler := &config.RuntimeHandler{CtrStopTimeout: 5}
+
+			// When
+			err := handler.ValidateRuntimeDefaults("runtime")
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(handler.CtrStopTimeout).To(BeEquivalentTo(5))
+		})
+
+		It("should fail with invalid MCS categories", func() {
+			// Given
+			handler := &config.RuntimeHandler{MCSCategories: "c10.c5"}
+
+			// When
+			err := handler.ValidateRuntimeDefaults("runtime")
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with non existing default mounts file", func() {
+			// Given
+			handler := &config.RuntimeHandler{DefaultMountsFile: invalidPath}
+
+			// When
+			err := handler.ValidateRuntimeDefaults("runtime")
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	t.Describe("ForHandler", func() {
+		It("should fall back to the global values", func() {
+			// Given
+			sut.PidsLimit = 1024
+			sut.DefaultMountsFile = validFilePath
+			sut.Runtimes["other"] = &config.RuntimeHandler{}
+
+			// When
+			pidsLimit := sut.PidsLimitForHandler("other")
+			mountsFile := sut.DefaultMountsFileForHandler("other")
+
+			// Then
+			Expect(pidsLimit).To(BeEquivalentTo(1024))
+			Expect(mountsFile).To(Equal(validFilePath))
+			Expect(sut.DefaultCapabilitiesForHandler("other")).To(Equal(sut.DefaultCapabilities))
+			Expect(sut.SeccompForHandler("other")).To(Equal(sut.Seccomp()))
+			Expect(sut.CtrStopTimeoutForHandler("other")).To(BeZero())
+			Expect(sut.MCSRangeForHandler("other")).To(Equal(mcs.DefaultRange()))
+			Expect(sut.RuntimeFeaturesForHandler("other")).To(BeNil())
+			Expect(sut.RuntimeFeaturesForHandler("missing")).To(BeNil())
+		})
+
+		It("should use the runtime handler values", func() {
+			// Given
+			sut.Runtimes["other"] = &config.RuntimeHandler{
+				DefaultCapabilities: capabilities.Capabilities{"CHOWN"},
+				DefaultSysctls:      []string{"net.ipv4.ping_group_range=0 2147483647"},
+				DefaultUlimits:      []string{"nofile=1024:2048"},
+				PidsLimit:           42,
+				CtrStopTimeout:      60,
+				MCSCategories:       "c0.c511",
+			}
+			Expect(sut.Runtimes["other"].ValidateRuntimeDefaults("other")).To(BeNil())
+
+			// When
+			sysctls, err := sut.SysctlsForHandler("other")
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(sysctls).To(HaveLen(1))
+			Expect(sysctls[0].Key()).To(Equal("net.ipv4.ping_group_range"))
+			Expect(sut.UlimitsForHandler("other")).To(HaveLen(1))
+			Expect(sut.DefaultCapabilitiesForHandler("other")).To(ConsistOf("CHOWN"))
+			Expect(sut.PidsLimitForHandler("other")).To(BeEquivalentTo(42))
+			Expect(sut.CtrStopTimeoutForHandler("other")).To(BeEquivalentTo(60))
+			Expect(sut.MCSRangeForHandler("other")).To(Equal(mcs.Range{Min: 0, Max: 511}))
+		})
+	})
+})
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	criotypes "github.com/cri-o/cri-o/pkg/types"
+	"github.com/cri-o/cri-o/utils/cmdrunner"
+	json "github.com/json-iterator/go"
+	"github.com/sirupsen/logrus"
+)
+
+// runtimeFeaturesTimeout is the maximum time to wait for the `features`
+// subcommand of a runtime to finish.
+const runtimeFeaturesTimeout = 10 * time.Second
+
+// Features returns the discovered features for every runtime handler.
+// Handlers whose features are unknown map to nil.
+func (r Runtimes) Features() map[string]*criotypes.RuntimeFeatures {
+	features := make(map[string]*criotypes.RuntimeFeatures, len(r))
+	for name, handler := range r {
+		features[name] = handler.RuntimeFeatures()
+	}
+	return features
+}
+
+// RuntimeFeatures returns the features discovered for the runtime handler or
+// nil if they are unknown.
+func (r *RuntimeHandler) RuntimeFeatures() *criotypes.RuntimeFeatures {
+	return r.features
+}
+
+// LoadRuntimeFeatures runs the `features` subcommand of the runtime and caches
+// its result. VM based runtimes do not provide such a subcommand, which means
+// that their features stay unknown.
+func (r *RuntimeHandler) LoadRuntimeFeatures(name string) error {
+	r.features = nil
+	if r.RuntimeType == RuntimeTypeVM {
+		logrus.Debugf("Skipping features discovery for VM runtime %q", name)
+		return nil
+	}
+
+	cmd := cmdrunner.Command(r.RuntimePath, "features")
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start %s features: %w", r.RuntimePath, err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("run %s features: %s: %w", r.RuntimePath, strings.TrimSpace(stderr.String()), err)
+		}
+	case <-time.After(runtimeFeaturesTimeout):
+		if err := cmd.Process.Kill(); err != nil {
+			logrus.Debugf("Unable to kill %s features: %v", r.RuntimePath, err)
+		}
+		<-done
+		return fmt.Errorf("run %s features: timed out after %v", r.RuntimePath, runtimeFeaturesTimeout)
+	}
+
+	features := &criotypes.RuntimeFeatures{}
+	if err := json.Unmarshal(stdout.Bytes(), features); err != nil {
+		return fmt.Errorf("parse %s features: %w", r.RuntimePath, err)
+	}
+	r.features = features
+	logrus.Infof("Discovered features of runtime %q: OCI version %s - %s", name, features.OCIVersionMin, features.OCIVersionMax)
+	return nil
+}
@@ -0,0 +1,249 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/cri-o/cri-o/internal/config/node"
+	"github.com/cri-o/cri-o/pkg/config"
+	"github.com/cri-o/cri-o/pkg/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-tools/generate"
+)
+
+// runcFeatures is the output of `runc features` of runc 1.1.12.
+const runcFeatures = `{
+  "ociVersionMin": "1.0.0",
+  "ociVersionMax": "1.1.0+dev",
+  "hooks": ["prestart", "createRuntime", "createContainer", "startContainer", "poststart", "poststop"],
+  "mountOptions": [
+    "acl", "async", "atime", "bind", "defaults", "dev", "diratime", "dirsync",
+    "exec", "iversion", "lazytime", "loud", "mand", "noacl", "noatime", "nodev",
+    "nodiratime", "noexec", "noiversion", "nolazytime", "nomand", "norelatime",
+    "nostrictatime", "nosuid", "nosymfollow", "private", "ratime", "rbind",
+    "rdev", "rdiratime", "relatime", "remount", "rexec", "rnoatime", "rnodev",
+    "rnodiratime", "rnoexec", "rnorelatime", "rnostrictatime", "rnosuid",
+    "rnosymfollow", "ro", "rprivate", "rrelatime", "rro", "rrw", "rshared",
+    "rslave", "rstrictatime", "rsuid", "rsymfollow", "runbindable", "rw",
+    "shared", "silent", "slave", "strictatime", "suid", "symfollow", "sync",
+    "tmpcopyup", "unbindable"
+  ],
+  "linux": {
+    "namespaces": ["cgroup", "ipc", "mount", "network", "pid", "user", "uts"],
+    "capabilities": ["CAP_CHOWN", "CAP_DAC_OVERRIDE", "CAP_FOWNER", "CAP_FSETID", "CAP_KILL", "CAP_SETGID", "CAP_SETUID", "CAP_SETPCAP", "CAP_NET_BIND_SERVICE", "CAP_SYS_CHROOT"],
+    "cgroup": {"v1": true, "v2": true, "systemd": true, "systemdUser": true},
+    "seccomp": {
+      "enabled": true,
+      "actions": ["SCMP_ACT_ALLOW", "SCMP_ACT_ERRNO", "SCMP_ACT_KILL", "SCMP_ACT_KILL_PROCESS", "SCMP_ACT_KILL_THREAD", "SCMP_ACT_LOG", "SCMP_ACT_NOTIFY", "SCMP_ACT_TRACE", "SCMP_ACT_TRAP"],
+      "operators": ["SCMP_CMP_EQ", "SCMP_CMP_GE", "SCMP_CMP_GT", "SCMP_CMP_LE", "SCMP_CMP_LT", "SCMP_CMP_MASKED_EQ", "SCMP_CMP_NE"],
+      "archs": ["SCMP_ARCH_AARCH64", "SCMP_ARCH_ARM", "SCMP_ARCH_X86", "SCMP_ARCH_X86_64"]
+    },
+    "apparmor": {"enabled": true},
+    "selinux": {"enabled": true}
+  },
+  "annotations": {
+    "io.github.seccomp.libseccomp.version": "2.5.4",
+    "org.opencontainers.runc.checkpoint.enabled": "true",
+    "org.opencontainers.runc.commit": "v1.1.12-0-g51d5e946",
+    "org.opencontainers.runc.version": "1.1.12"
+  }
+}`
+
+// The actual test suite
+var _ = t.Describe("RuntimeFeatures", func() {
+	writeRuntime := func(script string) string {
+		path := filepath.Join(t.MustTempDir("runtime"), "runtime")
+		Expect(os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o755)).To(BeNil())
+		return path
+	}
+
+	t.Describe("LoadRuntimeFeatures", func() {
+		It("should succeed to parse the features", func() {
+			// Given
+			handler := &config.RuntimeHandler{
+				RuntimePath: writeRuntime(`echo '{"ociVersionMin":"1.0.0","ociVersionMax":"1.1.0","mountOptions":["bind","ro"]}'`),
+			}
+
+			// When
+			err := handler.LoadRuntimeFeatures("runtime")
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(handler.RuntimeFeatures()).NotTo(BeNil())
+			Expect(handler.RuntimeFeatures().OCIVersionMax).To(Equal("1.1.0"))
+			Expect(handler.RuntimeFeatures().MountOptions).To(ConsistOf("bind", "ro"))
+		})
+
+		It("should fail if the runtime does not support features", func() {
+			// Given
+			handler := &config.RuntimeHandler{
+				RuntimePath: writeRuntime("exit 1"),
+			}
+
+			// When
+			err := handler.LoadRuntimeFeatures("runtime")
+
+			// Then
+			Expect(err).NotTo(BeNil())
+			Expect(handler.RuntimeFeatures()).To(BeNil())
+		})
+
+		It("should skip VM runtimes", func() {
+			// Given
+			handler := &config.RuntimeHandler{
+				RuntimePath: invalidPath,
+				RuntimeType: config.RuntimeTypeVM,
+			}
+
+			// When
+			err := handler.LoadRuntimeFeatures("runtime")
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(handler.RuntimeFeatures()).To(BeNil())
+		})
+	})
+
+	t.Describe("ValidateRuntimeFeatures", func() {
+		It("should fail without cgroup v2 support only on cgroup v2 nodes", func() {
+			// Given
+			handler := &config.RuntimeHandler{
+				RuntimePath: writeRuntime(`echo '{"linux":{"cgroup":{"v1":true,"v2":false}}}'`),
+			}
+
+			// When
+			err := handler.ValidateRuntimeFeatures("runtime")
+
+			// Then
+			if node.CgroupIsV2() {
+				Expect(err).NotTo(BeNil())
+			} else {
+				Expect(err).To(BeNil())
+			}
+		})
+	})
+
+	t.Describe("ValidateSpec", func() {
+		enabled := true
+		disabled := false
+
+		It("should succeed with unknown features", func() {
+			// Given
+			var features *types.RuntimeFeatures
+
+			// When
+			err := features.ValidateSpec(&rspec.Spec{
+				Mounts: []rspec.Mount{{Destination: "/foo", Options: []string{"unknown"}}},
+			})
+
+			// Then
+			Expect(err).To(BeNil())
+		})
+
+		It("should succeed with supported mount options", func() {
+			// Given
+			features := &types.RuntimeFeatures{MountOptions: []string{"bind", "ro"}}
+
+			// When
+			err := features.ValidateSpec(&rspec.Spec{
+				Mounts: []rspec.Mount{{Destination: "/foo", Options: []string{"bind", "ro", "mode=755"}}},
+			})
+
+			// Then
+			Expect(err).To(BeNil())
+		})
+
+		It("should fail with unsupported mount options", func() {
+			// Given
+			features := &types.RuntimeFeatures{MountOptions: []string{"bind"}}
+
+			// When
+			err := features.ValidateSpec(&rspec.Spec{
+				Mounts: []rspec.Mount{{Destination: "/foo", Options: []string{"bind", "ro"}}},
+			})
+
+			// Then
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`"ro"`))
+		})
+
+		It("should succeed with filesystem specific mount options", func() {
+			// Given
+			features := &types.RuntimeFeatures{MountOptions: []string{"nosuid", "noexec"}}
+
+			// When
+			err := features.ValidateSpec(&rspec.Spec{
+				Mounts: []rspec.Mount{{Destination: "/dev/pts", Options: []string{"nosuid", "noexec", "newinstance", "ptmxmode=0666"}}},
+			})
+
+			// Then
+			Expect(err).To(BeNil())
+		})
+
+		It("should succeed with the default spec and the features of runc", func() {
+			// Given
+			handler := &config.RuntimeHandler{
+				RuntimePath: writeRuntime("cat <<'EOF'\n" + runcFeatures + "\nEOF\n"),
+			}
+			Expect(handler.LoadRuntimeFeatures("runc")).To(BeNil())
+			Expect(handler.RuntimeFeatures().MountOptions).To(ContainElement("tmpcopyup"))
+			generator, err := generate.New("linux")
+			Expect(err).To(BeNil())
+
+			// When
+			err = handler.RuntimeFeatures().ValidateSpec(generator.Config)
+
+			// Then
+			Expect(err).To(BeNil())
+		})
+
+		It("should fail with unsupported seccomp actions", func() {
+			// Given
+			features := &types.RuntimeFeatures{Linux: &types.LinuxRuntimeFeatures{
+				Seccomp: &types.SeccompRuntimeFeatures{
+					Enabled: &enabled,
+					Actions: []string{string(rspec.ActErrno), string(rspec.ActAllow)},
+				},
+			}}
+
+			// When
+			err := features.ValidateSpec(&rspec.Spec{Linux: &rspec.Linux{
+				Seccomp: &rspec.LinuxSeccomp{
+					DefaultAction: rspec.ActErrno,
+					Syscalls: []rspec.LinuxSyscall{
+						{Names: []string{"read"}, Action: rspec.ActAllow},
+						{Names: []string{"write"}, Action: rspec.ActNotify},
+					},
+				},
+			}})
+
+			// Then
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring(string(rspec.ActNotify)))
+		})
+
+		It("should fail with idmapped mounts if unsupported", func() {
+			// Given
+			features := &types.RuntimeFeatures{Linux: &types.LinuxRuntimeFeatures{
+				MountExtensions: &types.MountExtensionsRuntimeFeatures{
+					IDMap: &types.IDMapRuntimeFeatures{Enabled: &disabled},
+				},
+			}}
+
+			// When
+			err := features.ValidateSpec(&rspec.Spec{
+				Linux: &rspec.Linux{},
+				Mounts: []rspec.Mount{{
+					Destination: "/foo",
+					UIDMappings: []rspec.LinuxIDMapping{{ContainerID: 0, HostID: 1000, Size: 1}},
+				}},
+			})
+
+			// Then
+			Expect(err).NotTo(BeNil())
+			Expect(features.SupportsIDMappedMounts()).To(BeFalse())
+		})
+	})
+})
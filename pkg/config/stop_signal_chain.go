@@ -0,0 +1,110 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/containers/common/pkg/signal"
+	"golang.org/x/sys/unix"
+)
+
+// stopSignalChainExecPrefix is the prefix of stop signal chain steps which
+// run a command inside the container instead of sending a signal.
+const stopSignalChainExecPrefix = "exec="
+
+// StopSignalChainStep is a single step of a stop signal escalation chain.
+type StopSignalChainStep struct {
+	// Signal is the signal sent to the container. It is unset for exec
+	// steps.
+	Signal syscall.Signal
+
+	// Exec is the command run inside the container. It is unset for signal
+	// steps.
+	Exec []string
+
+	// Timeout is the time to wait for the container to exit before
+	// escalating to the next step. Zero means the remaining grace period.
+	Timeout time.Duration
+}
+
+// StopSignalChain is a sequence of steps used to stop a container within its
+// grace period. The container gets killed with SIGKILL if it did not exit
+// once the chain is exhausted or the grace period expired.
+type StopSignalChain []StopSignalChainStep
+
+// ParseStopSignalChain parses a comma separated list of steps in the form
+// `SIGNAL[:TIMEOUT]` or `exec=COMMAND[:TIMEOUT]`, for example
+// `exec=/bin/pre-stop --now:5s,SIGTERM:10s,SIGINT`. An empty string results
+// in an empty chain.
+func ParseStopSignalChain(s string) (StopSignalChain, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	rawSteps := strings.Split(s, ",")
+	chain := make(StopSignalChain, 0, len(rawSteps))
+	for i, rawStep := range rawSteps {
+		rawStep = strings.TrimSpace(rawStep)
+		step := StopSignalChainStep{}
+
+		if idx := strings.LastIndex(rawStep, ":"); idx != -1 {
+			if timeout, err := time.ParseDuration(rawStep[idx+1:]); err == nil {
+				if timeout < 0 {
+					return nil, fmt.Errorf("step %q: negative timeout", rawStep)
+				}
+				step.Timeout = timeout
+				rawStep = rawStep[:idx]
+			}
+		}
+
+		if strings.HasPrefix(rawStep, stopSignalChainExecPrefix) {
+			step.Exec = strings.Fields(strings.TrimPrefix(rawStep, stopSignalChainExecPrefix))
+			if len(step.Exec) == 0 {
+				return nil, fmt.Errorf("step %d: empty exec command", i+1)
+			}
+		} else {
+			sig, err := signal.ParseSignal(rawStep)
+			if err != nil {
+				return nil, fmt.Errorf("step %d: %w", i+1, err)
+			}
+			if sig == syscall.SIGKILL && i != len(rawSteps)-1 {
+				return nil, errors.New("SIGKILL can only be the last step")
+			}
+			step.Signal = sig
+		}
+
+		chain = append(chain, step)
+	}
+	return chain, nil
+}
+
+// String returns the string representation of the step, which can be parsed
+// by ParseStopSignalChain.
+func (s *StopSignalChainStep) String() string {
+	var res string
+	if len(s.Exec) > 0 {
+		res = stopSignalChainExecPrefix + strings.Join(s.Exec, " ")
+	} else {
+		res = unix.SignalName(s.Signal)
+		if res == "" {
+			res = fmt.Sprintf("%d", s.Signal)
+		}
+	}
+	if s.Timeout > 0 {
+		res += ":" + s.Timeout.String()
+	}
+	return res
+}
+
+// String returns the string representation of the chain, which can be parsed
+// by ParseStopSignalChain.
+func (c StopSignalChain) String() string {
+	steps := make([]string, 0, len(c))
+	for i := range c {
+		steps = append(steps, c[i].String())
+	}
+	return strings.Join(steps, ",")
+}
@@ -0,0 +1,72 @@
+package config_test
+
+import (
+	"syscall"
+	"time"
+
+	"github.com/cri-o/cri-o/pkg/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// The actual test suite
+var _ = t.Describe("StopSignalChain", func() {
+	t.Describe("ParseStopSignalChain", func() {
+		It("should succeed with an empty chain", func() {
+			// Given
+			// When
+			chain, err := config.ParseStopSignalChain("")
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(chain).To(BeEmpty())
+		})
+
+		It("should succeed to parse signals and exec steps", func() {
+			// Given
+			const s = "exec=/bin/pre-stop --now:5s,SIGTERM:10s,INT,SIGKILL"
+
+			// When
+			chain, err := config.ParseStopSignalChain(s)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(chain).To(HaveLen(4))
+			Expect(chain[0].Exec).To(Equal([]string{"/bin/pre-stop", "--now"}))
+			Expect(chain[0].Timeout).To(Equal(5 * time.Second))
+			Expect(chain[1].Signal).To(Equal(syscall.SIGTERM))
+			Expect(chain[1].Timeout).To(Equal(10 * time.Second))
+			Expect(chain[2].Signal).To(Equal(syscall.SIGINT))
+			Expect(chain[2].Timeout).To(BeZero())
+			Expect(chain[3].Signal).To(Equal(syscall.SIGKILL))
+			Expect(chain.String()).To(Equal("exec=/bin/pre-stop --now:5s,SIGTERM:10s,SIGINT,SIGKILL"))
+		})
+
+		It("should fail with an invalid signal", func() {
+			// Given
+			// When
+			_, err := config.ParseStopSignalChain("SIGFOO:10s")
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with an empty exec command", func() {
+			// Given
+			// When
+			_, err := config.ParseStopSignalChain("exec=:10s")
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail if SIGKILL is not the last step", func() {
+			// Given
+			// When
+			_, err := config.ParseStopSignalChain("SIGKILL,SIGTERM")
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+	})
+})
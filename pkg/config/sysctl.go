@@ -27,8 +27,12 @@ func (s *Sysctl) Value() string {
 // Sysctls returns the parsed sysctl slice and an error if not parsable
 // Some validation based on https://github.com/containers/common/blob/main/pkg/sysctl/sysctl.go
 func (c *RuntimeConfig) Sysctls() ([]Sysctl, error) {
-	sysctls := make([]Sysctl, 0, len(c.DefaultSysctls))
-	for _, sysctl := range c.DefaultSysctls {
+	return parseSysctls(c.DefaultSysctls)
+}
+
+func parseSysctls(defaultSysctls []string) ([]Sysctl, error) {
+	sysctls := make([]Sysctl, 0, len(defaultSysctls))
+	for _, sysctl := range defaultSysctls {
 		// skip empty values for sake of backwards compatibility
 		if sysctl == "" {
 			continue
@@ -51,6 +51,9 @@ func assembleTemplateString(displayAllConfig bool, c *Config) string {
 	// [crio.stats] configuration
 	templateString += crioTemplateString(crioStatsConfig, templateStringCrioStats, displayAllConfig, crioTemplateConfig)
 
+	// [crio.audit] configuration
+	templateString += crioTemplateString(crioAuditConfig, templateStringCrioAudit, displayAllConfig, crioTemplateConfig)
+
 	if templateString != "" {
 		templateString = templateStringPrefix + templateStringCrio + templateString
 	}
@@ -90,6 +93,7 @@ const (
 	crioTracingConfig
 	crioStatsConfig
 	crioNRIConfig
+	crioAuditConfig
 )
 
 type templateConfigValue struct {
@@ -240,6 +244,11 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioRuntimeConfig,
 			isDefaultValue: simpleEqual(dc.SELinux, c.SELinux),
 		},
+		{
+			templateString: templateStringCrioRuntimeSelinuxMCSAllocationFile,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.SELinuxMCSAllocationFile, c.SELinuxMCSAllocationFile),
+		},
 		{
 			templateString: templateStringCrioRuntimeSeccompProfile,
 			group:          crioRuntimeConfig,
@@ -250,11 +259,21 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioRuntimeConfig,
 			isDefaultValue: simpleEqual(dc.SeccompUseDefaultWhenEmpty, c.SeccompUseDefaultWhenEmpty),
 		},
+		{
+			templateString: templateStringCrioRuntimeSeccompRecordDir,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.SeccompRecordDir, c.SeccompRecordDir),
+		},
 		{
 			templateString: templateStringCrioRuntimeApparmorProfile,
 			group:          crioRuntimeConfig,
 			isDefaultValue: simpleEqual(dc.ApparmorProfile, c.ApparmorProfile),
 		},
+		{
+			templateString: templateStringCrioRuntimeApparmorProfilesDir,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.ApparmorProfilesDir, c.ApparmorProfilesDir),
+		},
 		{
 			templateString: templateStringCrioRuntimeBlockIOConfigFile,
 			group:          crioRuntimeConfig,
@@ -295,6 +314,11 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioRuntimeConfig,
 			isDefaultValue: simpleEqual(dc.AddInheritableCapabilities, c.AddInheritableCapabilities),
 		},
+		{
+			templateString: templateStringCrioRuntimeCapabilityPolicy,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.CapabilityPolicy, c.CapabilityPolicy),
+		},
 		{
 			templateString: templateStringCrioRuntimeDefaultSysctls,
 			group:          crioRuntimeConfig,
@@ -340,6 +364,11 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioRuntimeConfig,
 			isDefaultValue: simpleEqual(dc.LogSizeMax, c.LogSizeMax),
 		},
+		{
+			templateString: templateStringCrioRuntimeExecSyncOutputSizeMax,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.ExecSyncOutputSizeMax, c.ExecSyncOutputSizeMax),
+		},
 		{
 			templateString: templateStringCrioRuntimeLogToJournald,
 			group:          crioRuntimeConfig,
@@ -395,6 +424,21 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioRuntimeConfig,
 			isDefaultValue: simpleEqual(dc.MinimumMappableGID, c.MinimumMappableGID),
 		},
+		{
+			templateString: templateStringCrioRuntimeUsernsAllocationUser,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.UsernsAllocationUser, c.UsernsAllocationUser),
+		},
+		{
+			templateString: templateStringCrioRuntimeUsernsAllocationRanges,
+			group:          crioRuntimeConfig,
+			isDefaultValue: stringSliceEqual(dc.UsernsAllocationRanges, c.UsernsAllocationRanges),
+		},
+		{
+			templateString: templateStringCrioRuntimeUsernsAllocationFile,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.UsernsAllocationFile, c.UsernsAllocationFile),
+		},
 		{
 			templateString: templateStringCrioRuntimeCtrStopTimeout,
 			group:          crioRuntimeConfig,
@@ -415,6 +459,11 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioRuntimeConfig,
 			isDefaultValue: simpleEqual(dc.NamespacesDir, c.NamespacesDir),
 		},
+		{
+			templateString: templateStringCrioRuntimeNamespacePoolSize,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.NamespacePoolSize, c.NamespacePoolSize),
+		},
 		{
 			templateString: templateStringCrioRuntimePinnsPath,
 			group:          crioRuntimeConfig,
@@ -440,6 +489,11 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioRuntimeConfig,
 			isDefaultValue: stringSliceEqual(dc.AbsentMountSourcesToReject, c.AbsentMountSourcesToReject),
 		},
+		{
+			templateString: templateStringCrioRuntimeNamespaceAllowedCapabilities,
+			group:          crioRuntimeConfig,
+			isDefaultValue: reflect.DeepEqual(dc.NamespaceAllowedCapabilities, c.NamespaceAllowedCapabilities),
+		},
 		{
 			templateString: templateStringCrioRuntimeRuntimesRuntimeHandler,
 			group:          crioRuntimeConfig,
@@ -510,6 +564,31 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioNetworkConfig,
 			isDefaultValue: stringSliceEqual(dc.PluginDirs, c.PluginDirs),
 		},
+		{
+			templateString: templateStringCrioNetworkHostPortBackend,
+			group:          crioNetworkConfig,
+			isDefaultValue: simpleEqual(dc.HostPortBackend, c.HostPortBackend),
+		},
+		{
+			templateString: templateStringCrioNetworkHostPortReconcileInterval,
+			group:          crioNetworkConfig,
+			isDefaultValue: simpleEqual(dc.HostPortReconcileInterval, c.HostPortReconcileInterval),
+		},
+		{
+			templateString: templateStringCrioNetworkEnableBandwidthFallback,
+			group:          crioNetworkConfig,
+			isDefaultValue: simpleEqual(dc.EnableBandwidthFallback, c.EnableBandwidthFallback),
+		},
+		{
+			templateString: templateStringCrioNetworkDNSOptions,
+			group:          crioNetworkConfig,
+			isDefaultValue: stringSliceEqual(dc.DNSOptions, c.DNSOptions),
+		},
+		{
+			templateString: templateStringCrioNetworkDNSUpstreamResolvConf,
+			group:          crioNetworkConfig,
+			isDefaultValue: simpleEqual(dc.DNSUpstreamResolvConf, c.DNSUpstreamResolvConf),
+		},
 		{
 			templateString: templateStringCrioMetricsEnableMetrics,
 			group:          crioMetricsConfig,
@@ -560,6 +639,36 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioStatsConfig,
 			isDefaultValue: simpleEqual(dc.StatsCollectionPeriod, c.StatsCollectionPeriod),
 		},
+		{
+			templateString: templateStringCrioAuditAuditLogPath,
+			group:          crioAuditConfig,
+			isDefaultValue: simpleEqual(dc.AuditLogPath, c.AuditLogPath),
+		},
+		{
+			templateString: templateStringCrioAuditAuditLogToJournald,
+			group:          crioAuditConfig,
+			isDefaultValue: simpleEqual(dc.AuditLogToJournald, c.AuditLogToJournald),
+		},
+		{
+			templateString: templateStringCrioAuditAuditLogMaxSize,
+			group:          crioAuditConfig,
+			isDefaultValue: simpleEqual(dc.AuditLogMaxSize, c.AuditLogMaxSize),
+		},
+		{
+			templateString: templateStringCrioAuditAuditLogMaxBackups,
+			group:          crioAuditConfig,
+			isDefaultValue: simpleEqual(dc.AuditLogMaxBackups, c.AuditLogMaxBackups),
+		},
+		{
+			templateString: templateStringCrioAuditAuditLogMaxAge,
+			group:          crioAuditConfig,
+			isDefaultValue: simpleEqual(dc.AuditLogMaxAge, c.AuditLogMaxAge),
+		},
+		{
+			templateString: templateStringCrioAuditAuditRedactPatterns,
+			group:          crioAuditConfig,
+			isDefaultValue: stringSliceEqual(dc.AuditRedactPatterns, c.AuditRedactPatterns),
+		},
 		{
 			templateString: templateStringCrioNRIEnable,
 			group:          crioNRIConfig,
@@ -865,9 +974,20 @@ const templateStringCrioRuntimeSelinux = `# If true, SELinux will be used for po
 
 `
 
+const templateStringCrioRuntimeSelinuxMCSAllocationFile = `# File where the SELinux MCS levels allocated to pods are persisted, which
+# ensures that the levels stay unique across restarts. The levels are allocated
+# from the mcs_categories of the runtime handler of a pod. If empty, the levels
+# are allocated by the SELinux library without persistence.
+{{ $.Comment }}selinux_mcs_allocation_file = "{{ .SELinuxMCSAllocationFile }}"
+
+`
+
 const templateStringCrioRuntimeSeccompProfile = `# Path to the seccomp.json profile which is used as the default seccomp profile
 # for the runtime. If not specified, then the internal default seccomp profile
-# will be used. This option supports live configuration reload.
+# will be used. A profile contained in an OCI artifact can be referenced as
+# "oci://REGISTRY/REPOSITORY:TAG" or "oci://REGISTRY/REPOSITORY@DIGEST", which
+# is pulled by using the signature policy of CRI-O. This option supports live
+# configuration reload.
 {{ $.Comment }}seccomp_profile = "{{ .SeccompProfile }}"
 
 `
@@ -880,6 +1000,12 @@ const templateStringCrioRuntimeSeccompUseDefaultWhenEmpty = `# Changes the meani
 
 `
 
+const templateStringCrioRuntimeSeccompRecordDir = `# Directory where the seccomp profiles recorded for containers of pods with the
+# "io.kubernetes.cri-o.seccompNotifierAction=record" annotation get written to.
+{{ $.Comment }}seccomp_record_dir = "{{ .SeccompRecordDir }}"
+
+`
+
 const templateStringCrioRuntimeApparmorProfile = `# Used to change the name of the default AppArmor profile of CRI-O. The default
 # profile name is "crio-default". This profile only takes effect if the user
 # does not specify a profile via the Kubernetes Pod's metadata annotation. If
@@ -889,6 +1015,15 @@ const templateStringCrioRuntimeApparmorProfile = `# Used to change the name of t
 
 `
 
+const templateStringCrioRuntimeApparmorProfilesDir = `# Directory of AppArmor profile files, which get loaded by CRI-O on startup and
+# configuration reload. Containers can use the profiles via their "localhost/"
+# profile name. Profiles removed from the directory get unloaded once they are
+# not used by containers anymore. This option supports live configuration
+# reload.
+{{ $.Comment }}apparmor_profiles_dir = "{{ .ApparmorProfilesDir }}"
+
+`
+
 const templateStringCrioRuntimeBlockIOConfigFile = `# Path to the blockio class configuration file for configuring
 # the cgroup blockio controller.
 {{ $.Comment }}blockio_config_file = "{{ .BlockIOConfigFile }}"
@@ -931,6 +1066,17 @@ const templateStringCrioRuntimeAddInheritableCapabilities = `# Add capabilities
 
 `
 
+const templateStringCrioRuntimeCapabilityPolicy = `# Defines what happens to containers using capabilities which are not allowed
+# by the allowed_capabilities of their runtime handler or by the
+# namespace_allowed_capabilities of their Kubernetes namespace. The default
+# capabilities count as used as well. Possible values are:
+# "reject": The creation of the container fails with an error.
+# "strip": The capabilities get removed from the container.
+# Privileged containers are always rejected if not all capabilities are allowed.
+{{ $.Comment }}capability_policy = "{{ .CapabilityPolicy }}"
+
+`
+
 const templateStringCrioRuntimeDefaultSysctls = `# List of default sysctls. If it is empty or commented out, only the sysctls
 # defined in the container json file by the user/kube will be added.
 {{ $.Comment }}default_sysctls = [
@@ -1005,7 +1151,20 @@ const templateStringCrioRuntimeLogSizeMax = `# Maximum sized allowed for the con
 
 `
 
+const templateStringCrioRuntimeExecSyncOutputSizeMax = `# Maximum size in bytes of the stdout and stderr output buffered for a single
+# ExecSync request. Output exceeding the limit is dropped and a truncation
+# marker is appended, while the command still runs to completion. Runtime
+# handlers of the "pod" runtime_type receive the complete output from conmon-rs
+# and truncate it afterwards. It must be >= 8192 to match/exceed conmon's read
+# buffer.
+{{ $.Comment }}exec_sync_output_size_max = {{ .ExecSyncOutputSizeMax }}
+
+`
+
 const templateStringCrioRuntimeLogToJournald = `# Whether container output should be logged to journald in addition to the kuberentes log file
+# It is not supported by runtime handlers of the "pod" runtime_type, which get
+# ignored if it is enabled, or fail the validation if they are the default
+# runtime handler.
 {{ $.Comment }}log_to_journald = {{ .LogToJournald }}
 
 `
@@ -1071,6 +1230,25 @@ const templateStringCrioRuntimeMinimumMappableGID = `# If set, CRI-O will reject
 
 `
 
+const templateStringCrioRuntimeUsernsAllocationUser = `# The user whose subordinate ID ranges in /etc/subuid and /etc/subgid are used
+# for allocating non-overlapping ranges of 65536 UIDs and GIDs to pods, which
+# request a user namespace via the CRI without providing ID mappings.
+{{ $.Comment }}userns_allocation_user = "{{ .UsernsAllocationUser }}"
+
+`
+
+const templateStringCrioRuntimeUsernsAllocationRanges = `# List of host ID ranges in the form HOSTID:SIZE, which are used for allocating
+# the UIDs and GIDs of pods instead of the ranges of the userns_allocation_user.
+{{ $.Comment }}userns_allocation_ranges = [
+{{ range $range := .UsernsAllocationRanges }}{{ $.Comment }}{{ printf "\t%q,\n" $range }}{{ end }}{{ $.Comment }}]
+
+`
+
+const templateStringCrioRuntimeUsernsAllocationFile = `# File where the user namespace ID ranges allocated to pods are persisted.
+{{ $.Comment }}userns_allocation_file = "{{ .UsernsAllocationFile }}"
+
+`
+
 const templateStringCrioRuntimeCtrStopTimeout = `# The minimal amount of time in seconds to wait before issuing a timeout
 # regarding the proper termination of the container. The lowest possible
 # value is 30s, whereas lower values are not considered by CRI-O.
@@ -1106,6 +1284,13 @@ const templateStringCrioRuntimeNamespacesDir = `# The directory where the state
 
 `
 
+const templateStringCrioRuntimeNamespacePoolSize = `# The number of pre-created network, IPC and UTS namespaces kept in the
+# namespaces_dir for fast pod startup. The pool is only used by pods without
+# host namespaces, user namespaces and sysctls. Set to 0 to disable the pool.
+{{ $.Comment }}namespace_pool_size = {{ .NamespacePoolSize }}
+
+`
+
 const templateStringCrioRuntimePinnsPath = `# pinns_path is the path to find the pinns binary, which is needed to manage namespace lifecycle
 {{ $.Comment }}pinns_path = "{{ .PinnsPath }}"
 
@@ -1156,6 +1341,16 @@ const templateStringCrioRuntimeRuntimesRuntimeHandler = `# The "crio.runtime.run
 # monitor_env = []
 # privileged_without_host_devices = false
 # allowed_annotations = []
+# default_capabilities = []
+# allowed_capabilities = []
+# default_sysctls = []
+# default_ulimits = []
+# seccomp_profile = ""
+# apparmor_profile = ""
+# default_mounts_file = ""
+# pids_limit = 0
+# ctr_stop_timeout = 0
+# stop_signal_chain = ""
 # Where:
 # - runtime-handler: Name used to identify the runtime.
 # - runtime_path (optional, string): Absolute path to the runtime executable in
@@ -1180,6 +1375,10 @@ const templateStringCrioRuntimeRuntimesRuntimeHandler = `# The "crio.runtime.run
 #   "io.kubernetes.cri-o.UnifiedCgroup.$CTR_NAME" for configuring the cgroup v2 unified block for a container.
 #   "io.containers.trace-syscall" for tracing syscalls via the OCI seccomp BPF hook.
 #   "io.kubernetes.cri.rdt-class" for setting the RDT class of a container
+#   "io.kubernetes.cri-o.StopSignalChain" for configuring the stop signal escalation chain of the containers in a pod.
+#   "io.kubernetes.cri-o.Networks" for attaching the pod to additional CNI networks.
+#   "io.kubernetes.cri-o.HostAliases" for adding entries to the hosts file of the pod.
+#   "io.kubernetes.cri-o.WritablePaths" for adding tmpfs mounts to containers with a read-only rootfs.
 # - monitor_path (optional, string): The path of the monitor binary. Replaces
 #   deprecated option "conmon".
 # - monitor_cgroup (optional, string): The cgroup the container monitor process will be put in.
@@ -1188,6 +1387,25 @@ const templateStringCrioRuntimeRuntimesRuntimeHandler = `# The "crio.runtime.run
 #   should be moved to the container's cgroup
 # - monitor_env (optional, array of strings): Environment variables to pass to the montior.
 #   Replaces deprecated option "conmon_env".
+# - default_capabilities, default_sysctls, default_ulimits, seccomp_profile,
+#   apparmor_profile, default_mounts_file (optional): Override the global
+#   options of the same name for containers using this runtime handler.
+# - allowed_capabilities (optional, array of strings): Capabilities which
+#   containers of this runtime handler are allowed to use, see
+#   capability_policy. "ALL" allows all capabilities.
+# - pids_limit (optional, int): Overrides the global pids_limit if non-zero.
+# - ctr_stop_timeout (optional, int): Grace period in seconds used when CRI-O
+#   stops the containers of the runtime handler on its own, for example when
+#   removing a pod. Defaults to 10 seconds if zero.
+# - stop_signal_chain (optional, string): Comma separated list of steps used to
+#   stop containers within their grace period, in the form "SIGNAL[:TIMEOUT]" or
+#   "exec=COMMAND[:TIMEOUT]", for example "SIGTERM:10s,SIGINT:10s". A step without
+#   timeout waits for the remaining grace period. Containers get killed with
+#   SIGKILL once the chain is exhausted. Can be overridden by the
+#   "io.kubernetes.cri-o.StopSignalChain" pod annotation if allowed.
+# - mcs_categories (optional, string): Range of MCS categories in the form
+#   "cMIN.cMAX", from which the SELinux levels of pods of this runtime handler
+#   are allocated, for example "c0.c511". Defaults to "c0.c1023".
 #
 # Using the seccomp notifier feature:
 #
@@ -1215,6 +1433,19 @@ const templateStringCrioRuntimeRuntimesRuntimeHandler = `# The "crio.runtime.run
 # Please be aware that CRI-O is not able to get notified if a syscall gets
 # blocked based on the seccomp defaultAction, which is a general runtime
 # limitation.
+#
+# If the value is "io.kubernetes.cri-o.seccompNotifierAction=audit", CRI-O
+# will allow the blocked syscalls to continue and only count them per container
+# and syscall. The counts are part of the container inspect information and the
+# "containers_seccomp_notifier_count_total" metric. The notifications of a
+# container are rate limited, which means that the counts of noisy workloads
+# get aggregated.
+#
+# If the value is "io.kubernetes.cri-o.seccompNotifierAction=record", CRI-O
+# will record the syscalls allowed by the seccomp profile which are used by the
+# container instead. Once the container exits, a profile allowing only the
+# recorded syscalls gets written to the seccomp_record_dir as
+# "NAMESPACE_POD_CONTAINER.json", which can be used as a localhost profile.
 
 {{ range $runtime_name, $runtime_handler := .Runtimes  }}
 {{ $.Comment }}[crio.runtime.runtimes.{{ $runtime_name }}]
@@ -1230,9 +1461,47 @@ const templateStringCrioRuntimeRuntimesRuntimeHandler = `# The "crio.runtime.run
 {{ if $runtime_handler.AllowedAnnotations }}{{ $.Comment }}allowed_annotations = [
 {{ range $opt := $runtime_handler.AllowedAnnotations }}{{ $.Comment }}{{ printf "\t%q,\n" $opt }}{{ end }}{{ $.Comment }}]{{ end }}
 {{ $.Comment }}privileged_without_host_devices = {{ $runtime_handler.PrivilegedWithoutHostDevices }}
+{{- if $runtime_handler.DefaultCapabilities }}
+{{ $.Comment }}default_capabilities = [
+{{ range $opt := $runtime_handler.DefaultCapabilities }}{{ $.Comment }}{{ printf "\t%q,\n" $opt }}{{ end }}{{ $.Comment }}]{{ end }}
+{{- if $runtime_handler.AllowedCapabilities }}
+{{ $.Comment }}allowed_capabilities = [
+{{ range $opt := $runtime_handler.AllowedCapabilities }}{{ $.Comment }}{{ printf "\t%q,\n" $opt }}{{ end }}{{ $.Comment }}]{{ end }}
+{{- if $runtime_handler.DefaultSysctls }}
+{{ $.Comment }}default_sysctls = [
+{{ range $opt := $runtime_handler.DefaultSysctls }}{{ $.Comment }}{{ printf "\t%q,\n" $opt }}{{ end }}{{ $.Comment }}]{{ end }}
+{{- if $runtime_handler.DefaultUlimits }}
+{{ $.Comment }}default_ulimits = [
+{{ range $opt := $runtime_handler.DefaultUlimits }}{{ $.Comment }}{{ printf "\t%q,\n" $opt }}{{ end }}{{ $.Comment }}]{{ end }}
+{{- if $runtime_handler.SeccompProfile }}
+{{ $.Comment }}seccomp_profile = "{{ $runtime_handler.SeccompProfile }}"{{ end }}
+{{- if $runtime_handler.ApparmorProfile }}
+{{ $.Comment }}apparmor_profile = "{{ $runtime_handler.ApparmorProfile }}"{{ end }}
+{{- if $runtime_handler.DefaultMountsFile }}
+{{ $.Comment }}default_mounts_file = "{{ $runtime_handler.DefaultMountsFile }}"{{ end }}
+{{- if $runtime_handler.PidsLimit }}
+{{ $.Comment }}pids_limit = {{ $runtime_handler.PidsLimit }}{{ end }}
+{{- if $runtime_handler.CtrStopTimeout }}
+{{ $.Comment }}ctr_stop_timeout = {{ $runtime_handler.CtrStopTimeout }}{{ end }}
+{{- if $runtime_handler.StopSignalChain }}
+{{ $.Comment }}stop_signal_chain = "{{ $runtime_handler.StopSignalChain }}"{{ end }}
+{{- if $runtime_handler.MCSCategories }}
+{{ $.Comment }}mcs_categories = "{{ $runtime_handler.MCSCategories }}"{{ end }}
 {{ end }}
 `
 
+const templateStringCrioRuntimeNamespaceAllowedCapabilities = `# The namespace_allowed_capabilities table lists the capabilities which
+# containers of a Kubernetes namespace are allowed to use, where "ALL" allows
+# all capabilities. Containers of namespaces not listed are not restricted.
+# Example:
+# [crio.runtime.namespace_allowed_capabilities]
+# kube-system = ["ALL"]
+# default = ["CHOWN", "NET_BIND_SERVICE", "SETGID", "SETUID"]
+{{ if .NamespaceAllowedCapabilities }}{{ $.Comment }}[crio.runtime.namespace_allowed_capabilities]
+{{ range $namespace, $allowed := .NamespaceAllowedCapabilities }}{{ $.Comment }}{{ printf "%q" $namespace }} = [{{ range $i, $capability := $allowed }}{{ if $i }}, {{ end }}{{ printf "%q" $capability }}{{ end }}]
+{{ end }}{{ end }}
+`
+
 const templateStringCrioRuntimeWorkloads = `# The workloads table defines ways to customize containers with different resources
 # that work based on annotations, rather than the CRI.
 # Note, the behavior of this table is EXPERIMENTAL and may change at any time.
@@ -1257,10 +1526,19 @@ const templateStringCrioRuntimeWorkloads = `# The workloads table defines ways t
 # annotation_prefix is used to customize the different resources.
 # To configure the cpu shares a container gets in the example above, the pod would have to have the following annotation:
 # "io.crio.workload-type/$container_name = {"cpushares": "value"}"
+# The writable_paths of a workload are the paths in the form "PATH[:SIZE]",
+# which get tmpfs mounts with an optional size limit like "64Mi" in containers
+# with a read-only rootfs, for example ["/var/cache/nginx:64Mi", "/var/log"].
+# The tmpfs mounts are noexec, nosuid and nodev, which means that binaries
+# written to them cannot be run. The paths must not be mounted already. They can be overridden by the
+# "io.kubernetes.cri-o.WritablePaths" annotation, which is a comma separated
+# list of paths, if allowed.
 {{ range $workload_type, $workload_config := .Workloads  }}
 {{ $.Comment }}[crio.runtime.workloads.{{ $workload_type }}]
 {{ $.Comment }}activation_annotation = "{{ $workload_config.ActivationAnnotation }}"
 {{ $.Comment }}annotation_prefix = "{{ $workload_config.AnnotationPrefix }}"
+{{ if $workload_config.WritablePaths }}{{ $.Comment }}writable_paths = [
+{{ range $opt := $workload_config.WritablePaths }}{{ $.Comment }}{{ printf "\t%q,\n" $opt }}{{ end }}{{ $.Comment }}]{{ end }}
 {{ if $workload_config.Resources }}{{ $.Comment }}[crio.runtime.workloads.{{ $workload_type }}.resources]
 {{ $.Comment }}cpuset = "{{ $workload_config.Resources.CPUSet }}"
 {{ $.Comment }}cpushares = {{ $workload_config.Resources.CPUShares }}{{ end }}
@@ -1361,6 +1639,45 @@ const templateStringCrioNetworkPluginDirs = `# Paths to directories where CNI pl
 
 `
 
+const templateStringCrioNetworkHostPortBackend = `# The backend used to map the hostports of pods, either "iptables" or
+# "nftables". The nftables backend manages its own "crio-hostports" tables and
+# migrates hostports which got added by the iptables backend on startup.
+{{ $.Comment }}hostport_backend = "{{ .HostPortBackend }}"
+
+`
+
+const templateStringCrioNetworkHostPortReconcileInterval = `# The interval in which the installed hostport rules get compared with the
+# port mappings of the running pods. Missing rules get re-added and rules of
+# pods which do not exist any more get removed. The reconciliation always runs
+# once on startup, a value of "0" disables the periodic reconciliation.
+{{ $.Comment }}hostport_reconcile_interval = "{{ .HostPortReconcileInterval }}"
+
+`
+
+const templateStringCrioNetworkEnableBandwidthFallback = `# Limit the bandwidth of pods with the "kubernetes.io/ingress-bandwidth" and
+# "kubernetes.io/egress-bandwidth" annotations by traffic control rules on
+# their host side veth, if no plugin of the default CNI network handles the
+# "bandwidth" capability.
+{{ $.Comment }}enable_bandwidth_fallback = {{ .EnableBandwidthFallback }}
+
+`
+
+const templateStringCrioNetworkDNSOptions = `# Resolver options, like "ndots:5", "timeout:2" or "attempts:3", which get
+# applied to the resolv.conf of all pods. Options of the DNS config of a pod
+# take precedence.
+{{ $.Comment }}dns_options = [
+{{ range $opt := .DNSOptions }}{{ $.Comment }}{{ printf "\t%q,\n" $opt }}{{ end }}{{ $.Comment }}]
+
+`
+
+const templateStringCrioNetworkDNSUpstreamResolvConf = `# Path to the resolv.conf containing the upstream servers of a local DNS stub
+# resolver, like the one of systemd-resolved. Stub resolvers on the loopback
+# interface are not reachable from pods outside of the host network, which is
+# why they get replaced by the upstream servers in the resolv.conf of such pods.
+{{ $.Comment }}dns_upstream_resolv_conf = "{{ .DNSUpstreamResolvConf }}"
+
+`
+
 const templateStringCrioMetrics = `# A necessary configuration for Prometheus based metrics retrieval
 [crio.metrics]
 
@@ -1435,6 +1752,45 @@ const templateStringCrioStatsStatsCollectionPeriod = `# The number of seconds be
 
 `
 
+const templateStringCrioAudit = `# Audit log of exec, attach, port forward, checkpoint and privileged container
+# creation requests. Every request is recorded as a JSON line.
+[crio.audit]
+
+`
+
+const templateStringCrioAuditAuditLogPath = `# Path of the audit log file. If empty, the audit log is not written to a file.
+{{ $.Comment }}audit_log_path = "{{ .AuditLogPath }}"
+
+`
+
+const templateStringCrioAuditAuditLogToJournald = `# Whether the audit log should be sent to journald.
+{{ $.Comment }}audit_log_to_journald = {{ .AuditLogToJournald }}
+
+`
+
+const templateStringCrioAuditAuditLogMaxSize = `# Size in megabytes at which the audit log file gets rotated.
+{{ $.Comment }}audit_log_max_size = {{ .AuditLogMaxSize }}
+
+`
+
+const templateStringCrioAuditAuditLogMaxBackups = `# Number of rotated audit log files to retain. If set to 0, all of them are retained.
+{{ $.Comment }}audit_log_max_backups = {{ .AuditLogMaxBackups }}
+
+`
+
+const templateStringCrioAuditAuditLogMaxAge = `# Number of days to retain rotated audit log files. If set to 0, rotated files
+# are not removed based on their age.
+{{ $.Comment }}audit_log_max_age = {{ .AuditLogMaxAge }}
+
+`
+
+const templateStringCrioAuditAuditRedactPatterns = `# List of regular expressions matching the parts of exec command arguments,
+# which get replaced by "[REDACTED]" in the audit log, for example "password=\\S+".
+{{ $.Comment }}audit_redact_patterns = [
+{{ range $pattern := .AuditRedactPatterns }}{{ $.Comment }}{{ printf "\t%q,\n" $pattern }}{{ end }}{{ $.Comment }}]
+
+`
+
 const templateStringCrioNRI = `# CRI-O NRI configuration.
 [crio.nri]
 
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/cri-o/cri-o/pkg/annotations"
 	"github.com/opencontainers/runtime-tools/generate"
 	"github.com/sirupsen/logrus"
 	"k8s.io/kubernetes/pkg/kubelet/cm/cpuset"
@@ -37,6 +38,10 @@ type WorkloadConfig struct {
 	// the annotation with the resource and value, the default value will apply.
 	// Default values do not need to be specified.
 	Resources *Resources `toml:"resources"`
+	// WritablePaths are the paths in the form `PATH[:SIZE]`, which get noexec
+	// tmpfs mounts in containers with a read-only rootfs. They can be
+	// overridden by the "io.kubernetes.cri-o.WritablePaths" annotation.
+	WritablePaths []string `toml:"writable_paths,omitempty"`
 }
 
 // Resources is a structure for overriding certain resources for the pod.
@@ -65,6 +70,9 @@ func (w *WorkloadConfig) Validate(workloadName string) error {
 	if err := w.ValidateWorkloadAllowedAnnotations(); err != nil {
 		return err
 	}
+	if _, err := parseWritablePaths(w.WritablePaths); err != nil {
+		return fmt.Errorf("invalid writable_paths for workload %q: %w", workloadName, err)
+	}
 	return w.Resources.ValidateDefaults()
 }
 
@@ -123,6 +131,27 @@ func (w Workloads) MutateSpecGivenAnnotations(ctrName string, specgen *generate.
 	return nil
 }
 
+// WritablePaths returns the paths which get tmpfs mounts in the container
+// with the provided name, if its rootfs is read-only. The
+// "io.kubernetes.cri-o.WritablePaths.$CTR_NAME" and
+// "io.kubernetes.cri-o.WritablePaths" sandbox annotations take precedence over
+// the writable paths of the workload of the sandbox.
+func (w Workloads) WritablePaths(ctrName string, sboxAnnotations map[string]string) (WritablePaths, error) {
+	for _, key := range []string{
+		annotations.WritablePathsAnnotation + "." + ctrName,
+		annotations.WritablePathsAnnotation,
+	} {
+		if value, ok := sboxAnnotations[key]; ok {
+			return ParseWritablePaths(value)
+		}
+	}
+	workload := w.workloadGivenActivationAnnotation(sboxAnnotations)
+	if workload == nil || len(workload.WritablePaths) == 0 {
+		return nil, nil
+	}
+	return parseWritablePaths(workload.WritablePaths)
+}
+
 func (w Workloads) workloadGivenActivationAnnotation(sboxAnnotations map[string]string) *WorkloadConfig {
 	for _, wc := range w {
 		for annotation := range sboxAnnotations {
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// WritablePath is a path of a container with a read-only rootfs, which gets
+// a tmpfs mount.
+type WritablePath struct {
+	// Path is the absolute path of the tmpfs mount inside the container.
+	Path string
+
+	// Size is the size limit of the tmpfs in bytes. Zero means the default
+	// size of tmpfs, which is half of the memory.
+	Size int64
+}
+
+func (p *WritablePath) String() string {
+	if p.Size == 0 {
+		return p.Path
+	}
+	return fmt.Sprintf("%s:%d", p.Path, p.Size)
+}
+
+// WritablePaths are the paths of a container with a read-only rootfs, which
+// get tmpfs mounts.
+type WritablePaths []WritablePath
+
+func (w WritablePaths) String() string {
+	paths := make([]string, 0, len(w))
+	for i := range w {
+		paths = append(paths, w[i].String())
+	}
+	return strings.Join(paths, ",")
+}
+
+// ParseWritablePaths parses a comma separated list of paths in the form
+// `PATH[:SIZE]`, where SIZE is a quantity like `64Mi`, for example
+// `/var/cache/nginx:64Mi,/var/log`. An empty string results in no paths.
+func ParseWritablePaths(s string) (WritablePaths, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	return parseWritablePaths(strings.Split(s, ","))
+}
+
+func parseWritablePaths(rawPaths []string) (WritablePaths, error) {
+	paths := make(WritablePaths, 0, len(rawPaths))
+	seen := make(map[string]bool, len(rawPaths))
+	for _, rawPath := range rawPaths {
+		rawPath = strings.TrimSpace(rawPath)
+		path := WritablePath{Path: rawPath}
+		if p, size, ok := strings.Cut(rawPath, ":"); ok {
+			quantity, err := resource.ParseQuantity(size)
+			if err != nil {
+				return nil, fmt.Errorf("writable path %q: invalid size: %w", rawPath, err)
+			}
+			if quantity.Value() <= 0 {
+				return nil, fmt.Errorf("writable path %q: size has to be positive", rawPath)
+			}
+			path.Path, path.Size = p, quantity.Value()
+		}
+		if !filepath.IsAbs(path.Path) || filepath.Clean(path.Path) != path.Path || path.Path == "/" {
+			return nil, fmt.Errorf("writable path %q: path has to be a clean absolute path other than /", rawPath)
+		}
+		if seen[path.Path] {
+			return nil, fmt.Errorf("writable path %q: path specified more than once", rawPath)
+		}
+		seen[path.Path] = true
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
@@ -0,0 +1,123 @@
+package config_test
+
+import (
+	"github.com/cri-o/cri-o/pkg/annotations"
+	"github.com/cri-o/cri-o/pkg/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// The actual test suite
+var _ = t.Describe("WritablePaths", func() {
+	t.Describe("ParseWritablePaths", func() {
+		It("should succeed without paths", func() {
+			// Given
+			// When
+			paths, err := config.ParseWritablePaths("")
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(paths).To(BeEmpty())
+		})
+
+		It("should succeed to parse paths with and without size", func() {
+			// Given
+			const s = "/var/cache/nginx:64Mi, /var/log"
+
+			// When
+			paths, err := config.ParseWritablePaths(s)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(paths).To(Equal(config.WritablePaths{
+				{Path: "/var/cache/nginx", Size: 64 * 1024 * 1024},
+				{Path: "/var/log"},
+			}))
+			Expect(paths.String()).To(Equal("/var/cache/nginx:67108864,/var/log"))
+		})
+
+		It("should fail with invalid paths", func() {
+			for _, s := range []string{
+				"relative",
+				"/",
+				"/var/../etc",
+				"/var/log:",
+				"/var/log:invalid",
+				"/var/log:0",
+				"/var/log,/var/log:1Mi",
+			} {
+				// Given
+				// When
+				_, err := config.ParseWritablePaths(s)
+
+				// Then
+				Expect(err).NotTo(BeNil(), s)
+			}
+		})
+	})
+
+	t.Describe("Workloads", func() {
+		const activationAnnotation = "io.crio/workload"
+
+		var sut config.Workloads
+
+		BeforeEach(func() {
+			sut = config.Workloads{
+				"workload": &config.WorkloadConfig{
+					ActivationAnnotation: activationAnnotation,
+					WritablePaths:        []string{"/var/cache:1Mi"},
+				},
+			}
+		})
+
+		It("should fail validation with invalid writable paths", func() {
+			// Given
+			sut["workload"].WritablePaths = []string{"relative"}
+
+			// When
+			err := sut.Validate()
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should return no paths without workload or annotation", func() {
+			// Given
+			// When
+			paths, err := sut.WritablePaths("ctr", map[string]string{})
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(paths).To(BeEmpty())
+		})
+
+		It("should return the paths of the workload", func() {
+			// Given
+			sboxAnnotations := map[string]string{activationAnnotation: ""}
+
+			// When
+			paths, err := sut.WritablePaths("ctr", sboxAnnotations)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(paths).To(Equal(config.WritablePaths{{Path: "/var/cache", Size: 1024 * 1024}}))
+		})
+
+		It("should prefer the container annotation over the pod annotation", func() {
+			// Given
+			sboxAnnotations := map[string]string{
+				activationAnnotation:                               "",
+				annotations.WritablePathsAnnotation:                "/pod",
+				annotations.WritablePathsAnnotation + ".ctr":       "/ctr",
+				annotations.WritablePathsAnnotation + ".other-ctr": "/other",
+			}
+
+			// When
+			paths, err := sut.WritablePaths("ctr", sboxAnnotations)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(paths).To(Equal(config.WritablePaths{{Path: "/ctr"}}))
+		})
+	})
+})
@@ -0,0 +1,190 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// RuntimeFeatures is the subset of the output of the OCI runtime `features`
+// subcommand which is relevant for CRI-O. A nil field means that the runtime
+// did not report it, which is treated as supported.
+type RuntimeFeatures struct {
+	// OCIVersionMin is the minimum OCI runtime spec version recognized by
+	// the runtime.
+	OCIVersionMin string `json:"ociVersionMin,omitempty"`
+
+	// OCIVersionMax is the maximum OCI runtime spec version recognized by
+	// the runtime.
+	OCIVersionMax string `json:"ociVersionMax,omitempty"`
+
+	// MountOptions is the list of the recognized mount options.
+	MountOptions []string `json:"mountOptions,omitempty"`
+
+	// Linux holds the Linux specific features.
+	Linux *LinuxRuntimeFeatures `json:"linux,omitempty"`
+}
+
+// LinuxRuntimeFeatures are the Linux specific runtime features.
+type LinuxRuntimeFeatures struct {
+	// Namespaces is the list of the recognized namespaces.
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Capabilities is the list of the recognized capabilities.
+	Capabilities []string `json:"capabilities,omitempty"`
+
+	// Cgroup holds the cgroup features.
+	Cgroup *CgroupRuntimeFeatures `json:"cgroup,omitempty"`
+
+	// Seccomp holds the seccomp features.
+	Seccomp *SeccompRuntimeFeatures `json:"seccomp,omitempty"`
+
+	// MountExtensions holds the features of the mount extensions.
+	MountExtensions *MountExtensionsRuntimeFeatures `json:"mountExtensions,omitempty"`
+}
+
+// CgroupRuntimeFeatures are the cgroup related runtime features.
+type CgroupRuntimeFeatures struct {
+	// V1 indicates whether cgroup v1 is supported.
+	V1 *bool `json:"v1,omitempty"`
+
+	// V2 indicates whether cgroup v2 is supported.
+	V2 *bool `json:"v2,omitempty"`
+
+	// Systemd indicates whether the systemd cgroup driver is supported.
+	Systemd *bool `json:"systemd,omitempty"`
+}
+
+// SeccompRuntimeFeatures are the seccomp related runtime features.
+type SeccompRuntimeFeatures struct {
+	// Enabled indicates whether seccomp support is compiled in.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Actions is the list of the recognized seccomp actions.
+	Actions []string `json:"actions,omitempty"`
+
+	// Operators is the list of the recognized seccomp operators.
+	Operators []string `json:"operators,omitempty"`
+
+	// Archs is the list of the recognized seccomp architectures.
+	Archs []string `json:"archs,omitempty"`
+}
+
+// MountExtensionsRuntimeFeatures are the features of the mount extensions.
+type MountExtensionsRuntimeFeatures struct {
+	// IDMap holds the idmapped mounts features.
+	IDMap *IDMapRuntimeFeatures `json:"idmap,omitempty"`
+}
+
+// IDMapRuntimeFeatures are the idmapped mounts features.
+type IDMapRuntimeFeatures struct {
+	// Enabled indicates whether idmapped mounts are supported.
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// runtimeMountOptions are the mount options of the OCI runtime spec, which
+// are interpreted by the runtime as mount flags instead of being passed to the
+// filesystem as mount data.
+var runtimeMountOptions = stringSet([]string{
+	"async", "atime", "bind", "defaults", "dev", "diratime", "dirsync",
+	"exec", "idmap", "iversion", "lazytime", "loud", "mand", "noatime",
+	"nodev", "nodiratime", "noexec", "noiversion", "nolazytime", "nomand",
+	"norelatime", "nostrictatime", "nosuid", "nosymfollow", "private",
+	"ratime", "rbind", "rdev", "rdiratime", "relatime", "remount", "rexec",
+	"ridmap", "rnoatime", "rnodev", "rnodiratime", "rnoexec", "rnorelatime",
+	"rnostrictatime", "rnosuid", "rnosymfollow", "ro", "rprivate",
+	"rrelatime", "rro", "rrw", "rshared", "rslave", "rstrictatime", "rsuid",
+	"rsymfollow", "runbindable", "rw", "shared", "silent", "slave",
+	"strictatime", "suid", "symfollow", "sync", "tmpcopyup", "unbindable",
+})
+
+// ValidateSpec checks that the provided spec only relies on features supported
+// by the runtime. It does nothing if the features are unknown.
+func (f *RuntimeFeatures) ValidateSpec(spec *rspec.Spec) error {
+	if f == nil || spec == nil {
+		return nil
+	}
+	var errs []error
+
+	if len(f.MountOptions) > 0 {
+		supported := stringSet(f.MountOptions)
+		for i := range spec.Mounts {
+			for _, opt := range spec.Mounts[i].Options {
+				// Filesystem specific options like "mode=0755" or
+				// "newinstance" are passed through to the kernel as mount
+				// data and not listed by the runtime.
+				if _, ok := runtimeMountOptions[opt]; !ok {
+					continue
+				}
+				if _, ok := supported[opt]; !ok {
+					errs = append(errs, fmt.Errorf("mount option %q of %s is not supported by the runtime", opt, spec.Mounts[i].Destination))
+				}
+			}
+		}
+	}
+
+	if spec.Linux == nil || f.Linux == nil {
+		return errors.Join(errs...)
+	}
+
+	if !f.SupportsIDMappedMounts() {
+		for i := range spec.Mounts {
+			if len(spec.Mounts[i].UIDMappings) > 0 || len(spec.Mounts[i].GIDMappings) > 0 {
+				errs = append(errs, fmt.Errorf("idmapped mount %s is not supported by the runtime", spec.Mounts[i].Destination))
+			}
+		}
+	}
+
+	if spec.Linux.Seccomp != nil && f.Linux.Seccomp != nil {
+		if f.Linux.Seccomp.Enabled != nil && !*f.Linux.Seccomp.Enabled {
+			errs = append(errs, errors.New("seccomp is not supported by the runtime"))
+		} else if len(f.Linux.Seccomp.Actions) > 0 {
+			supported := stringSet(f.Linux.Seccomp.Actions)
+			actions := []rspec.LinuxSeccompAction{spec.Linux.Seccomp.DefaultAction}
+			for i := range spec.Linux.Seccomp.Syscalls {
+				actions = append(actions, spec.Linux.Seccomp.Syscalls[i].Action)
+			}
+			reported := make(map[rspec.LinuxSeccompAction]struct{})
+			for _, action := range actions {
+				if _, ok := reported[action]; ok {
+					continue
+				}
+				if _, ok := supported[string(action)]; !ok {
+					reported[action] = struct{}{}
+					errs = append(errs, fmt.Errorf("seccomp action %q is not supported by the runtime", action))
+				}
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// SupportsIDMappedMounts returns whether the runtime supports idmapped mounts.
+// Unknown features are treated as unsupported, because runtimes silently
+// ignore unknown mount fields.
+func (f *RuntimeFeatures) SupportsIDMappedMounts() bool {
+	return f != nil && f.Linux != nil &&
+		f.Linux.MountExtensions != nil &&
+		f.Linux.MountExtensions.IDMap != nil &&
+		f.Linux.MountExtensions.IDMap.Enabled != nil &&
+		*f.Linux.MountExtensions.IDMap.Enabled
+}
+
+// SupportsCgroupV2 returns whether the runtime supports cgroup v2. Runtimes
+// which do not report their cgroup features are treated as supporting it.
+func (f *RuntimeFeatures) SupportsCgroupV2() bool {
+	if f == nil || f.Linux == nil || f.Linux.Cgroup == nil || f.Linux.Cgroup.V2 == nil {
+		return true
+	}
+	return *f.Linux.Cgroup.V2
+}
+
+func stringSet(s []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(s))
+	for _, e := range s {
+		set[e] = struct{}{}
+	}
+	return set
+}
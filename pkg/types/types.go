@@ -18,6 +18,10 @@ type ContainerInfo struct {
 	Root            string            `json:"root"`
 	Sandbox         string            `json:"sandbox"`
 	IPs             []string          `json:"ip_addresses"`
+	// SeccompAuditedSyscalls are the number of calls per syscall which
+	// would have been blocked by the seccomp profile, if the container
+	// runs with the seccomp notifier audit action.
+	SeccompAuditedSyscalls map[string]uint64 `json:"seccomp_audited_syscalls,omitempty"`
 }
 
 // IDMappings specifies the ID mappings used for containers.
@@ -32,4 +36,62 @@ type CrioInfo struct {
 	StorageRoot       string     `json:"storage_root"`
 	CgroupDriver      string     `json:"cgroup_driver"`
 	DefaultIDMappings IDMappings `json:"default_id_mappings"`
+	// RuntimeFeatures are the discovered features per runtime handler.
+	RuntimeFeatures map[string]*RuntimeFeatures `json:"runtime_features,omitempty"`
+}
+
+// HostPortsInfo stores information about the hostports of the pods
+type HostPortsInfo struct {
+	// Reservations are the host ports currently reserved by pods.
+	Reservations []HostPortReservation `json:"reservations"`
+	// Reconciliation is the result of the last hostport reconciliation, nil
+	// if no reconciliation ran so far.
+	Reconciliation *HostPortReconciliation `json:"reconciliation,omitempty"`
+}
+
+// HostPortReservation stores information about a host port reserved by a pod
+type HostPortReservation struct {
+	HostPort int32  `json:"host_port"`
+	Protocol string `json:"protocol"`
+	HostIP   string `json:"host_ip,omitempty"`
+	PodID    string `json:"pod_id"`
+	PodName  string `json:"pod_name"`
+}
+
+// HostPortReconciliation stores the result of a hostport reconciliation
+type HostPortReconciliation struct {
+	// Time is the UNIX time in nanoseconds when the reconciliation finished.
+	Time int64 `json:"time"`
+	// Readded are the IDs of the sandboxes whose missing hostport rules got
+	// re-added.
+	Readded []string `json:"readded,omitempty"`
+	// Removed are the names of the stale hostport chains which got removed.
+	Removed []string `json:"removed,omitempty"`
+	// Error is the error of the reconciliation, if any.
+	Error string `json:"error,omitempty"`
+}
+
+const (
+	// EventTypeNormal is the type of events about expected state changes.
+	EventTypeNormal = "Normal"
+	// EventTypeWarning is the type of events about problems and adjustments
+	// which require attention.
+	EventTypeWarning = "Warning"
+)
+
+// Event is a node, pod or container event which cannot be represented by the
+// CRI container events, like a change of the network readiness.
+type Event struct {
+	// Time is the UNIX time in nanoseconds when the event occurred.
+	Time int64 `json:"time"`
+	// Type is either EventTypeNormal or EventTypeWarning.
+	Type string `json:"type"`
+	// Reason is a machine readable reason of the event.
+	Reason string `json:"reason"`
+	// Message is a human readable description of the event.
+	Message string `json:"message"`
+	// PodSandboxID is the ID of the affected pod sandbox, if any.
+	PodSandboxID string `json:"pod_sandbox_id,omitempty"`
+	// ContainerID is the ID of the affected container, if any.
+	ContainerID string `json:"container_id,omitempty"`
 }
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+
+	"github.com/cri-o/cri-o/internal/audit"
+	libconfig "github.com/cri-o/cri-o/pkg/config"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+	kubeletTypes "k8s.io/kubernetes/pkg/kubelet/types"
+)
+
+func newAuditLogger(config *libconfig.Config) (*audit.Logger, error) {
+	return audit.New(&audit.Options{
+		Path:           config.AuditLogPath,
+		Journald:       config.AuditLogToJournald,
+		MaxSize:        config.AuditLogMaxSize,
+		MaxBackups:     config.AuditLogMaxBackups,
+		MaxAge:         config.AuditLogMaxAge,
+		RedactPatterns: config.AuditRedactPatterns,
+	})
+}
+
+// startContainerAudit starts the audit event of an operation on a container.
+// The identity of the container and its pod is added if the container exists.
+// It returns nil if the audit log is disabled.
+func (s *Server) startContainerAudit(ctx context.Context, operation audit.Operation, containerID string) *audit.Event {
+	event := s.auditLogger.Start(ctx, operation)
+	if event == nil {
+		return nil
+	}
+	event.ContainerID = containerID
+
+	c, err := s.GetContainerFromShortID(ctx, containerID)
+	if err != nil {
+		return event
+	}
+	labels := c.Labels()
+	event.ContainerID = c.ID()
+	event.ContainerName = labels[kubeletTypes.KubernetesContainerNameLabel]
+	event.PodSandboxID = c.Sandbox()
+	event.PodName = labels[kubeletTypes.KubernetesPodNameLabel]
+	event.PodNamespace = labels[kubeletTypes.KubernetesPodNamespaceLabel]
+	return event
+}
+
+// startSandboxAudit starts the audit event of an operation on a pod. The
+// identity of the pod is added if it exists. It returns nil if the audit log
+// is disabled.
+func (s *Server) startSandboxAudit(ctx context.Context, operation audit.Operation, podSandboxID string) *audit.Event {
+	event := s.auditLogger.Start(ctx, operation)
+	if event == nil {
+		return nil
+	}
+	event.PodSandboxID = podSandboxID
+
+	sandboxID, err := s.PodIDIndex().Get(podSandboxID)
+	if err != nil {
+		return event
+	}
+	sb := s.GetSandbox(sandboxID)
+	if sb == nil {
+		return event
+	}
+	event.PodSandboxID = sb.ID()
+	if metadata := sb.Metadata(); metadata != nil {
+		event.PodName = metadata.Name
+		event.PodNamespace = metadata.Namespace
+	}
+	return event
+}
+
+// startCreateContainerAudit starts the audit event of the creation of a
+// privileged container. It returns nil if the container is not privileged or
+// the audit log is disabled.
+func (s *Server) startCreateContainerAudit(ctx context.Context, req *types.CreateContainerRequest) *audit.Event {
+	if !req.GetConfig().GetLinux().GetSecurityContext().GetPrivileged() {
+		return nil
+	}
+	event := s.auditLogger.Start(ctx, audit.OperationCreatePrivileged)
+	if event == nil {
+		return nil
+	}
+	event.ContainerName = req.GetConfig().GetMetadata().GetName()
+	event.Image = req.GetConfig().GetImage().GetImage()
+	event.PodSandboxID = req.PodSandboxId
+	event.PodName = req.GetSandboxConfig().GetMetadata().GetName()
+	event.PodNamespace = req.GetSandboxConfig().GetMetadata().GetNamespace()
+	return event
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/cri-o/cri-o/internal/audit"
 	"github.com/cri-o/cri-o/internal/log"
 	"github.com/cri-o/cri-o/internal/oci"
 	"golang.org/x/net/context"
@@ -14,7 +15,14 @@ import (
 )
 
 // Attach prepares a streaming endpoint to attach to a running container.
-func (s *Server) Attach(ctx context.Context, req *types.AttachRequest) (*types.AttachResponse, error) {
+func (s *Server) Attach(ctx context.Context, req *types.AttachRequest) (_ *types.AttachResponse, retErr error) {
+	event := s.startContainerAudit(ctx, audit.OperationAttach, req.ContainerId)
+	if event != nil {
+		event.TTY = req.Tty
+		event.Stdin = req.Stdin
+	}
+	defer func() { s.auditLogger.Finish(ctx, event, retErr) }()
+
 	resp, err := s.getAttach(req)
 	if err != nil {
 		return nil, fmt.Errorf("unable to prepare attach endpoint")
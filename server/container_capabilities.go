@@ -0,0 +1,66 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cri-o/cri-o/internal/config/capabilities"
+	"github.com/cri-o/cri-o/pkg/config"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// enforceCapabilityPolicy checks the capabilities of the container spec
+// against the lists of allowed capabilities, which all have to allow a
+// capability. Depending on the policy, capabilities which are not allowed
+// either result in an error or get removed from the spec, in which case they
+// are returned. Privileged containers are always rejected if any capability is
+// not allowed.
+func enforceCapabilityPolicy(spec *specs.Spec, allowed []capabilities.Capabilities, policy string, privileged bool) ([]string, error) {
+	if len(allowed) == 0 || spec.Process == nil || spec.Process.Capabilities == nil {
+		return nil, nil
+	}
+
+	caps := spec.Process.Capabilities
+	sets := []*[]string{&caps.Bounding, &caps.Effective, &caps.Permitted, &caps.Inheritable, &caps.Ambient}
+	denied := make(map[string]struct{})
+	for _, set := range sets {
+		for _, capability := range *set {
+			if !capabilityAllowed(allowed, capability) {
+				denied[capability] = struct{}{}
+			}
+		}
+	}
+	if len(denied) == 0 {
+		return nil, nil
+	}
+
+	deniedList := make([]string, 0, len(denied))
+	for capability := range denied {
+		deniedList = append(deniedList, capability)
+	}
+	sort.Strings(deniedList)
+	if privileged || policy != config.CapabilityPolicyStrip {
+		return nil, fmt.Errorf("capabilities %s are not allowed by the capability policy", strings.Join(deniedList, ", "))
+	}
+
+	for _, set := range sets {
+		kept := []string{}
+		for _, capability := range *set {
+			if _, ok := denied[capability]; !ok {
+				kept = append(kept, capability)
+			}
+		}
+		*set = kept
+	}
+	return deniedList, nil
+}
+
+func capabilityAllowed(allowed []capabilities.Capabilities, capability string) bool {
+	for _, caps := range allowed {
+		if !caps.Allows(capability) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cri-o/cri-o/internal/config/capabilities"
+	"github.com/cri-o/cri-o/pkg/config"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+func TestEnforceCapabilityPolicy(t *testing.T) {
+	newSpec := func() *specs.Spec {
+		caps := []string{"CAP_CHOWN", "CAP_NET_ADMIN", "CAP_SYS_ADMIN"}
+		return &specs.Spec{Process: &specs.Process{Capabilities: &specs.LinuxCapabilities{
+			Bounding:  append([]string{}, caps...),
+			Effective: append([]string{}, caps...),
+			Permitted: append([]string{}, caps...),
+		}}}
+	}
+
+	for _, tc := range []struct {
+		name       string
+		allowed    []capabilities.Capabilities
+		policy     string
+		privileged bool
+		wantErr    bool
+		want       []string
+		wantCaps   []string
+	}{
+		{
+			name:     "no policy",
+			policy:   config.CapabilityPolicyReject,
+			wantCaps: []string{"CAP_CHOWN", "CAP_NET_ADMIN", "CAP_SYS_ADMIN"},
+		},
+		{
+			name:     "all allowed",
+			allowed:  []capabilities.Capabilities{{"ALL"}, {"chown", "NET_ADMIN", "CAP_SYS_ADMIN"}},
+			policy:   config.CapabilityPolicyReject,
+			wantCaps: []string{"CAP_CHOWN", "CAP_NET_ADMIN", "CAP_SYS_ADMIN"},
+		},
+		{
+			name:    "reject",
+			allowed: []capabilities.Capabilities{{"CHOWN", "NET_ADMIN"}},
+			policy:  config.CapabilityPolicyReject,
+			wantErr: true,
+		},
+		{
+			name:     "strip",
+			allowed:  []capabilities.Capabilities{{"ALL"}, {"CHOWN", "SYS_ADMIN"}, {"CHOWN", "NET_ADMIN"}},
+			policy:   config.CapabilityPolicyStrip,
+			want:     []string{"CAP_NET_ADMIN", "CAP_SYS_ADMIN"},
+			wantCaps: []string{"CAP_CHOWN"},
+		},
+		{
+			name:       "privileged",
+			allowed:    []capabilities.Capabilities{{"CHOWN"}},
+			policy:     config.CapabilityPolicyStrip,
+			privileged: true,
+			wantErr:    true,
+		},
+	} {
+		spec := newSpec()
+		got, err := enforceCapabilityPolicy(spec, tc.allowed, tc.policy, tc.privileged)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: got error %v, want error %v", tc.name, err, tc.wantErr)
+			continue
+		}
+		if tc.wantErr {
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got stripped %v, want %v", tc.name, got, tc.want)
+		}
+		caps := spec.Process.Capabilities
+		for _, set := range [][]string{caps.Bounding, caps.Effective, caps.Permitted} {
+			if !reflect.DeepEqual(set, tc.wantCaps) {
+				t.Errorf("%s: got capabilities %v, want %v", tc.name, set, tc.wantCaps)
+			}
+		}
+	}
+}
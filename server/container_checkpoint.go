@@ -5,6 +5,7 @@ import (
 
 	metadata "github.com/checkpoint-restore/checkpointctl/lib"
 	"github.com/containers/podman/v4/libpod"
+	"github.com/cri-o/cri-o/internal/audit"
 	"github.com/cri-o/cri-o/internal/log"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc/codes"
@@ -13,7 +14,13 @@ import (
 )
 
 // CheckpointContainer checkpoints a container
-func (s *Server) CheckpointContainer(ctx context.Context, req *types.CheckpointContainerRequest) (*types.CheckpointContainerResponse, error) {
+func (s *Server) CheckpointContainer(ctx context.Context, req *types.CheckpointContainerRequest) (_ *types.CheckpointContainerResponse, retErr error) {
+	event := s.startContainerAudit(ctx, audit.OperationCheckpoint, req.ContainerId)
+	if event != nil {
+		event.CheckpointLocation = req.Location
+	}
+	defer func() { s.auditLogger.Finish(ctx, event, retErr) }()
+
 	if !s.config.RuntimeConfig.CheckpointRestore() {
 		return nil, fmt.Errorf("checkpoint/restore support not available")
 	}
@@ -299,6 +299,14 @@ func generateUserString(username, imageUser string, uid *types.Int64Value) strin
 func (s *Server) CreateContainer(ctx context.Context, req *types.CreateContainerRequest) (res *types.CreateContainerResponse, retErr error) {
 	log.Infof(ctx, "Creating container: %s", translateLabelsToDescription(req.GetConfig().GetLabels()))
 
+	event := s.startCreateContainerAudit(ctx, req)
+	defer func() {
+		if event != nil && res != nil {
+			event.ContainerID = res.ContainerId
+		}
+		s.auditLogger.Finish(ctx, event, retErr)
+	}()
+
 	// Check if image is a file. If it is a file it might be a checkpoint archive.
 	checkpointImage, err := func() (bool, error) {
 		if !s.config.CheckpointRestore() {
@@ -25,6 +25,7 @@ import (
 	oci "github.com/cri-o/cri-o/internal/oci"
 	"github.com/cri-o/cri-o/internal/storage"
 	crioann "github.com/cri-o/cri-o/pkg/annotations"
+	libconfig "github.com/cri-o/cri-o/pkg/config"
 	securejoin "github.com/cyphar/filepath-securejoin"
 	rspec "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/opencontainers/runtime-tools/generate"
@@ -40,6 +41,16 @@ const (
 	cgroupSysFsSystemdPath = "/sys/fs/cgroup/systemd"
 )
 
+const (
+	// idMappingStrategyIDMappedMounts maps the IDs of the volumes of a
+	// container in a user namespace via idmapped mounts.
+	idMappingStrategyIDMappedMounts = "idmapped-mounts"
+
+	// idMappingStrategyChown changes the ownership of the volumes of a
+	// container in a user namespace.
+	idMappingStrategyChown = "chown"
+)
+
 // createContainerPlatform performs platform dependent intermediate steps before calling the container's oci.Runtime().CreateContainer()
 func (s *Server) createContainerPlatform(ctx context.Context, container *oci.Container, cgroupParent string, idMappings *idtools.IDMappings) error {
 	ctx, span := log.StartSpan(ctx)
@@ -174,7 +185,7 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 	specgen.HostSpecific = true
 	specgen.ClearProcessRlimits()
 
-	for _, u := range s.config.Ulimits() {
+	for _, u := range s.config.UlimitsForHandler(sb.RuntimeHandler()) {
 		specgen.AddProcessRlimits(u.Name, u.Hard, u.Soft)
 	}
 
@@ -366,8 +377,8 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 	}
 
 	// set this container's apparmor profile if it is set by sandbox
-	if s.Config().AppArmor().IsEnabled() && !ctr.Privileged() {
-		profile, err := s.Config().AppArmor().Apply(
+	if apparmorConfig := s.config.AppArmorForHandler(sb.RuntimeHandler()); apparmorConfig.IsEnabled() && !ctr.Privileged() {
+		profile, err := apparmorConfig.Apply(
 			securityContext.ApparmorProfile,
 		)
 		if err != nil {
@@ -460,10 +471,23 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 			specgen.SetupPrivileged(true)
 		} else {
 			capabilities := securityContext.Capabilities
-			if err := ctr.SpecSetupCapabilities(capabilities, s.config.DefaultCapabilities, s.config.AddInheritableCapabilities); err != nil {
+			if err := ctr.SpecSetupCapabilities(capabilities, s.config.DefaultCapabilitiesForHandler(sb.RuntimeHandler()), s.config.AddInheritableCapabilities); err != nil {
 				return nil, err
 			}
 		}
+		stripped, err := enforceCapabilityPolicy(
+			specgen.Config,
+			s.config.AllowedCapabilitiesFor(sb.RuntimeHandler(), sb.Namespace()),
+			s.config.CapabilityPolicy,
+			ctr.Privileged(),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("container %s(%s): %w", containerName, containerID, err)
+		}
+		if len(stripped) > 0 {
+			log.Warnf(ctx, "Removed capabilities %s of container %s(%s) which are not allowed by the capability policy",
+				strings.Join(stripped, ", "), containerName, containerID)
+		}
 		specgen.SetProcessNoNewPrivileges(securityContext.NoNewPrivs)
 
 		if !ctr.Privileged() {
@@ -600,14 +624,26 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 		})
 	}
 
-	if !isInCRIMounts("/etc/hosts", containerConfig.Mounts) && hostNet {
-		// Only bind mount for host netns and when CRI does not give us any hosts file
-		ctr.SpecAddMount(rspec.Mount{
-			Destination: "/etc/hosts",
-			Type:        "bind",
-			Source:      "/etc/hosts",
-			Options:     append(options, "bind"),
-		})
+	if !isInCRIMounts("/etc/hosts", containerConfig.Mounts) {
+		// Only bind mount when CRI does not give us any hosts file
+		if hostNet {
+			ctr.SpecAddMount(rspec.Mount{
+				Destination: "/etc/hosts",
+				Type:        "bind",
+				Source:      "/etc/hosts",
+				Options:     append(options, "bind"),
+			})
+		} else if sb.HostsPath() != "" {
+			if err := securityLabel(sb.HostsPath(), mountLabel, false, false); err != nil {
+				return nil, err
+			}
+			ctr.SpecAddMount(rspec.Mount{
+				Destination: "/etc/hosts",
+				Type:        "bind",
+				Source:      sb.HostsPath(),
+				Options:     append(options, "bind"),
+			})
+		}
 	}
 
 	if ctr.Privileged() {
@@ -620,7 +656,7 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 
 	created := time.Now()
 	if !ctr.Privileged() {
-		notifier, err := s.config.Seccomp().Setup(
+		notifier, err := s.config.SeccompForHandler(sb.RuntimeHandler()).Setup(
 			ctx,
 			s.seccompNotifierChan,
 			containerID,
@@ -657,6 +693,18 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 		return nil, err
 	}
 
+	// The pod annotation is only present if allowed for the runtime handler.
+	stopSignalChain := s.config.StopSignalChainForHandler(sb.RuntimeHandler())
+	if v, ok := sb.Annotations()[crioann.StopSignalChainAnnotation]; ok {
+		stopSignalChain = v
+	}
+	if stopSignalChain != "" {
+		if _, err := libconfig.ParseStopSignalChain(stopSignalChain); err != nil {
+			return nil, fmt.Errorf("invalid stop signal chain %q: %w", stopSignalChain, err)
+		}
+		specgen.AddAnnotation(crioann.StopSignalChainAnnotation, stopSignalChain)
+	}
+
 	// First add any configured environment variables from crio config.
 	// They will get overridden if specified in the image or container config.
 	specgen.AddMultipleProcessEnv(s.Config().DefaultEnv)
@@ -701,7 +749,7 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 	secretMounts := subscriptions.MountsWithUIDGID(
 		mountLabel,
 		containerInfo.RunDir,
-		s.config.DefaultMountsFile,
+		s.config.DefaultMountsFileForHandler(sb.RuntimeHandler()),
 		mountPoint,
 		0,
 		0,
@@ -716,6 +764,21 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 
 	sort.Sort(orderedMounts(mounts))
 
+	// The rootfs is idmapped by the storage driver if the kernel supports it,
+	// the bind mounts are idmapped by the runtime. Runtimes which do not report
+	// support for idmapped mounts would silently ignore the mappings, so the
+	// mounts are chowned instead.
+	idMappingStrategy := ""
+	specgen.RemoveAnnotation(crioann.IDMappingStrategyAnnotation)
+	if containerIDMappings != nil {
+		idMappingStrategy = idMappingStrategyChown
+		if node.IDMappedMountsSupported() &&
+			s.config.RuntimeFeaturesForHandler(sb.RuntimeHandler()).SupportsIDMappedMounts() {
+			idMappingStrategy = idMappingStrategyIDMappedMounts
+		}
+		specgen.AddAnnotation(crioann.IDMappingStrategyAnnotation, idMappingStrategy)
+	}
+
 	for _, m := range mounts {
 		rspecMount := rspec.Mount{
 			Type:        "bind",
@@ -725,9 +788,17 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 			UIDMappings: m.UIDMappings,
 			GIDMappings: m.GIDMappings,
 		}
+		if idMappingStrategy == idMappingStrategyIDMappedMounts && rspecMount.UIDMappings == nil && rspecMount.GIDMappings == nil {
+			rspecMount.UIDMappings = toOCIIDMappings(containerIDMappings.UIDs())
+			rspecMount.GIDMappings = toOCIIDMappings(containerIDMappings.GIDs())
+		}
 		ctr.SpecAddMount(rspecMount)
 	}
 
+	if err := s.addWritablePaths(ctx, specgen, metadata.Name, sb.Annotations(), readOnlyRootfs); err != nil {
+		return nil, err
+	}
+
 	if ctr.WillRunSystemd() {
 		processLabel, err = selinux.InitLabel(processLabel)
 		if err != nil {
@@ -752,7 +823,7 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 
 	// Set up pids limit if pids cgroup is mounted
 	if node.CgroupHasPid() {
-		specgen.SetLinuxResourcesPidsLimit(s.config.PidsLimit)
+		specgen.SetLinuxResourcesPidsLimit(s.config.PidsLimitForHandler(sb.RuntimeHandler()))
 	}
 
 	// by default, the root path is an empty string. set it now.
@@ -789,8 +860,10 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 		rootPair = containerIDMappings.RootPair()
 
 		pathsToChown := []string{mountPoint, containerInfo.RunDir}
-		for _, m := range secretMounts {
-			pathsToChown = append(pathsToChown, m.Source)
+		if idMappingStrategy == idMappingStrategyChown {
+			for _, m := range secretMounts {
+				pathsToChown = append(pathsToChown, m.Source)
+			}
 		}
 		for _, path := range pathsToChown {
 			if err := makeAccessible(path, rootPair.UID, rootPair.GID, true); err != nil {
@@ -832,6 +905,10 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 		}
 	}()
 
+	if err := s.Runtime().ValidateSpecForRuntimeHandler(sb.RuntimeHandler(), specgen.Config); err != nil {
+		return nil, err
+	}
+
 	saveOptions := generate.ExportOptions{}
 	if err := specgen.SaveToFile(filepath.Join(containerInfo.Dir, "config.json"), saveOptions); err != nil {
 		return nil, err
@@ -1056,6 +1133,19 @@ func addOCIBindMounts(ctx context.Context, ctr ctrfactory.Container, mountLabel,
 	return volumes, ociMounts, nil
 }
 
+// toOCIIDMappings converts ID mappings into the ID mappings of a mount.
+func toOCIIDMappings(idMap []idtools.IDMap) []rspec.LinuxIDMapping {
+	ids := make([]rspec.LinuxIDMapping, 0, len(idMap))
+	for _, m := range idMap {
+		ids = append(ids, rspec.LinuxIDMapping{
+			ContainerID: uint32(m.ContainerID),
+			HostID:      uint32(m.HostID),
+			Size:        uint32(m.Size),
+		})
+	}
+	return ids
+}
+
 func getOCIMappings(m []*types.IDMapping) []rspec.LinuxIDMapping {
 	if m == nil {
 		return nil
@@ -2,9 +2,13 @@ package server
 
 import (
 	"context"
+	"reflect"
 	"testing"
 
+	"github.com/containers/storage/pkg/idtools"
 	"github.com/cri-o/cri-o/internal/factory/container"
+	libconfig "github.com/cri-o/cri-o/pkg/config"
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
 
@@ -158,3 +162,34 @@ func TestAddOCIBindsCGroupRW(t *testing.T) {
 		t.Error("Cgroup mount not added with RO.")
 	}
 }
+
+func TestToOCIIDMappings(t *testing.T) {
+	idMap := []idtools.IDMap{
+		{ContainerID: 0, HostID: 100000, Size: 65536},
+		{ContainerID: 65536, HostID: 300000, Size: 1},
+	}
+	expected := []rspec.LinuxIDMapping{
+		{ContainerID: 0, HostID: 100000, Size: 65536},
+		{ContainerID: 65536, HostID: 300000, Size: 1},
+	}
+	if res := toOCIIDMappings(idMap); !reflect.DeepEqual(res, expected) {
+		t.Errorf("expected %v, got %v", expected, res)
+	}
+}
+
+func TestWritablePathMount(t *testing.T) {
+	expected := rspec.Mount{
+		Destination: "/var/cache",
+		Type:        "tmpfs",
+		Source:      "tmpfs",
+		Options:     []string{"rw", "noexec", "nosuid", "nodev", "tmpcopyup", "size=1048576"},
+	}
+	if res := writablePathMount(&libconfig.WritablePath{Path: "/var/cache", Size: 1048576}); !reflect.DeepEqual(res, expected) {
+		t.Errorf("expected %v, got %v", expected, res)
+	}
+
+	expected.Options = expected.Options[:5]
+	if res := writablePathMount(&libconfig.WritablePath{Path: "/var/cache"}); !reflect.DeepEqual(res, expected) {
+		t.Errorf("expected %v, got %v", expected, res)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/cri-o/cri-o/internal/audit"
 	"github.com/cri-o/cri-o/internal/log"
 	"github.com/cri-o/cri-o/internal/oci"
 	"golang.org/x/net/context"
@@ -14,7 +15,15 @@ import (
 )
 
 // Exec prepares a streaming endpoint to execute a command in the container.
-func (s *Server) Exec(ctx context.Context, req *types.ExecRequest) (*types.ExecResponse, error) {
+func (s *Server) Exec(ctx context.Context, req *types.ExecRequest) (_ *types.ExecResponse, retErr error) {
+	event := s.startContainerAudit(ctx, audit.OperationExec, req.ContainerId)
+	if event != nil {
+		event.Command = req.Cmd
+		event.TTY = req.Tty
+		event.Stdin = req.Stdin
+	}
+	defer func() { s.auditLogger.Finish(ctx, event, retErr) }()
+
 	resp, err := s.getExec(req)
 	if err != nil {
 		return nil, fmt.Errorf("unable to prepare exec endpoint: %w", err)
@@ -3,6 +3,7 @@ package server
 import (
 	"errors"
 
+	"github.com/cri-o/cri-o/internal/audit"
 	"github.com/cri-o/cri-o/internal/log"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc/codes"
@@ -11,9 +12,20 @@ import (
 )
 
 // ExecSync runs a command in a container synchronously.
-func (s *Server) ExecSync(ctx context.Context, req *types.ExecSyncRequest) (*types.ExecSyncResponse, error) {
+func (s *Server) ExecSync(ctx context.Context, req *types.ExecSyncRequest) (res *types.ExecSyncResponse, retErr error) {
 	ctx, span := log.StartSpan(ctx)
 	defer span.End()
+	event := s.startContainerAudit(ctx, audit.OperationExecSync, req.ContainerId)
+	if event != nil {
+		event.Command = req.Cmd
+	}
+	defer func() {
+		if event != nil && res != nil {
+			exitCode := res.ExitCode
+			event.ExitCode = &exitCode
+		}
+		s.auditLogger.Finish(ctx, event, retErr)
+	}()
 	c, err := s.GetContainerFromShortID(ctx, req.ContainerId)
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "could not find container %q: %v", req.ContainerId, err)
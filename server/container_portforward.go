@@ -5,13 +5,20 @@ import (
 	"io"
 
 	"github.com/containers/storage/pkg/pools"
+	"github.com/cri-o/cri-o/internal/audit"
 	"github.com/cri-o/cri-o/internal/log"
 	"golang.org/x/net/context"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
 
 // PortForward prepares a streaming endpoint to forward ports from a PodSandbox.
-func (s *Server) PortForward(ctx context.Context, req *types.PortForwardRequest) (*types.PortForwardResponse, error) {
+func (s *Server) PortForward(ctx context.Context, req *types.PortForwardRequest) (_ *types.PortForwardResponse, retErr error) {
+	event := s.startSandboxAudit(ctx, audit.OperationPortForward, req.PodSandboxId)
+	if event != nil {
+		event.Ports = req.Port
+	}
+	defer func() { s.auditLogger.Finish(ctx, event, retErr) }()
+
 	resp, err := s.getPortForward(req)
 	if err != nil {
 		return nil, fmt.Errorf("unable to prepare portforward endpoint")
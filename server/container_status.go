@@ -118,10 +118,14 @@ func (s *Server) ContainerStatus(ctx context.Context, req *types.ContainerStatus
 }
 
 type containerInfo struct {
-	SandboxID   string    `json:"sandboxID"`
-	Pid         int       `json:"pid"`
-	RuntimeSpec spec.Spec `json:"runtimeSpec"`
-	Privileged  bool      `json:"privileged"`
+	SandboxID           string    `json:"sandboxID"`
+	Pid                 int       `json:"pid"`
+	RuntimeSpec         spec.Spec `json:"runtimeSpec"`
+	Privileged          bool      `json:"privileged"`
+	StopSignalChain     string    `json:"stopSignalChain,omitempty"`
+	StopSignalChainStep string    `json:"stopSignalChainStep,omitempty"`
+	IDMappingStrategy   string    `json:"idMappingStrategy,omitempty"`
+	WritablePaths       string    `json:"writablePaths,omitempty"`
 }
 
 type containerInfoCheckpointRestore struct {
@@ -137,10 +141,14 @@ func (s *Server) createContainerInfo(container *oci.Container) (map[string]strin
 
 	bytes, err := func(metadata *storage.RuntimeContainerMetadata) ([]byte, error) {
 		localContainerInfo := containerInfo{
-			SandboxID:   container.Sandbox(),
-			Pid:         container.State().Pid,
-			RuntimeSpec: container.Spec(),
-			Privileged:  metadata.Privileged,
+			SandboxID:           container.Sandbox(),
+			Pid:                 container.State().Pid,
+			RuntimeSpec:         container.Spec(),
+			Privileged:          metadata.Privileged,
+			StopSignalChain:     container.StopSignalChain().String(),
+			StopSignalChainStep: container.StopSignalChainStep(),
+			IDMappingStrategy:   container.IDMappingStrategy(),
+			WritablePaths:       container.WritablePaths(),
 		}
 
 		if s.config.CheckpointRestore() {
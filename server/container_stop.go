@@ -47,6 +47,19 @@ func (s *Server) StopContainer(ctx context.Context, req *types.StopContainerRequ
 	return &types.StopContainerResponse{}, nil
 }
 
+// defaultInternalStopTimeout is the grace period in seconds used when CRI-O
+// stops containers on its own, for example when removing a pod.
+const defaultInternalStopTimeout int64 = 10
+
+// internalStopTimeout returns the grace period used when CRI-O stops the
+// containers of the provided runtime handler on its own.
+func (s *Server) internalStopTimeout(runtimeHandler string) int64 {
+	if timeout := s.config.CtrStopTimeoutForHandler(runtimeHandler); timeout > 0 {
+		return timeout
+	}
+	return defaultInternalStopTimeout
+}
+
 // stopContainer stops a running container with a grace period (i.e., timeout).
 func (s *Server) stopContainer(ctx context.Context, ctr *oci.Container, timeout int64) error {
 	ctx, span := log.StartSpan(ctx)
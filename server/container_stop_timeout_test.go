@@ -0,0 +1,31 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/cri-o/cri-o/pkg/config"
+)
+
+func TestInternalStopTimeout(t *testing.T) {
+	s := &Server{}
+	s.config.CtrStopTimeout = 30
+	s.config.DefaultRuntime = "runc"
+	s.config.Runtimes = config.Runtimes{
+		"runc":  &config.RuntimeHandler{},
+		"short": &config.RuntimeHandler{CtrStopTimeout: 2},
+	}
+
+	for _, tc := range []struct {
+		handler string
+		want    int64
+	}{
+		{handler: "", want: defaultInternalStopTimeout},
+		{handler: "runc", want: defaultInternalStopTimeout},
+		{handler: "short", want: 2},
+		{handler: "missing", want: defaultInternalStopTimeout},
+	} {
+		if got := s.internalStopTimeout(tc.handler); got != tc.want {
+			t.Errorf("handler %q: expected %d, got %d", tc.handler, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/cri-o/cri-o/internal/log"
+	crioann "github.com/cri-o/cri-o/pkg/annotations"
+	libconfig "github.com/cri-o/cri-o/pkg/config"
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-tools/generate"
+)
+
+// addWritablePaths adds tmpfs mounts for the writable paths of a container
+// with a read-only rootfs. A writable path must not be mounted already.
+func (s *Server) addWritablePaths(ctx context.Context, specgen *generate.Generator, ctrName string, sandboxAnnotations map[string]string, readOnlyRootfs bool) error {
+	// the annotation is set by CRI-O only
+	specgen.RemoveAnnotation(crioann.AppliedWritablePathsAnnotation)
+
+	paths, err := s.config.Workloads.WritablePaths(ctrName, sandboxAnnotations)
+	if err != nil {
+		return fmt.Errorf("invalid writable paths: %w", err)
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+	if !readOnlyRootfs {
+		log.Debugf(ctx, "Ignoring writable paths of container %s with a writable rootfs", ctrName)
+		return nil
+	}
+
+	for i := range paths {
+		if mountExists(specgen.Mounts(), paths[i].Path) {
+			return fmt.Errorf("writable path %s is already mounted", paths[i].Path)
+		}
+		specgen.AddMount(writablePathMount(&paths[i]))
+	}
+	specgen.AddAnnotation(crioann.AppliedWritablePathsAnnotation, paths.String())
+	return nil
+}
+
+// writablePathMount returns the tmpfs mount of a writable path, which
+// contains a copy of the content of the image at the path. Like the tmpfs
+// mounts of read_only, it is mounted noexec, so that a read-only rootfs
+// cannot be circumvented by writing and running new binaries.
+func writablePathMount(path *libconfig.WritablePath) rspec.Mount {
+	// tmpcopyup is a runc extension and is not part of the OCI spec.
+	options := []string{"rw", "noexec", "nosuid", "nodev", "tmpcopyup"}
+	if path.Size > 0 {
+		options = append(options, "size="+strconv.FormatInt(path.Size, 10))
+	}
+	return rspec.Mount{
+		Destination: path.Path,
+		Type:        "tmpfs",
+		Source:      "tmpfs",
+		Options:     options,
+	}
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/cri-o/cri-o/internal/config/cnimgr"
+	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/internal/oci"
+	"github.com/cri-o/cri-o/pkg/types"
+)
+
+const (
+	// maxRecordedEvents is the number of events kept for the events
+	// endpoint of the inspect API.
+	maxRecordedEvents = 1000
+
+	// eventReasonNetworkReady is the reason of the event if the CNI network
+	// became ready.
+	eventReasonNetworkReady = "NetworkReady"
+
+	// eventReasonDNSConfigAdjusted is the reason of the event if the DNS
+	// config of a pod had to be adjusted.
+	eventReasonDNSConfigAdjusted = "DNSConfigAdjusted"
+
+	// eventReasonSeccompSyscallAudited is the reason of the event if a
+	// container used a syscall blocked by its seccomp profile, which got
+	// allowed by the audit action of the seccomp notifier.
+	eventReasonSeccompSyscallAudited = "SeccompSyscallAudited"
+)
+
+// eventRecorder keeps the latest events, which are served by the events
+// endpoint of the inspect API. CRI container events only support the
+// lifecycle of containers, which is why all other events are recorded here.
+type eventRecorder struct {
+	sync.Mutex
+	events []types.Event
+}
+
+// record adds the event and drops the oldest one if the limit is reached.
+func (r *eventRecorder) record(event *types.Event) {
+	r.Lock()
+	defer r.Unlock()
+	if len(r.events) >= maxRecordedEvents {
+		r.events = r.events[1:]
+	}
+	r.events = append(r.events, *event)
+}
+
+// list returns a copy of the recorded events, the oldest first.
+func (r *eventRecorder) list() []types.Event {
+	r.Lock()
+	defer r.Unlock()
+	return append([]types.Event{}, r.events...)
+}
+
+// recordEvent logs and records the event.
+func (s *Server) recordEvent(ctx context.Context, event *types.Event) {
+	if event.Time == 0 {
+		event.Time = time.Now().UnixNano()
+	}
+	if event.Type == types.EventTypeWarning {
+		log.Warnf(ctx, "Event %s: %s", event.Reason, event.Message)
+	} else {
+		log.Infof(ctx, "Event %s: %s", event.Reason, event.Message)
+	}
+	s.events.record(event)
+}
+
+// recordSandboxWarning records a warning event of the pod sandbox.
+func (s *Server) recordSandboxWarning(ctx context.Context, sandboxID, reason, message string) {
+	s.recordEvent(ctx, &types.Event{
+		Type:         types.EventTypeWarning,
+		Reason:       reason,
+		Message:      message,
+		PodSandboxID: sandboxID,
+	})
+}
+
+// recordContainerWarning records a warning event of the container.
+func (s *Server) recordContainerWarning(ctx context.Context, ctr *oci.Container, reason, message string) {
+	s.recordEvent(ctx, &types.Event{
+		Type:         types.EventTypeWarning,
+		Reason:       reason,
+		Message:      message,
+		PodSandboxID: ctr.Sandbox(),
+		ContainerID:  ctr.ID(),
+	})
+}
+
+// handleCNIStatus records an event whenever the readiness of the CNI network
+// changes, whereas err is nil if it became ready.
+func (s *Server) handleCNIStatus(err error) {
+	event := &types.Event{
+		Type:    types.EventTypeNormal,
+		Reason:  eventReasonNetworkReady,
+		Message: "CNI network " + s.config.CNIPlugin().GetDefaultNetworkName() + " is ready",
+	}
+	if err != nil {
+		event.Type = types.EventTypeWarning
+		event.Reason = cnimgr.ReasonNetworkPluginNotReady
+		var notReady *cnimgr.NotReadyError
+		if errors.As(err, &notReady) {
+			event.Reason = notReady.Reason
+		}
+		event.Message = err.Error()
+	}
+	s.recordEvent(context.Background(), event)
+}
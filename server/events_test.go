@@ -0,0 +1,41 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cri-o/cri-o/pkg/types"
+)
+
+func TestEventRecorder(t *testing.T) {
+	recorder := eventRecorder{}
+	for i := 0; i < maxRecordedEvents+10; i++ {
+		recorder.record(&types.Event{Reason: fmt.Sprint(i)})
+	}
+
+	events := recorder.list()
+	if len(events) != maxRecordedEvents {
+		t.Fatalf("expected %d events, got %d", maxRecordedEvents, len(events))
+	}
+	if events[0].Reason != "10" {
+		t.Fatalf("expected the oldest events to be dropped, got %s first", events[0].Reason)
+	}
+	if last := events[len(events)-1].Reason; last != fmt.Sprint(maxRecordedEvents+9) {
+		t.Fatalf("expected the newest event last, got %s", last)
+	}
+}
+
+func TestRecordSandboxWarning(t *testing.T) {
+	s := &Server{}
+	s.recordSandboxWarning(context.Background(), "id", eventReasonDNSConfigAdjusted, "message")
+
+	events := s.events.list()
+	if len(events) != 1 {
+		t.Fatalf("expected one event, got %d", len(events))
+	}
+	e := events[0]
+	if e.Type != types.EventTypeWarning || e.PodSandboxID != "id" || e.Reason != eventReasonDNSConfigAdjusted || e.Time == 0 {
+		t.Fatalf("unexpected event %+v", e)
+	}
+}
@@ -0,0 +1,182 @@
+package server
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/cri-o/cri-o/internal/hostport"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/pkg/types"
+	"github.com/cri-o/cri-o/server/metrics"
+
+	utilnet "k8s.io/utils/net"
+)
+
+const (
+	// hostportDriftMissing is the drift type of hostport rules which got
+	// re-added by the reconciliation.
+	hostportDriftMissing = "missing"
+
+	// hostportDriftStale is the drift type of hostport rules which got
+	// removed by the reconciliation.
+	hostportDriftStale = "stale"
+)
+
+// hostportReconciliation stores the result of the last hostport
+// reconciliation.
+type hostportReconciliation struct {
+	sync.Mutex
+	last *types.HostPortReconciliation
+}
+
+// sandboxHostportMappings returns the hostport mappings of the sandbox for the
+// first IP of each IP family.
+func sandboxHostportMappings(sb *sandbox.Sandbox, ips []net.IP) []*hostport.PodPortMapping {
+	sbPortMappings := sb.PortMappings()
+	if len(sbPortMappings) == 0 {
+		return nil
+	}
+
+	// only do portmapping to the first IP of each IP family
+	foundIPv4 := false
+	foundIPv6 := false
+	mappings := []*hostport.PodPortMapping{}
+	for _, ip := range ips {
+		if utilnet.IsIPv6(ip) {
+			if foundIPv6 {
+				continue
+			}
+			foundIPv6 = true
+		} else {
+			if foundIPv4 {
+				continue
+			}
+			foundIPv4 = true
+		}
+		mappings = append(mappings, &hostport.PodPortMapping{
+			Name:         sb.Name(),
+			PortMappings: sbPortMappings,
+			IP:           ip,
+			HostNetwork:  false,
+		})
+	}
+	return mappings
+}
+
+// startHostportReconciliation reconciles the hostport rules once and
+// afterwards periodically in the configured interval until the server gets
+// shut down.
+func (s *Server) startHostportReconciliation(ctx context.Context) {
+	s.reconcileHostports(ctx)
+
+	interval, err := s.config.HostPortReconcileIntervalDuration()
+	if err != nil {
+		log.Errorf(ctx, "Unable to get hostport reconcile interval: %v", err)
+		return
+	}
+	if interval == 0 {
+		log.Debugf(ctx, "Periodic hostport reconciliation is disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.reconcileHostports(ctx)
+			case <-s.monitorsChan:
+				log.Debugf(ctx, "Closing hostport reconciliation...")
+				return
+			}
+		}
+	}()
+}
+
+// reconcileHostports compares the installed hostport rules with the port
+// mappings of the sandboxes. Missing rules get re-added and rules of
+// sandboxes which do not exist any more get removed.
+func (s *Server) reconcileHostports(ctx context.Context) {
+	ctx, span := log.StartSpan(ctx)
+	defer span.End()
+
+	// Hostports of sandboxes get only added or removed while holding the
+	// read lock, which makes the snapshot consistent with the installed
+	// rules.
+	s.hostportReconcileLock.Lock()
+	defer s.hostportReconcileLock.Unlock()
+
+	podPortMappings := make(map[string][]*hostport.PodPortMapping)
+	for _, sb := range s.ContainerServer.ListSandboxes() {
+		if sb.HostNetwork() || sb.NetworkStopped() || len(sb.PortMappings()) == 0 {
+			continue
+		}
+		ips := make([]net.IP, 0, len(sb.IPs()))
+		for _, ip := range sb.IPs() {
+			if parsed := net.ParseIP(ip); parsed != nil {
+				ips = append(ips, parsed)
+			}
+		}
+		mappings := sandboxHostportMappings(sb, ips)
+		if len(mappings) == 0 {
+			// The network of the sandbox is still being set up, which
+			// means that its hostports have to be kept as they are.
+			mappings = []*hostport.PodPortMapping{{
+				Name:         sb.Name(),
+				PortMappings: sb.PortMappings(),
+				HostNetwork:  false,
+			}}
+		}
+		podPortMappings[sb.ID()] = mappings
+	}
+
+	status := &types.HostPortReconciliation{}
+	res, err := s.hostportManager.Reconcile(podPortMappings)
+	if err != nil {
+		log.Warnf(ctx, "Failed to reconcile hostports: %v", err)
+		status.Error = err.Error()
+	}
+	if res != nil {
+		status.Readded = res.Readded
+		status.Removed = res.Removed
+		if len(res.Readded) > 0 {
+			log.Infof(ctx, "Re-added missing hostports of sandboxes: %v", res.Readded)
+			metrics.Instance().MetricHostportReconcileDriftAdd(float64(len(res.Readded)), hostportDriftMissing)
+		}
+		if len(res.Removed) > 0 {
+			log.Infof(ctx, "Removed stale hostport chains: %v", res.Removed)
+			metrics.Instance().MetricHostportReconcileDriftAdd(float64(len(res.Removed)), hostportDriftStale)
+		}
+	}
+	status.Time = time.Now().UnixNano()
+
+	s.hostportReconciliation.Lock()
+	s.hostportReconciliation.last = status
+	s.hostportReconciliation.Unlock()
+}
+
+// getHostPortsInfo returns the hostport information for the inspect API.
+func (s *Server) getHostPortsInfo() types.HostPortsInfo {
+	reservations := s.hostportReservations.List()
+	info := types.HostPortsInfo{
+		Reservations: make([]types.HostPortReservation, 0, len(reservations)),
+	}
+	for i := range reservations {
+		info.Reservations = append(info.Reservations, types.HostPortReservation{
+			HostPort: reservations[i].HostPort,
+			Protocol: string(reservations[i].Protocol),
+			HostIP:   reservations[i].HostIP,
+			PodID:    reservations[i].PodID,
+			PodName:  reservations[i].PodName,
+		})
+	}
+
+	s.hostportReconciliation.Lock()
+	defer s.hostportReconciliation.Unlock()
+	info.Reconciliation = s.hostportReconciliation.last
+	return info
+}
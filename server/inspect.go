@@ -8,6 +8,7 @@ import (
 	"net/http/pprof"
 
 	"github.com/containers/storage/pkg/idtools"
+	"github.com/cri-o/cri-o/internal/config/seccomp"
 	"github.com/cri-o/cri-o/internal/lib/sandbox"
 	"github.com/cri-o/cri-o/internal/log"
 	"github.com/cri-o/cri-o/internal/oci"
@@ -47,6 +48,7 @@ func (s *Server) getInfo() types.CrioInfo {
 		StorageRoot:       s.config.Root,
 		CgroupDriver:      s.config.CgroupManager().Name(),
 		DefaultIDMappings: s.getIDMappingsInfo(),
+		RuntimeFeatures:   s.config.Runtimes.Features(),
 	}
 }
 
@@ -56,6 +58,20 @@ var (
 	errSandboxNotFound = errors.New("sandbox for container not found")
 )
 
+// seccompAuditedSyscalls returns the syscall counts of a container running
+// with the seccomp notifier audit action.
+func (s *Server) seccompAuditedSyscalls(id string) map[string]uint64 {
+	result, ok := s.seccompNotifiers.Load(id)
+	if !ok {
+		return nil
+	}
+	notifier, ok := result.(*seccomp.Notifier)
+	if !ok || !notifier.Audit() {
+		return nil
+	}
+	return notifier.SyscallCounts()
+}
+
 func (s *Server) getContainerInfo(ctx context.Context, id string, getContainerFunc, getInfraContainerFunc func(ctx context.Context, id string) *oci.Container, getSandboxFunc func(ctx context.Context, id string) *sandbox.Sandbox) (types.ContainerInfo, error) {
 	ctx, span := log.StartSpan(ctx)
 	defer span.End()
@@ -96,24 +112,27 @@ func (s *Server) getContainerInfo(ctx context.Context, id string, getContainerFu
 		}
 	}
 	return types.ContainerInfo{
-		Name:            ctr.Name(),
-		Pid:             pidToReturn,
-		Image:           ctr.ImageName(),
-		ImageRef:        ctr.ImageRef(),
-		CreatedTime:     ctrState.Created.UnixNano(),
-		Labels:          ctr.Labels(),
-		Annotations:     ctr.Annotations(),
-		CrioAnnotations: ctr.CrioAnnotations(),
-		Root:            ctr.MountPoint(),
-		LogPath:         ctr.LogPath(),
-		Sandbox:         ctr.Sandbox(),
-		IPs:             sb.IPs(),
+		Name:                   ctr.Name(),
+		Pid:                    pidToReturn,
+		Image:                  ctr.ImageName(),
+		ImageRef:               ctr.ImageRef(),
+		CreatedTime:            ctrState.Created.UnixNano(),
+		Labels:                 ctr.Labels(),
+		Annotations:            ctr.Annotations(),
+		CrioAnnotations:        ctr.CrioAnnotations(),
+		Root:                   ctr.MountPoint(),
+		LogPath:                ctr.LogPath(),
+		Sandbox:                ctr.Sandbox(),
+		IPs:                    sb.IPs(),
+		SeccompAuditedSyscalls: s.seccompAuditedSyscalls(ctr.ID()),
 	}, nil
 }
 
 const (
 	InspectConfigEndpoint     = "/config"
 	InspectContainersEndpoint = "/containers"
+	InspectEventsEndpoint     = "/events"
+	InspectHostPortsEndpoint  = "/hostports"
 	InspectInfoEndpoint       = "/info"
 	InspectPauseEndpoint      = "/pause"
 	InspectUnpauseEndpoint    = "/unpause"
@@ -149,6 +168,31 @@ func (s *Server) GetExtendInterfaceMux(enableProfile bool) *bone.Mux {
 		}
 	}))
 
+	mux.Get(InspectHostPortsEndpoint, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		hi := s.getHostPortsInfo()
+		js, err := json.Marshal(hi)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(js); err != nil {
+			logrus.Errorf("Unable to write response JSON: %v", err)
+		}
+	}))
+
+	mux.Get(InspectEventsEndpoint, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		js, err := json.Marshal(s.events.list())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(js); err != nil {
+			logrus.Errorf("Unable to write response JSON: %v", err)
+		}
+	}))
+
 	mux.Get(InspectContainersEndpoint+"/:id", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		ctx := context.TODO()
 		containerID := bone.GetValue(req, "id")
@@ -44,6 +44,32 @@ var _ = t.Describe("Inspect", func() {
 			Expect(recorder.Code).To(BeEquivalentTo(http.StatusOK))
 		})
 
+		It("should succeed with /hostports route", func() {
+			// Given
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/hostports", http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(request).NotTo(BeNil())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusOK))
+			Expect(recorder.Body.String()).To(ContainSubstring("reconciliation"))
+		})
+
+		It("should succeed with /events route", func() {
+			// Given
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/events", http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(request).NotTo(BeNil())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusOK))
+			Expect(recorder.Body.String()).To(HavePrefix("["))
+		})
+
 		It("should succeed with valid /containers route", func() {
 			ctx := context.TODO()
 			// Given
@@ -2,13 +2,22 @@ package server
 
 import (
 	"context"
+	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 
+	"github.com/cri-o/cri-o/internal/config/seccomp"
+	"github.com/cri-o/cri-o/internal/hostport"
 	"github.com/cri-o/cri-o/internal/lib/sandbox"
 	"github.com/cri-o/cri-o/internal/oci"
+	ann "github.com/cri-o/cri-o/pkg/annotations"
 	"github.com/cri-o/cri-o/pkg/config"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
 
@@ -222,3 +231,62 @@ func TestGetContainerInfoSandboxNotFound(t *testing.T) {
 		t.Fatalf("expected errSandboxNotFound error, got %v", err)
 	}
 }
+
+func TestGetHostPortsInfo(t *testing.T) {
+	s := &Server{hostportReservations: hostport.NewReservations()}
+	if err := s.reserveHostports("id1", "pod1", []*hostport.PortMapping{
+		{HostPort: 8080, ContainerPort: 80, Protocol: v1.ProtocolTCP},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := s.reserveHostports("id2", "pod2", []*hostport.PortMapping{
+		{HostPort: 8080, ContainerPort: 8080, Protocol: v1.ProtocolTCP, HostIP: "127.0.0.1"},
+	})
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.AlreadyExists {
+		t.Fatalf("expected AlreadyExists error, got %v", err)
+	}
+	details := st.Details()
+	if len(details) != 1 {
+		t.Fatalf("expected one error detail, got %d", len(details))
+	}
+	info, ok := details[0].(*errdetails.ErrorInfo)
+	if !ok || info.Metadata["ownerPodID"] != "id1" || info.Metadata["ownerPodName"] != "pod1" {
+		t.Fatalf("expected error info naming pod1(id1), got %v", details[0])
+	}
+
+	hi := s.getHostPortsInfo()
+	if len(hi.Reservations) != 1 {
+		t.Fatalf("expected one reservation, got %d", len(hi.Reservations))
+	}
+	if hi.Reservations[0].PodID != "id1" || hi.Reservations[0].HostPort != 8080 {
+		t.Fatalf("expected reservation of id1 for 8080, got %+v", hi.Reservations[0])
+	}
+	if hi.Reconciliation != nil {
+		t.Fatalf("expected no reconciliation, got %+v", hi.Reconciliation)
+	}
+}
+
+func TestSeccompAuditedSyscalls(t *testing.T) {
+	s := &Server{}
+	if counts := s.seccompAuditedSyscalls("testid"); counts != nil {
+		t.Fatalf("expected no syscall counts without notifier, got %v", counts)
+	}
+
+	notifier, err := seccomp.NewNotifier(context.Background(), nil, "testid",
+		filepath.Join(t.TempDir(), "testid"),
+		map[string]string{ann.SeccompNotifierActionAnnotation: ann.SeccompNotifierActionAudit})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer notifier.Close()
+	notifier.AddSyscalls("mkdir", 3)
+	notifier.AddSyscall("mkdir")
+	s.seccompNotifiers.Store("testid", notifier)
+
+	counts := s.seccompAuditedSyscalls("testid")
+	if !reflect.DeepEqual(counts, map[string]uint64{"mkdir": 4}) {
+		t.Fatalf("expected 4 calls of mkdir, got %v", counts)
+	}
+}
@@ -88,6 +88,10 @@ type Metrics struct {
 	metricImageLayerReuseTotal                *prometheus.CounterVec
 	metricContainersOOMCountTotal             *prometheus.CounterVec
 	metricContainersSeccompNotifierCountTotal *prometheus.CounterVec
+	metricContainersExecSyncOutputTruncated   *prometheus.CounterVec
+	metricHostportReconcileDriftTotal         *prometheus.CounterVec
+	metricNamespacePoolRequestsTotal          *prometheus.CounterVec
+	metricUsernsRanges                        *prometheus.GaugeVec
 }
 
 var instance *Metrics
@@ -318,6 +322,38 @@ func New(config *libconfig.MetricsConfig) *Metrics {
 			},
 			[]string{"name", "syscall"},
 		),
+		metricContainersExecSyncOutputTruncated: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.ContainersExecSyncOutputTruncatedTotal.String(),
+				Help:      "Number of truncated exec sync outputs by container name and stream",
+			},
+			[]string{"name", "stream"},
+		),
+		metricHostportReconcileDriftTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.HostportReconcileDriftTotal.String(),
+				Help:      "Number of hostport rules fixed by the reconciliation by drift type",
+			},
+			[]string{"type"},
+		),
+		metricNamespacePoolRequestsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.NamespacePoolRequestsTotal.String(),
+				Help:      "Number of requests for pooled namespaces by result (hit or miss)",
+			},
+			[]string{"result"},
+		),
+		metricUsernsRanges: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.UsernsRanges.String(),
+				Help:      "Number of user namespace ID ranges by state (allocated or available)",
+			},
+			[]string{"state"},
+		),
 	}
 	return Instance()
 }
@@ -448,15 +484,51 @@ func (m *Metrics) MetricContainersOOMTotalInc() {
 	m.metricContainersOOMTotal.Inc()
 }
 
-func (m *Metrics) MetricContainersSeccompNotifierCountTotalInc(name, syscall string) {
+func (m *Metrics) MetricContainersSeccompNotifierCountTotalAdd(add float64, name, syscall string) {
 	c, err := m.metricContainersSeccompNotifierCountTotal.GetMetricWithLabelValues(name, syscall)
 	if err != nil {
 		logrus.Warnf("Unable to write container seccomp notifier metric: %v", err)
 		return
 	}
+	c.Add(add)
+}
+
+func (m *Metrics) MetricContainersExecSyncOutputTruncatedInc(name, stream string) {
+	c, err := m.metricContainersExecSyncOutputTruncated.GetMetricWithLabelValues(name, stream)
+	if err != nil {
+		logrus.Warnf("Unable to write container exec sync output truncation metric: %v", err)
+		return
+	}
 	c.Inc()
 }
 
+func (m *Metrics) MetricHostportReconcileDriftAdd(add float64, driftType string) {
+	c, err := m.metricHostportReconcileDriftTotal.GetMetricWithLabelValues(driftType)
+	if err != nil {
+		logrus.Warnf("Unable to write hostport reconcile drift metric: %v", err)
+		return
+	}
+	c.Add(add)
+}
+
+func (m *Metrics) MetricNamespacePoolRequestsInc(result string) {
+	c, err := m.metricNamespacePoolRequestsTotal.GetMetricWithLabelValues(result)
+	if err != nil {
+		logrus.Warnf("Unable to write namespace pool requests metric: %v", err)
+		return
+	}
+	c.Inc()
+}
+
+func (m *Metrics) MetricUsernsRangesSet(state string, value int) {
+	c, err := m.metricUsernsRanges.GetMetricWithLabelValues(state)
+	if err != nil {
+		logrus.Warnf("Unable to write userns ranges metric: %v", err)
+		return
+	}
+	c.Set(float64(value))
+}
+
 func (m *Metrics) MetricImagePullsLayerSizeObserve(size int64) {
 	m.metricImagePullsLayerSize.Observe(float64(size))
 }
@@ -567,17 +639,21 @@ func (m *Metrics) createEndpoint() (*http.ServeMux, error) {
 		collectors.ContainersOOM:           m.metricContainersOOM,
 		collectors.ProcessesDefunct:        m.metricProcessesDefunct,
 
-		collectors.OperationsTotal:                     m.metricOperationsTotal,
-		collectors.OperationsLatencySeconds:            m.metricOperationsLatencySeconds,
-		collectors.OperationsLatencySecondsTotal:       m.metricOperationsLatencySecondsTotal,
-		collectors.OperationsErrorsTotal:               m.metricOperationsErrorsTotal,
-		collectors.ImagePullsBytesTotal:                m.metricImagePullsBytesTotal,
-		collectors.ImagePullsSkippedBytesTotal:         m.metricImagePullsSkippedBytesTotal,
-		collectors.ImagePullsFailureTotal:              m.metricImagePullsFailureTotal,
-		collectors.ImagePullsSuccessTotal:              m.metricImagePullsSuccessTotal,
-		collectors.ImageLayerReuseTotal:                m.metricImageLayerReuseTotal,
-		collectors.ContainersOOMCountTotal:             m.metricContainersOOMCountTotal,
-		collectors.ContainersSeccompNotifierCountTotal: m.metricContainersSeccompNotifierCountTotal,
+		collectors.OperationsTotal:                        m.metricOperationsTotal,
+		collectors.OperationsLatencySeconds:               m.metricOperationsLatencySeconds,
+		collectors.OperationsLatencySecondsTotal:          m.metricOperationsLatencySecondsTotal,
+		collectors.OperationsErrorsTotal:                  m.metricOperationsErrorsTotal,
+		collectors.ImagePullsBytesTotal:                   m.metricImagePullsBytesTotal,
+		collectors.ImagePullsSkippedBytesTotal:            m.metricImagePullsSkippedBytesTotal,
+		collectors.ImagePullsFailureTotal:                 m.metricImagePullsFailureTotal,
+		collectors.ImagePullsSuccessTotal:                 m.metricImagePullsSuccessTotal,
+		collectors.ImageLayerReuseTotal:                   m.metricImageLayerReuseTotal,
+		collectors.ContainersOOMCountTotal:                m.metricContainersOOMCountTotal,
+		collectors.ContainersSeccompNotifierCountTotal:    m.metricContainersSeccompNotifierCountTotal,
+		collectors.ContainersExecSyncOutputTruncatedTotal: m.metricContainersExecSyncOutputTruncated,
+		collectors.HostportReconcileDriftTotal:            m.metricHostportReconcileDriftTotal,
+		collectors.NamespacePoolRequestsTotal:             m.metricNamespacePoolRequestsTotal,
+		collectors.UsernsRanges:                           m.metricUsernsRanges,
 	} {
 		if m.config.MetricsCollectors.Contains(collector) {
 			logrus.Debugf("Enabling metric: %s", collector.Stripped())
@@ -101,6 +101,18 @@ const (
 
 	// ContainersSeccompNotifierCountTotal is the key for the CRI-O container seccomp notifier metrics per container name and syscalls.
 	ContainersSeccompNotifierCountTotal Collector = crioPrefix + "containers_seccomp_notifier_count_total"
+
+	// ContainersExecSyncOutputTruncatedTotal is the key for the CRI-O exec sync output truncation metrics per container name and stream.
+	ContainersExecSyncOutputTruncatedTotal Collector = crioPrefix + "containers_exec_sync_output_truncated_total"
+
+	// HostportReconcileDriftTotal is the key for the CRI-O hostport reconciliation drift metrics per drift type.
+	HostportReconcileDriftTotal Collector = crioPrefix + "hostport_reconcile_drift_total"
+
+	// NamespacePoolRequestsTotal is the key for the CRI-O namespace pool requests metrics per result.
+	NamespacePoolRequestsTotal Collector = crioPrefix + "namespace_pool_requests_total"
+
+	// UsernsRanges is the key for the CRI-O user namespace ID range metrics per state.
+	UsernsRanges Collector = crioPrefix + "userns_ranges"
 )
 
 // FromSlice converts a string slice to a Collectors type.
@@ -148,6 +160,10 @@ func All() Collectors {
 		ImageLayerReuseTotal.Stripped(),
 		ContainersOOMCountTotal.Stripped(),
 		ContainersSeccompNotifierCountTotal.Stripped(),
+		ContainersExecSyncOutputTruncatedTotal.Stripped(),
+		HostportReconcileDriftTotal.Stripped(),
+		NamespacePoolRequestsTotal.Stripped(),
+		UsernsRanges.Stripped(),
 	}
 }
 
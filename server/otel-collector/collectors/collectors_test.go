@@ -61,11 +61,15 @@ var _ = t.Describe("Collectors", func() {
 				collectors.ImageLayerReuseTotal,
 				collectors.ContainersOOMCountTotal,
 				collectors.ContainersSeccompNotifierCountTotal,
+				collectors.ContainersExecSyncOutputTruncatedTotal,
+				collectors.HostportReconcileDriftTotal,
+				collectors.NamespacePoolRequestsTotal,
+				collectors.UsernsRanges,
 			} {
 				Expect(all.Contains(collector)).To(BeTrue())
 			}
 
-			Expect(all).To(HaveLen(25))
+			Expect(all).To(HaveLen(29))
 		})
 	})
 
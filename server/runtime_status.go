@@ -1,8 +1,11 @@
 package server
 
 import (
+	"errors"
 	"fmt"
 
+	"github.com/cri-o/cri-o/internal/config/cnimgr"
+	json "github.com/json-iterator/go"
 	"golang.org/x/net/context"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
@@ -24,15 +27,29 @@ func (s *Server) Status(ctx context.Context, req *types.StatusRequest) (*types.S
 	if err := s.config.CNIPluginReadyOrError(); err != nil {
 		networkCondition.Status = false
 		networkCondition.Reason = networkNotReadyReason
+		var notReadyErr *cnimgr.NotReadyError
+		if errors.As(err, &notReadyErr) {
+			networkCondition.Reason = notReadyErr.Reason
+		}
 		networkCondition.Message = fmt.Sprintf("Network plugin returns error: %v", err)
 	}
 
-	return &types.StatusResponse{
+	resp := &types.StatusResponse{
 		Status: &types.RuntimeStatus{
 			Conditions: []*types.RuntimeCondition{
 				runtimeCondition,
 				networkCondition,
 			},
 		},
-	}, nil
+	}
+
+	if req.Verbose {
+		features, err := json.Marshal(s.config.Runtimes.Features())
+		if err != nil {
+			return nil, fmt.Errorf("marshal runtime features: %w", err)
+		}
+		resp.Info = map[string]string{"runtimeFeatures": string(features)}
+	}
+
+	return resp, nil
 }
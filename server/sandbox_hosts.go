@@ -0,0 +1,62 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+
+	json "github.com/json-iterator/go"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// parseHostAliases parses the value of the host aliases annotation, which is
+// a JSON list of Kubernetes HostAliases.
+func parseHostAliases(value string) ([]v1.HostAlias, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	hostAliases := []v1.HostAlias{}
+	if err := json.Unmarshal([]byte(value), &hostAliases); err != nil {
+		return nil, err
+	}
+	for i, hostAlias := range hostAliases {
+		if net.ParseIP(hostAlias.IP) == nil {
+			return nil, fmt.Errorf("entry %d: invalid IP %q", i+1, hostAlias.IP)
+		}
+		if len(hostAlias.Hostnames) == 0 {
+			return nil, fmt.Errorf("entry %d: no hostnames", i+1)
+		}
+		for _, hostname := range hostAlias.Hostnames {
+			if errs := validation.IsDNS1123Subdomain(hostname); len(errs) > 0 {
+				return nil, fmt.Errorf("entry %d: invalid hostname %q: %s", i+1, hostname, strings.Join(errs, ", "))
+			}
+		}
+	}
+	return hostAliases, nil
+}
+
+// hostsFileContent returns the content of the hosts file of a sandbox, which
+// has the same layout as the hosts file managed by the kubelet.
+func hostsFileContent(hostname string, podIPs []string, hostAliases []v1.HostAlias) []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString("# Kubernetes-managed hosts file (CRI-O).\n")
+	buffer.WriteString("127.0.0.1\tlocalhost\n")
+	buffer.WriteString("::1\tlocalhost ip6-localhost ip6-loopback\n")
+	buffer.WriteString("fe00::0\tip6-localnet\n")
+	buffer.WriteString("fe00::0\tip6-mcastprefix\n")
+	buffer.WriteString("fe00::1\tip6-allnodes\n")
+	buffer.WriteString("fe00::2\tip6-allrouters\n")
+	for _, podIP := range podIPs {
+		fmt.Fprintf(&buffer, "%s\t%s\n", podIP, hostname)
+	}
+	if len(hostAliases) > 0 {
+		buffer.WriteString("\n# Entries added by HostAliases.\n")
+		for _, hostAlias := range hostAliases {
+			fmt.Fprintf(&buffer, "%s\t%s\n", hostAlias.IP, strings.Join(hostAlias.Hostnames, "\t"))
+		}
+	}
+	return buffer.Bytes()
+}
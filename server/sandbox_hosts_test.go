@@ -0,0 +1,69 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestParseHostAliases(t *testing.T) {
+	testCases := []struct {
+		value       string
+		expected    []v1.HostAlias
+		expectError bool
+	}{
+		{value: "", expected: nil},
+		{
+			value: `[{"ip": "10.0.0.1", "hostnames": ["foo.local", "bar"]}, {"ip": "fd00::1", "hostnames": ["baz"]}]`,
+			expected: []v1.HostAlias{
+				{IP: "10.0.0.1", Hostnames: []string{"foo.local", "bar"}},
+				{IP: "fd00::1", Hostnames: []string{"baz"}},
+			},
+		},
+		{value: `{"ip": "10.0.0.1"}`, expectError: true},
+		{value: `[{"ip": "10.0.0", "hostnames": ["foo"]}]`, expectError: true},
+		{value: `[{"ip": "10.0.0.1"}]`, expectError: true},
+		{value: `[{"ip": "10.0.0.1", "hostnames": ["Foo_Bar"]}]`, expectError: true},
+	}
+
+	for _, tc := range testCases {
+		hostAliases, err := parseHostAliases(tc.value)
+		if tc.expectError {
+			if err == nil {
+				t.Errorf("expected error for %q", tc.value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", tc.value, err)
+			continue
+		}
+		if !reflect.DeepEqual(hostAliases, tc.expected) {
+			t.Errorf("expected %v for %q, got %v", tc.expected, tc.value, hostAliases)
+		}
+	}
+}
+
+func TestHostsFileContent(t *testing.T) {
+	content := hostsFileContent("pod", []string{"10.0.0.2", "fd00::2"}, []v1.HostAlias{
+		{IP: "10.0.0.1", Hostnames: []string{"foo.local", "bar"}},
+	})
+
+	expected := `# Kubernetes-managed hosts file (CRI-O).
+127.0.0.1	localhost
+::1	localhost ip6-localhost ip6-loopback
+fe00::0	ip6-localnet
+fe00::0	ip6-mcastprefix
+fe00::1	ip6-allnodes
+fe00::2	ip6-allrouters
+10.0.0.2	pod
+fd00::2	pod
+
+# Entries added by HostAliases.
+10.0.0.1	foo.local	bar
+`
+	if string(content) != expected {
+		t.Errorf("expected hosts file:\n%s\ngot:\n%s", expected, content)
+	}
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cri-o/cri-o/internal/log"
+)
+
+// allocateSandboxMCSLevel allocates the SELinux MCS level of a sandbox from
+// the MCS categories of its runtime handler. It returns an empty level if the
+// levels are allocated by the SELinux library.
+func (s *Server) allocateSandboxMCSLevel(id, runtimeHandler string) (string, error) {
+	allocator := s.MCSAllocator()
+	if allocator == nil {
+		return "", nil
+	}
+	level, err := allocator.Allocate(id, s.config.MCSRangeForHandler(runtimeHandler))
+	if err != nil {
+		return "", fmt.Errorf("allocate SELinux MCS level: %w", err)
+	}
+	return level, nil
+}
+
+// releaseSandboxMCSLevel releases the SELinux MCS level of a sandbox.
+func (s *Server) releaseSandboxMCSLevel(ctx context.Context, id string) {
+	allocator := s.MCSAllocator()
+	if allocator == nil {
+		return
+	}
+	if err := allocator.Release(id); err != nil {
+		log.Warnf(ctx, "Unable to release SELinux MCS level of sandbox %s: %v", id, err)
+	}
+}
+
+// pruneSandboxMCSLevels releases the SELinux MCS levels of sandboxes, which
+// do not exist anymore.
+func (s *Server) pruneSandboxMCSLevels(ctx context.Context) {
+	allocator := s.MCSAllocator()
+	if allocator == nil {
+		return
+	}
+	if err := allocator.Prune(func(id string) bool {
+		return s.getSandbox(ctx, id) != nil
+	}); err != nil {
+		log.Warnf(ctx, "Unable to release SELinux MCS levels of removed sandboxes: %v", err)
+	}
+}
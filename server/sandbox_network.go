@@ -2,20 +2,48 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
+	"net"
+	"os"
+	"strings"
 	"time"
 
+	"github.com/containernetworking/cni/libcni"
 	cnitypes "github.com/containernetworking/cni/pkg/types"
 	cnicurrent "github.com/containernetworking/cni/pkg/types/100"
 	"github.com/cri-o/cri-o/internal/hostport"
 	"github.com/cri-o/cri-o/internal/lib/sandbox"
 	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/pkg/annotations"
 	"github.com/cri-o/cri-o/server/metrics"
 	"github.com/cri-o/ocicni/pkg/ocicni"
 	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// defaultNetworkInterface is the interface of the default network inside
+	// the sandbox
+	defaultNetworkInterface = "eth0"
 
-	utilnet "k8s.io/utils/net"
+	// maxInterfaceNameLength is the maximum length of a network interface
+	// name, which is IFNAMSIZ minus the terminating null byte
+	maxInterfaceNameLength = 15
+
+	// ingressBandwidthAnnotation limits the bandwidth of the traffic
+	// received by the pod
+	ingressBandwidthAnnotation = "kubernetes.io/ingress-bandwidth"
+	// egressBandwidthAnnotation limits the bandwidth of the traffic sent by
+	// the pod
+	egressBandwidthAnnotation = "kubernetes.io/egress-bandwidth"
+	// bandwidthCapability is the CNI capability used to pass the bandwidth
+	// limits to the plugins
+	bandwidthCapability = "bandwidth"
+	// minBandwidth and maxBandwidth are the bandwidth limits accepted by the
+	// kubelet in bits per second
+	minBandwidth = 1000
+	maxBandwidth = 1000 * 1000 * 1000 * 1000 * 1000
 )
 
 // networkStart sets up the sandbox's network and returns the pod IP on success
@@ -71,72 +99,88 @@ func (s *Server) networkStart(ctx context.Context, sb *sandbox.Sandbox) (podIPs
 		return nil, nil, fmt.Errorf("failed to get network status for pod sandbox %s(%s): %w", sb.Name(), sb.ID(), err)
 	}
 
-	// only one cnitypes.Result is returned since newPodNetwork sets Networks list empty
+	// the first cnitypes.Result belongs to the default network, the others
+	// to the additional networks of the networks annotation
 	result = podNetworkStatus[0].Result
 	log.Debugf(ctx, "CNI setup result: %v", result)
 
-	network, err := cnicurrent.GetResult(result)
+	networkResults, err := networkResultsFromStatus(podNetworkStatus)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get network JSON for pod sandbox %s(%s): %w", sb.Name(), sb.ID(), err)
 	}
+	sb.SetNetworkResults(networkResults)
 
-	// only do portmapping to the first IP of each IP family
-	foundIPv4 := false
-	foundIPv6 := false
-	// cache the portmapping info
-	sbID := sb.ID()
-	sbName := sb.Name()
-	sbPortMappings := sb.PortMappings()
-	// iterate over each IP and add the portmap if needed
-	for _, podIPConfig := range network.IPs {
-		ip := podIPConfig.Address.IP
-		podIPs = append(podIPs, ip.String())
-
-		// the pod has host-ports defined
-		if len(sbPortMappings) > 0 {
-			mapping := &hostport.PodPortMapping{
-				Name:         sbName,
-				PortMappings: sbPortMappings,
-				IP:           ip,
-				HostNetwork:  false,
-			}
-			// nolint:gocritic // using a switch statement is not much different
-			if utilnet.IsIPv6(ip) {
-				if foundIPv6 {
-					// we have already done the portmap for IPv6
-					continue
-				}
-				// found a new IPv6 address, do the portmap
-				foundIPv6 = true
-			} else if foundIPv4 {
-				// we have already done the portmap for IPv4
-				continue
-			} else {
-				// found a new IPv4 address, do the portmap
-				foundIPv4 = true
-			}
-			err = s.hostportManager.Add(sbID, mapping, "")
-			if err != nil {
-				return nil, nil, fmt.Errorf("failed to add hostport mapping for sandbox %s(%s): %w", sb.Name(), sb.ID(), err)
-			}
-		}
+	podIPs = sb.NetworkInterfaces()[0].IPs
+	ips := make([]net.IP, 0, len(podIPs))
+	for _, ip := range podIPs {
+		ips = append(ips, net.ParseIP(ip))
 	}
 	log.Debugf(ctx, "Found POD IPs: %v", podIPs)
 
+	if err := s.applyBandwidthFallback(ctx, sb); err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.addHostports(sb, ips); err != nil {
+		return nil, nil, err
+	}
+
 	// metric about the whole network setup operation
 	metrics.Instance().MetricOperationsLatencySet("network_setup_overall", overallStart)
 	return podIPs, result, err
 }
 
-// getSandboxIP retrieves the IP address for the sandbox
-func (s *Server) getSandboxIPs(ctx context.Context, sb *sandbox.Sandbox) ([]string, error) {
+// addHostports adds the hostport mappings of the sandbox for the first IP of
+// each IP family
+func (s *Server) addHostports(sb *sandbox.Sandbox, ips []net.IP) error {
+	s.hostportReconcileLock.RLock()
+	defer s.hostportReconcileLock.RUnlock()
+
+	for _, mapping := range sandboxHostportMappings(sb, ips) {
+		if err := s.hostportManager.Add(sb.ID(), mapping, ""); err != nil {
+			return fmt.Errorf("failed to add hostport mapping for sandbox %s(%s): %w", sb.Name(), sb.ID(), err)
+		}
+	}
+	return nil
+}
+
+// networkResultsFromStatus converts the CNI results of the network
+// attachments into the results stored in the sandbox
+func networkResultsFromStatus(podNetworkStatus []ocicni.NetResult) ([]sandbox.NetworkResult, error) {
+	networkResults := make([]sandbox.NetworkResult, 0, len(podNetworkStatus))
+	for i := range podNetworkStatus {
+		result, err := cnicurrent.GetResult(podNetworkStatus[i].Result)
+		if err != nil {
+			return nil, err
+		}
+		networkResults = append(networkResults, sandbox.NetworkResult{
+			Network:   podNetworkStatus[i].Name,
+			Interface: podNetworkStatus[i].Ifname,
+			Result:    result,
+		})
+	}
+	return networkResults, nil
+}
+
+// getSandboxNetworkResults retrieves the CNI results of the sandbox. The
+// results cached in the sandbox directory are preferred, whereas the CNI
+// plugin is only asked if the cache does not exist.
+func (s *Server) getSandboxNetworkResults(ctx context.Context, sb *sandbox.Sandbox) ([]sandbox.NetworkResult, error) {
 	ctx, span := log.StartSpan(ctx)
 	defer span.End()
 
-	if sb.HostNetwork() {
+	if sb.HostNetwork() || sb.NetworkStopped() {
 		return nil, nil
 	}
 
+	err := sb.RestoreNetworkResults()
+	if err == nil {
+		return sb.NetworkResults(), nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		log.Warnf(ctx, "Failed to restore cached network results of sandbox %s, asking the CNI plugin: %v", sb.ID(), err)
+	}
+
 	podNetwork, err := s.newPodNetwork(ctx, sb)
 	if err != nil {
 		return nil, err
@@ -146,17 +190,11 @@ func (s *Server) getSandboxIPs(ctx context.Context, sb *sandbox.Sandbox) ([]stri
 		return nil, fmt.Errorf("failed to get network status for pod sandbox %s(%s): %w", sb.Name(), sb.ID(), err)
 	}
 
-	res, err := cnicurrent.GetResult(podNetworkStatus[0].Result)
+	networkResults, err := networkResultsFromStatus(podNetworkStatus)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get network JSON for pod sandbox %s(%s): %w", sb.Name(), sb.ID(), err)
 	}
-
-	podIPs := make([]string, 0, len(res.IPs))
-	for _, podIPConfig := range res.IPs {
-		podIPs = append(podIPs, podIPConfig.Address.IP.String())
-	}
-
-	return podIPs, nil
+	return networkResults, nil
 }
 
 // networkStop cleans up and removes a pod's network.  It is best-effort and
@@ -171,6 +209,11 @@ func (s *Server) networkStop(ctx context.Context, sb *sandbox.Sandbox) error {
 	stopCtx, stopCancel := context.WithTimeout(ctx, 1*time.Minute)
 	defer stopCancel()
 
+	// hold the lock until the network is marked as stopped, otherwise the
+	// hostport reconciliation would re-add the removed hostports
+	s.hostportReconcileLock.RLock()
+	defer s.hostportReconcileLock.RUnlock()
+
 	mapping := &hostport.PodPortMapping{
 		Name:         sb.Name(),
 		PortMappings: sb.PortMappings(),
@@ -181,6 +224,7 @@ func (s *Server) networkStop(ctx context.Context, sb *sandbox.Sandbox) error {
 		log.Warnf(ctx, "Failed to remove hostport for pod sandbox %s(%s): %v",
 			sb.Name(), sb.ID(), err)
 	}
+	s.hostportReservations.Release(sb.ID())
 
 	podNetwork, err := s.newPodNetwork(ctx, sb)
 	if err != nil {
@@ -193,48 +237,157 @@ func (s *Server) networkStop(ctx context.Context, sb *sandbox.Sandbox) error {
 	return sb.SetNetworkStopped(ctx, true)
 }
 
-func (s *Server) newPodNetwork(ctx context.Context, sb *sandbox.Sandbox) (ocicni.PodNetwork, error) {
-	_, span := log.StartSpan(ctx)
-	defer span.End()
-
-	var egress, ingress int64
-	if val, ok := sb.Annotations()["kubernetes.io/egress-bandwidth"]; ok {
+// podBandwidth returns the ingress and egress bandwidth limits of the pod
+// annotations in bits per second.
+func podBandwidth(podAnnotations map[string]string) (ingress, egress int64, err error) {
+	if val, ok := podAnnotations[egressBandwidthAnnotation]; ok {
 		egressQ, err := resource.ParseQuantity(val)
 		if err != nil {
-			return ocicni.PodNetwork{}, fmt.Errorf("failed to parse egress bandwidth: %w", err)
+			return 0, 0, fmt.Errorf("failed to parse egress bandwidth: %w", err)
 		} else if iegress, isok := egressQ.AsInt64(); isok {
 			egress = iegress
 		}
 	}
-	if val, ok := sb.Annotations()["kubernetes.io/ingress-bandwidth"]; ok {
+	if val, ok := podAnnotations[ingressBandwidthAnnotation]; ok {
 		ingressQ, err := resource.ParseQuantity(val)
 		if err != nil {
-			return ocicni.PodNetwork{}, fmt.Errorf("failed to parse ingress bandwidth: %w", err)
+			return 0, 0, fmt.Errorf("failed to parse ingress bandwidth: %w", err)
 		} else if iingress, isok := ingressQ.AsInt64(); isok {
 			ingress = iingress
 		}
 	}
+	return ingress, egress, nil
+}
 
-	var bwConfig *ocicni.BandwidthConfig
-
-	if ingress > 0 || egress > 0 {
-		bwConfig = &ocicni.BandwidthConfig{}
-		if ingress > 0 {
-			bwConfig.IngressRate = uint64(ingress)
-			bwConfig.IngressBurst = math.MaxUint32*8 - 1 // 4GB burst limit
+// validatePodBandwidth validates the bandwidth annotations of a new pod. It is
+// only used when running a pod sandbox, the network of existing pods has to be
+// torn down and restored regardless of their limits.
+func validatePodBandwidth(podAnnotations map[string]string) error {
+	ingress, egress, err := podBandwidth(podAnnotations)
+	if err != nil {
+		return err
+	}
+	if _, ok := podAnnotations[egressBandwidthAnnotation]; ok {
+		if err := validateBandwidth(egress); err != nil {
+			return fmt.Errorf("invalid egress bandwidth: %w", err)
 		}
-		if egress > 0 {
-			bwConfig.EgressRate = uint64(egress)
-			bwConfig.EgressBurst = math.MaxUint32*8 - 1 // 4GB burst limit
+	}
+	if _, ok := podAnnotations[ingressBandwidthAnnotation]; ok {
+		if err := validateBandwidth(ingress); err != nil {
+			return fmt.Errorf("invalid ingress bandwidth: %w", err)
 		}
 	}
+	return nil
+}
+
+// podBandwidthConfig returns the bandwidth limits of the pod annotations in
+// bits per second, or nil if the pod is not limited. The limits are passed to
+// CNI as the standard bandwidth capability.
+func podBandwidthConfig(podAnnotations map[string]string) (*ocicni.BandwidthConfig, error) {
+	ingress, egress, err := podBandwidth(podAnnotations)
+	if err != nil {
+		return nil, err
+	}
+
+	if ingress <= 0 && egress <= 0 {
+		return nil, nil
+	}
+	bwConfig := &ocicni.BandwidthConfig{}
+	if ingress > 0 {
+		bwConfig.IngressRate = uint64(ingress)
+		bwConfig.IngressBurst = math.MaxUint32*8 - 1 // 4GB burst limit
+	}
+	if egress > 0 {
+		bwConfig.EgressRate = uint64(egress)
+		bwConfig.EgressBurst = math.MaxUint32*8 - 1 // 4GB burst limit
+	}
+	return bwConfig, nil
+}
+
+// validateBandwidth validates a bandwidth limit in bits per second the same
+// way as the kubelet does.
+func validateBandwidth(bandwidth int64) error {
+	if bandwidth < minBandwidth {
+		return fmt.Errorf("%d is below the minimum of %d", bandwidth, minBandwidth)
+	}
+	if bandwidth > maxBandwidth {
+		return fmt.Errorf("%d exceeds the maximum of %d", bandwidth, maxBandwidth)
+	}
+	return nil
+}
+
+// applyBandwidthFallback limits the bandwidth of the sandbox with traffic
+// control rules on its host side veth, if enabled and the default CNI network
+// does not handle the bandwidth capability.
+func (s *Server) applyBandwidthFallback(ctx context.Context, sb *sandbox.Sandbox) error {
+	if !s.config.EnableBandwidthFallback {
+		return nil
+	}
+	bwConfig, err := podBandwidthConfig(sb.Annotations())
+	if err != nil || bwConfig == nil {
+		return err
+	}
 
 	network := s.config.CNIPlugin().GetDefaultNetworkName()
+	if networkHandlesBandwidth(ctx, s.config.NetworkDir, network) {
+		return nil
+	}
+
+	log.Infof(ctx, "CNI network %s does not handle the bandwidth capability, applying traffic control rules for sandbox %s", network, sb.ID())
+	if err := applyBandwidthLimits(sb.NetNsPath(), defaultNetworkInterface, bwConfig.IngressRate, bwConfig.EgressRate); err != nil {
+		return fmt.Errorf("failed to apply bandwidth limits for sandbox %s(%s): %w", sb.Name(), sb.ID(), err)
+	}
+	return nil
+}
+
+// networkHandlesBandwidth returns true if a plugin of the CNI network has the
+// bandwidth capability enabled.
+func networkHandlesBandwidth(ctx context.Context, networkDir, network string) bool {
+	confList, err := libcni.LoadConfList(networkDir, network)
+	if err != nil {
+		log.Debugf(ctx, "Unable to load CNI network %s: %v", network, err)
+		return false
+	}
+	for _, plugin := range confList.Plugins {
+		if plugin.Network.Capabilities[bandwidthCapability] {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) newPodNetwork(ctx context.Context, sb *sandbox.Sandbox) (ocicni.PodNetwork, error) {
+	_, span := log.StartSpan(ctx)
+	defer span.End()
+
+	bwConfig, err := podBandwidthConfig(sb.Annotations())
+	if err != nil {
+		return ocicni.PodNetwork{}, err
+	}
+
+	network := s.config.CNIPlugin().GetDefaultNetworkName()
+	networks := []ocicni.NetAttachment{}
+	if val, ok := sb.Annotations()[annotations.NetworksAnnotation]; ok {
+		additionalNetworks, err := parseNetworksAnnotation(val, network)
+		if err != nil {
+			return ocicni.PodNetwork{}, fmt.Errorf("failed to parse %s annotation: %w", annotations.NetworksAnnotation, err)
+		}
+		if len(additionalNetworks) > 0 {
+			// the default network has to be specified explicitly as soon
+			// as additional networks get attached
+			networks = append(networks, ocicni.NetAttachment{
+				Name:   network,
+				Ifname: defaultNetworkInterface,
+			})
+			networks = append(networks, additionalNetworks...)
+		}
+	}
+
 	return ocicni.PodNetwork{
 		Name:      sb.KubeName(),
 		Namespace: sb.Namespace(),
 		UID:       sb.Metadata().Uid,
-		Networks:  []ocicni.NetAttachment{},
+		Networks:  networks,
 		ID:        sb.ID(),
 		NetNS:     sb.NetNsPath(),
 		RuntimeConfig: map[string]ocicni.RuntimeConfig{
@@ -242,3 +395,54 @@ func (s *Server) newPodNetwork(ctx context.Context, sb *sandbox.Sandbox) (ocicni
 		},
 	}, nil
 }
+
+// parseNetworksAnnotation parses the networks annotation, which is a comma
+// separated list of additional CNI networks in the form `NETWORK[@INTERFACE]`.
+// Networks without interface name get the interface `netN` assigned, whereas
+// N is the position of the network in the list, starting at 1.
+func parseNetworksAnnotation(value, defaultNetwork string) ([]ocicni.NetAttachment, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	networks := []ocicni.NetAttachment{}
+	seenNetworks := map[string]bool{defaultNetwork: true}
+	seenInterfaces := map[string]bool{defaultNetworkInterface: true, "lo": true}
+	for i, entry := range strings.Split(value, ",") {
+		name, ifname, hasIfname := strings.Cut(strings.TrimSpace(entry), "@")
+		if name == "" {
+			return nil, fmt.Errorf("entry %d: empty network name", i+1)
+		}
+		if !hasIfname {
+			ifname = fmt.Sprintf("net%d", i+1)
+		}
+		if err := validateInterfaceName(ifname); err != nil {
+			return nil, fmt.Errorf("entry %d: %w", i+1, err)
+		}
+		if seenNetworks[name] {
+			return nil, fmt.Errorf("entry %d: network %q is already attached", i+1, name)
+		}
+		if seenInterfaces[ifname] {
+			return nil, fmt.Errorf("entry %d: interface %q is already used", i+1, ifname)
+		}
+		seenNetworks[name] = true
+		seenInterfaces[ifname] = true
+		networks = append(networks, ocicni.NetAttachment{Name: name, Ifname: ifname})
+	}
+	return networks, nil
+}
+
+// validateInterfaceName validates a network interface name the same way as
+// the kernel does.
+func validateInterfaceName(name string) error {
+	if name == "" || name == "." || name == ".." {
+		return fmt.Errorf("invalid interface name %q", name)
+	}
+	if len(name) > maxInterfaceNameLength {
+		return fmt.Errorf("interface name %q exceeds %d characters", name, maxInterfaceNameLength)
+	}
+	if strings.ContainsAny(name, "/: \t\n") {
+		return fmt.Errorf("interface name %q contains invalid characters", name)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// bandwidthLatency is the maximum time a packet may wait in the token
+	// bucket filter limiting the ingress traffic of the pod
+	bandwidthLatency = 25 * time.Millisecond
+	// bandwidthBurstDuration is the duration of traffic at the limited rate
+	// which is allowed to pass as a single burst
+	bandwidthBurstDuration = 100 * time.Millisecond
+	// minBandwidthBurst is the minimum burst in bytes, which has to be at
+	// least as large as a GSO packet unless the rate is even lower
+	minBandwidthBurst = 64 * 1024
+	// bandwidthPoliceMTU is the maximum packet size in bytes accepted by the
+	// police action limiting the egress traffic of the pod
+	bandwidthPoliceMTU = math.MaxUint16
+)
+
+// applyBandwidthLimits limits the bandwidth of the pod by adding traffic
+// control rules to the host side of the veth pair whose sandbox side is the
+// interface ifname in the network namespace. The rates are in bits per second
+// and zero means unlimited. Traffic sent to the pod is shaped by a token bucket
+// filter, traffic sent by the pod is policed on ingress of the host side veth.
+func applyBandwidthLimits(netNsPath, ifname string, ingressRate, egressRate uint64) error {
+	hostIndex, err := hostVethIndex(netNsPath, ifname)
+	if err != nil {
+		return err
+	}
+	link, err := netlink.LinkByIndex(hostIndex)
+	if err != nil {
+		return fmt.Errorf("find host side veth of %s: %w", ifname, err)
+	}
+	if link.Type() != "veth" {
+		return fmt.Errorf("host side interface %s of %s is not a veth", link.Attrs().Name, ifname)
+	}
+
+	if ingressRate > 0 {
+		if err := addBandwidthShaping(link.Attrs().Index, ingressRate/8); err != nil {
+			return fmt.Errorf("limit ingress bandwidth on %s: %w", link.Attrs().Name, err)
+		}
+	}
+	if egressRate > 0 {
+		if err := addBandwidthPolicing(link.Attrs().Index, egressRate/8); err != nil {
+			return fmt.Errorf("limit egress bandwidth on %s: %w", link.Attrs().Name, err)
+		}
+	}
+	return nil
+}
+
+// hostVethIndex returns the interface index of the host side of the veth
+// pair whose sandbox side is the interface ifname in the network namespace.
+func hostVethIndex(netNsPath, ifname string) (int, error) {
+	index := 0
+	if err := ns.WithNetNSPath(netNsPath, func(ns.NetNS) error {
+		link, err := netlink.LinkByName(ifname)
+		if err != nil {
+			return err
+		}
+		if _, ok := link.(*netlink.Veth); !ok {
+			return fmt.Errorf("interface %s is not a veth", ifname)
+		}
+		index = link.Attrs().ParentIndex
+		return nil
+	}); err != nil {
+		return 0, fmt.Errorf("find interface %s in network namespace %s: %w", ifname, netNsPath, err)
+	}
+	return index, nil
+}
+
+// bandwidthBurst returns the burst in bytes for the rate in bytes per second.
+func bandwidthBurst(rate uint64) uint64 {
+	burst := uint64(float64(rate) * bandwidthBurstDuration.Seconds())
+	minBurst := uint64(minBandwidthBurst)
+	if rate < minBurst {
+		// keep the buffer time of the token bucket filter below one second
+		minBurst = rate
+	}
+	if burst < minBurst {
+		return minBurst
+	}
+	return burst
+}
+
+// addBandwidthShaping replaces the root qdisc of the link by a token bucket
+// filter for the rate in bytes per second.
+func addBandwidthShaping(linkIndex int, rate uint64) error {
+	burst := bandwidthBurst(rate)
+	buffer := uint32(float64(burst) * netlink.TIME_UNITS_PER_SEC / float64(rate) * netlink.TickInUsec())
+	limit := uint32(float64(rate)*bandwidthLatency.Seconds()) + uint32(burst)
+
+	return netlink.QdiscReplace(&netlink.Tbf{
+		QdiscAttrs: netlink.QdiscAttrs{
+			LinkIndex: linkIndex,
+			Handle:    netlink.MakeHandle(1, 0),
+			Parent:    netlink.HANDLE_ROOT,
+		},
+		Rate:   rate,
+		Limit:  limit,
+		Buffer: buffer,
+	})
+}
+
+// addBandwidthPolicing drops the packets received on the link which exceed
+// the rate in bytes per second.
+func addBandwidthPolicing(linkIndex int, rate uint64) error {
+	if err := netlink.QdiscReplace(&netlink.Ingress{
+		QdiscAttrs: netlink.QdiscAttrs{
+			LinkIndex: linkIndex,
+			Handle:    netlink.MakeHandle(0xffff, 0),
+			Parent:    netlink.HANDLE_INGRESS,
+		},
+	}); err != nil {
+		return err
+	}
+
+	// the police action only supports 32 bit rates and bursts
+	if rate > math.MaxUint32 {
+		rate = math.MaxUint32
+	}
+	burst := bandwidthBurst(rate)
+	if burst > math.MaxUint32 {
+		burst = math.MaxUint32
+	}
+
+	police := netlink.NewPoliceAction()
+	police.Rate = uint32(rate)
+	police.Burst = uint32(burst)
+	police.Mtu = bandwidthPoliceMTU
+	police.ExceedAction = netlink.TC_POLICE_SHOT
+
+	return netlink.FilterReplace(&netlink.MatchAll{
+		FilterAttrs: netlink.FilterAttrs{
+			LinkIndex: linkIndex,
+			Parent:    netlink.MakeHandle(0xffff, 0),
+			Priority:  1,
+			Protocol:  unix.ETH_P_ALL,
+		},
+		Actions: []netlink.Action{police},
+	})
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+// +build !linux
+
+package server
+
+import "errors"
+
+func applyBandwidthLimits(netNsPath, ifname string, ingressRate, egressRate uint64) error {
+	return errors.New("unsupported")
+}
@@ -0,0 +1,168 @@
+package server
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/cri-o/ocicni/pkg/ocicni"
+)
+
+func TestParseNetworksAnnotation(t *testing.T) {
+	testCases := []struct {
+		value       string
+		expected    []ocicni.NetAttachment
+		expectError bool
+	}{
+		{value: "", expected: nil},
+		{
+			value: "macvlan-conf",
+			expected: []ocicni.NetAttachment{
+				{Name: "macvlan-conf", Ifname: "net1"},
+			},
+		},
+		{
+			value: "macvlan-conf@data0, sriov-conf",
+			expected: []ocicni.NetAttachment{
+				{Name: "macvlan-conf", Ifname: "data0"},
+				{Name: "sriov-conf", Ifname: "net2"},
+			},
+		},
+		{value: "default", expectError: true},
+		{value: "macvlan-conf@eth0", expectError: true},
+		{value: "macvlan-conf,macvlan-conf@data0", expectError: true},
+		{value: "macvlan-conf@data0,sriov-conf@data0", expectError: true},
+		{value: "macvlan-conf@averyveryverylongname", expectError: true},
+		{value: "macvlan-conf@da/ta", expectError: true},
+		{value: "macvlan-conf@", expectError: true},
+		{value: "@data0", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		networks, err := parseNetworksAnnotation(tc.value, "default")
+		if tc.expectError {
+			if err == nil {
+				t.Fatalf("expected an error for %q but got nothing", tc.value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", tc.value, err)
+		}
+		if !reflect.DeepEqual(networks, tc.expected) {
+			t.Fatalf("expected %v for %q, got %v", tc.expected, tc.value, networks)
+		}
+	}
+}
+
+func TestValidatePodBandwidth(t *testing.T) {
+	testCases := []struct {
+		annotations map[string]string
+		expectError bool
+	}{
+		{annotations: map[string]string{}},
+		{annotations: map[string]string{ingressBandwidthAnnotation: "1k", egressBandwidthAnnotation: "1P"}},
+		{annotations: map[string]string{egressBandwidthAnnotation: "wrong"}, expectError: true},
+		{annotations: map[string]string{egressBandwidthAnnotation: "100"}, expectError: true},
+		{annotations: map[string]string{ingressBandwidthAnnotation: "2P"}, expectError: true},
+		{annotations: map[string]string{ingressBandwidthAnnotation: "0"}, expectError: true},
+	}
+
+	for _, tc := range testCases {
+		err := validatePodBandwidth(tc.annotations)
+		if tc.expectError && err == nil {
+			t.Fatalf("expected an error for %v but got nothing", tc.annotations)
+		}
+		if !tc.expectError && err != nil {
+			t.Fatalf("unexpected error for %v: %v", tc.annotations, err)
+		}
+	}
+}
+
+func TestPodBandwidthConfig(t *testing.T) {
+	testCases := []struct {
+		annotations map[string]string
+		expected    *ocicni.BandwidthConfig
+		expectError bool
+	}{
+		{annotations: map[string]string{}, expected: nil},
+		{
+			annotations: map[string]string{
+				ingressBandwidthAnnotation: "10M",
+			},
+			expected: &ocicni.BandwidthConfig{
+				IngressRate:  10000000,
+				IngressBurst: math.MaxUint32*8 - 1,
+			},
+		},
+		{
+			annotations: map[string]string{
+				ingressBandwidthAnnotation: "1k",
+				egressBandwidthAnnotation:  "1G",
+			},
+			expected: &ocicni.BandwidthConfig{
+				IngressRate:  1000,
+				IngressBurst: math.MaxUint32*8 - 1,
+				EgressRate:   1000000000,
+				EgressBurst:  math.MaxUint32*8 - 1,
+			},
+		},
+		{
+			// out of range limits are only rejected for new pods
+			annotations: map[string]string{egressBandwidthAnnotation: "100"},
+			expected: &ocicni.BandwidthConfig{
+				EgressRate:  100,
+				EgressBurst: math.MaxUint32*8 - 1,
+			},
+		},
+		{annotations: map[string]string{egressBandwidthAnnotation: "wrong"}, expectError: true},
+	}
+
+	for _, tc := range testCases {
+		bwConfig, err := podBandwidthConfig(tc.annotations)
+		if tc.expectError {
+			if err == nil {
+				t.Fatalf("expected an error for %v but got nothing", tc.annotations)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unexpected error for %v: %v", tc.annotations, err)
+		}
+		if !reflect.DeepEqual(bwConfig, tc.expected) {
+			t.Fatalf("expected %+v for %v, got %+v", tc.expected, tc.annotations, bwConfig)
+		}
+	}
+}
+
+func TestNetworkHandlesBandwidth(t *testing.T) {
+	dir := t.TempDir()
+	for name, conf := range map[string]string{
+		"10-shaped.conflist": `{"cniVersion": "0.4.0", "name": "shaped", "plugins": [
+			{"type": "bridge"},
+			{"type": "bandwidth", "capabilities": {"bandwidth": true}}
+		]}`,
+		"20-unshaped.conflist": `{"cniVersion": "0.4.0", "name": "unshaped", "plugins": [
+			{"type": "bridge"},
+			{"type": "portmap", "capabilities": {"portMappings": true}}
+		]}`,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(conf), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx := context.TODO()
+	if !networkHandlesBandwidth(ctx, dir, "shaped") {
+		t.Fatal("expected network shaped to handle the bandwidth capability")
+	}
+	if networkHandlesBandwidth(ctx, dir, "unshaped") {
+		t.Fatal("expected network unshaped to not handle the bandwidth capability")
+	}
+	if networkHandlesBandwidth(ctx, dir, "missing") {
+		t.Fatal("expected missing network to not handle the bandwidth capability")
+	}
+}
@@ -74,6 +74,8 @@ func (s *Server) removePodSandbox(ctx context.Context, sb *sandbox.Sandbox) erro
 	if err := s.PodIDIndex().Delete(sb.ID()); err != nil {
 		return fmt.Errorf("failed to delete pod sandbox %s from index: %w", sb.ID(), err)
 	}
+	s.releaseSandboxUserns(ctx, sb.ID())
+	s.releaseSandboxMCSLevel(ctx, sb.ID())
 	s.generateCRIEvent(ctx, sb.InfraContainer(), types.ContainerEventType_CONTAINER_DELETED_EVENT)
 
 	if err := s.nri.removePodSandbox(ctx, sb); err != nil {
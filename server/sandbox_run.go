@@ -1,11 +1,16 @@
 package server
 
 import (
+	"errors"
+	"fmt"
 	"os"
 
 	"github.com/cri-o/cri-o/internal/hostport"
 	"github.com/cri-o/cri-o/internal/log"
 	"golang.org/x/net/context"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	v1 "k8s.io/api/core/v1"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
@@ -18,6 +23,13 @@ const (
 	PodInfraOOMAdj int = -998
 	// PodInfraCPUshares is default cpu shares for sandbox container.
 	PodInfraCPUshares = 2
+
+	// hostportConflictReason is the reason of the error info attached to
+	// errors about conflicting host ports.
+	hostportConflictReason = "HOSTPORT_CONFLICT"
+	// hostportConflictDomain is the domain of the error info attached to
+	// errors about conflicting host ports.
+	hostportConflictDomain = "cri-o.io"
 )
 
 // privilegedSandbox returns true if the sandbox configuration
@@ -85,6 +97,34 @@ func convertPortMappings(in []*types.PortMapping) []*hostport.PortMapping {
 	return out
 }
 
+// reserveHostports reserves the host ports of the sandbox. A gRPC status
+// error with code AlreadyExists is returned if a host port is already
+// reserved by another pod, whereas the error details contain the owning pod.
+func (s *Server) reserveHostports(id, name string, portMappings []*hostport.PortMapping) error {
+	err := s.hostportReservations.Reserve(id, name, portMappings)
+	var conflictErr *hostport.ConflictError
+	if !errors.As(err, &conflictErr) {
+		return err
+	}
+
+	st := status.New(codes.AlreadyExists, conflictErr.Error())
+	detailed, detailsErr := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: hostportConflictReason,
+		Domain: hostportConflictDomain,
+		Metadata: map[string]string{
+			"hostPort":     fmt.Sprint(conflictErr.Requested.HostPort),
+			"protocol":     string(conflictErr.Requested.Protocol),
+			"hostIP":       conflictErr.Requested.HostIP,
+			"ownerPodID":   conflictErr.Owner.PodID,
+			"ownerPodName": conflictErr.Owner.PodName,
+		},
+	})
+	if detailsErr != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
 func getHostname(id, hostname string, hostNetwork bool) (string, error) {
 	if hostNetwork {
 		if hostname == "" {
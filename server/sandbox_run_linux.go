@@ -27,6 +27,7 @@ import (
 	"github.com/cri-o/cri-o/internal/resourcestore"
 	ann "github.com/cri-o/cri-o/pkg/annotations"
 	libconfig "github.com/cri-o/cri-o/pkg/config"
+	"github.com/cri-o/cri-o/server/metrics"
 	"github.com/cri-o/cri-o/utils"
 	json "github.com/json-iterator/go"
 	spec "github.com/opencontainers/runtime-spec/specs-go"
@@ -42,6 +43,16 @@ import (
 // DefaultUserNSSize is the default size for the user namespace created
 const DefaultUserNSSize = 65536
 
+const (
+	// namespacePoolHit is the result of a namespace pool request which got
+	// served from the pool.
+	namespacePoolHit = "hit"
+
+	// namespacePoolMiss is the result of a namespace pool request for which
+	// the namespaces had to be created.
+	namespacePoolMiss = "miss"
+)
+
 // addToMappingsIfMissing ensures the specified id is mapped from the host.
 func addToMappingsIfMissing(ids []idtools.IDMap, id int64) []idtools.IDMap {
 	firstAvailable := int(0)
@@ -62,12 +73,22 @@ func addToMappingsIfMissing(ids []idtools.IDMap, id int64) []idtools.IDMap {
 	return append(ids, newMapping)
 }
 
-func (s *Server) configureSandboxIDMappings(mode string, sc *types.LinuxSandboxSecurityContext) (*storage.IDMappingOptions, error) {
+func (s *Server) configureSandboxIDMappings(sandboxID, mode string, sc *types.LinuxSandboxSecurityContext) (*storage.IDMappingOptions, error) {
 	if sc.NamespaceOptions.UsernsOptions != nil {
 		switch sc.NamespaceOptions.UsernsOptions.Mode {
 		case types.NamespaceMode_NODE:
 			return nil, nil
 		case types.NamespaceMode_POD:
+			if len(sc.NamespaceOptions.UsernsOptions.Uids) == 0 && len(sc.NamespaceOptions.UsernsOptions.Gids) == 0 {
+				allocation, err := s.allocateSandboxUserns(sandboxID)
+				if err != nil {
+					return nil, err
+				}
+				return &storage.IDMappingOptions{
+					UIDMap: allocation.UIDMap(),
+					GIDMap: allocation.GIDMap(),
+				}, nil
+			}
 			return &storage.IDMappingOptions{
 				UIDMap: convertToStorageIDMap(sc.NamespaceOptions.UsernsOptions.Uids),
 				GIDMap: convertToStorageIDMap(sc.NamespaceOptions.UsernsOptions.Gids),
@@ -397,6 +418,17 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 	}
 	hostNetwork := securityContext.NamespaceOptions.Network == types.NamespaceMode_NODE
 
+	if !hostNetwork {
+		// reject conflicting host ports before doing any expensive work
+		if err := s.reserveHostports(sbox.ID(), sbox.Name(), convertPortMappings(sbox.Config().PortMappings)); err != nil {
+			return nil, err
+		}
+		resourceCleaner.Add(ctx, "runSandbox: releasing host ports of pod sandbox "+sbox.ID(), func() error {
+			s.hostportReservations.Release(sbox.ID())
+			return nil
+		})
+	}
+
 	if err := s.config.CNIPluginReadyOrError(); err != nil && !hostNetwork {
 		// if the cni plugin isn't ready yet, we should wait until it is
 		// before proceeding
@@ -421,12 +453,20 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 
 	kubeAnnotations := sbox.Config().Annotations
 
+	if err := validatePodBandwidth(kubeAnnotations); err != nil {
+		return nil, err
+	}
+
 	usernsMode := kubeAnnotations[ann.UsernsModeAnnotation]
 
-	idMappingsOptions, err := s.configureSandboxIDMappings(usernsMode, sbox.Config().Linux.SecurityContext)
+	idMappingsOptions, err := s.configureSandboxIDMappings(sbox.ID(), usernsMode, sbox.Config().Linux.SecurityContext)
 	if err != nil {
 		return nil, err
 	}
+	resourceCleaner.Add(ctx, "runSandbox: releasing user namespace ID range of pod sandbox "+sbox.ID(), func() error {
+		s.releaseSandboxUserns(ctx, sbox.ID())
+		return nil
+	})
 
 	containerName, err := s.ReserveSandboxContainerIDAndName(sbox.Config())
 	if err != nil {
@@ -442,6 +482,24 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 	if selinuxConfig != nil {
 		labelOptions = utils.GetLabelOptions(selinuxConfig)
 	}
+	// pods in the host PID or IPC namespace do not use SELinux separation
+	allocatedLevel := false
+	if (selinuxConfig == nil || selinuxConfig.Level == "") &&
+		securityContext.NamespaceOptions.Pid != types.NamespaceMode_NODE &&
+		securityContext.NamespaceOptions.Ipc != types.NamespaceMode_NODE {
+		level, err := s.allocateSandboxMCSLevel(sbox.ID(), runtimeHandler)
+		if err != nil {
+			return nil, err
+		}
+		if level != "" {
+			labelOptions = append(labelOptions, "level:"+level)
+			allocatedLevel = true
+		}
+	}
+	resourceCleaner.Add(ctx, "runSandbox: releasing SELinux MCS level of pod sandbox "+sbox.ID(), func() error {
+		s.releaseSandboxMCSLevel(ctx, sbox.ID())
+		return nil
+	})
 
 	privileged := s.privilegedSandbox(req)
 
@@ -472,6 +530,12 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 
 	mountLabel := podContainer.MountLabel
 	processLabel := podContainer.ProcessLabel
+	// levels which have not been allocated, like requested ones or the ones
+	// picked by the SELinux library for host PID or IPC pods, are reserved to
+	// not hand them out to other pods
+	if !allocatedLevel {
+		s.ReserveMCSLevel(ctx, sbox.ID(), processLabel)
+	}
 
 	// set log directory
 	logDir := sbox.Config().LogDirectory
@@ -495,6 +559,10 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 	if err := sbox.InitInfraContainer(&s.config, &podContainer); err != nil {
 		return nil, err
 	}
+	for _, warning := range sbox.DNSWarnings() {
+		s.recordSandboxWarning(ctx, sbox.ID(), eventReasonDNSConfigAdjusted,
+			fmt.Sprintf("DNS config of pod sandbox %s: %s", sbox.Name(), warning))
+	}
 	pathsToChown = append(pathsToChown, sbox.ResolvPath())
 
 	// add metadata
@@ -680,6 +748,12 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 	}
 
 	sb.SetDNSConfig(sbox.Config().DnsConfig)
+	sb.SetDNSWarnings(sbox.DNSWarnings())
+
+	hostAliases, err := parseHostAliases(sb.Annotations()[ann.HostAliasesAnnotation])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s annotation: %w", ann.HostAliasesAnnotation, err)
+	}
 
 	if err := s.addSandbox(ctx, sb); err != nil {
 		return nil, err
@@ -709,7 +783,7 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 	}
 
 	// Add default sysctls given in crio.conf
-	sysctls := s.configureGeneratorForSysctls(ctx, g, hostNetwork, hostIPC, req.Config.Linux.Sysctls)
+	sysctls := s.configureGeneratorForSysctls(ctx, g, runtimeHandler, hostNetwork, hostIPC, req.Config.Linux.Sysctls)
 
 	// set up namespaces
 	s.resourceStore.SetStageForResource(ctx, sbox.Name(), "sandbox namespace creation")
@@ -809,6 +883,20 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 	g.AddAnnotation(annotations.HostnamePath, hostnamePath)
 	sb.AddHostnamePath(hostnamePath)
 
+	// pods in the host network use the hosts file of the host
+	if !hostNetwork {
+		hostsPath := filepath.Join(podContainer.RunDir, "hosts")
+		if err := os.WriteFile(hostsPath, hostsFileContent(hostname, ips, hostAliases), 0o644); err != nil {
+			return nil, err
+		}
+		if err := label.Relabel(hostsPath, mountLabel, false); err != nil && !errors.Is(err, unix.ENOTSUP) {
+			return nil, err
+		}
+		pathsToChown = append(pathsToChown, hostsPath)
+		g.AddAnnotation(ann.HostsPathAnnotation, hostsPath)
+		sb.AddHostsPath(hostsPath)
+	}
+
 	if sandboxIDMappings != nil {
 		if securityContext.NamespaceOptions.Ipc == types.NamespaceMode_NODE {
 			g.RemoveMount("/dev/mqueue")
@@ -860,7 +948,7 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 	g.AddAnnotation(annotations.SeccompProfilePath, seccompProfilePath)
 	sb.SetSeccompProfilePath(seccompProfilePath)
 	if !privileged {
-		if _, err := s.config.Seccomp().Setup(
+		if _, err := s.config.SeccompForHandler(runtimeHandler).Setup(
 			ctx,
 			nil,
 			"",
@@ -922,6 +1010,10 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 		return nil, err
 	}
 
+	if err := s.Runtime().ValidateSpecForRuntimeHandler(runtimeHandler, g.Config); err != nil {
+		return nil, err
+	}
+
 	if err = g.SaveToFile(filepath.Join(podContainer.Dir, "config.json"), saveOptions); err != nil {
 		return nil, fmt.Errorf("failed to save template configuration for pod sandbox %s(%s): %w", sb.Name(), sbox.ID(), err)
 	}
@@ -958,7 +1050,7 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 	}
 	resourceCleaner.Add(ctx, "runSandbox: stopping container "+container.ID(), func() error {
 		// Clean-up steps from RemovePodSanbox
-		if err := s.stopContainer(ctx, container, int64(10)); err != nil {
+		if err := s.stopContainer(ctx, container, s.internalStopTimeout(runtimeHandler)); err != nil {
 			return fmt.Errorf("failed to stop container for removal")
 		}
 
@@ -977,6 +1069,12 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 		log.Warnf(ctx, "Unable to write containers %s state to disk: %v", container.ID(), err)
 	}
 
+	if !hostNetwork {
+		if err := sb.SaveNetworkResults(ctx); err != nil {
+			log.Warnf(ctx, "Unable to cache network results of sandbox %s: %v", sb.ID(), err)
+		}
+	}
+
 	for idx, ip := range ips {
 		g.AddAnnotation(fmt.Sprintf("%s.%d", annotations.IP, idx), ip)
 	}
@@ -1024,11 +1122,11 @@ func setupShm(ctx context.Context, podSandboxRunDir, mountLabel string, shmSize
 	return shmPath, nil
 }
 
-func (s *Server) configureGeneratorForSysctls(ctx context.Context, g *generate.Generator, hostNetwork, hostIPC bool, sysctls map[string]string) map[string]string {
+func (s *Server) configureGeneratorForSysctls(ctx context.Context, g *generate.Generator, runtimeHandler string, hostNetwork, hostIPC bool, sysctls map[string]string) map[string]string {
 	ctx, span := log.StartSpan(ctx)
 	defer span.End()
 	sysctlsToReturn := make(map[string]string)
-	defaultSysctls, err := s.config.RuntimeConfig.Sysctls()
+	defaultSysctls, err := s.config.SysctlsForHandler(runtimeHandler)
 	if err != nil {
 		log.Warnf(ctx, "Sysctls invalid: %v", err)
 	}
@@ -1093,9 +1191,25 @@ func (s *Server) configureGeneratorForSandboxNamespaces(ctx context.Context, hos
 	}
 
 	// now that we've configured the namespaces we're sharing, create them
-	namespaces, err := s.config.NamespaceManager().NewPodNamespaces(namespaceConfig)
-	if err != nil {
-		return nil, err
+	// or take them from the pool, which only has unmodified namespaces
+	var (
+		namespaces []nsmgr.Namespace
+		pooled     bool
+	)
+	if s.config.NamespacePoolSize > 0 && !hostNetwork && !hostIPC && idMappings == nil && len(sysctls) == 0 {
+		namespaces, pooled = s.config.NamespaceManager().TakePooledNamespaces()
+		result := namespacePoolMiss
+		if pooled {
+			result = namespacePoolHit
+		}
+		metrics.Instance().MetricNamespacePoolRequestsInc(result)
+	}
+	if !pooled {
+		var err error
+		namespaces, err = s.config.NamespaceManager().NewPodNamespaces(namespaceConfig)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	sb.AddManagedNamespaces(namespaces)
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
 	"github.com/cri-o/cri-o/internal/log"
 	"github.com/cri-o/cri-o/internal/oci"
 	json "github.com/json-iterator/go"
@@ -71,7 +72,7 @@ func (s *Server) PodSandboxStatus(ctx context.Context, req *types.PodSandboxStat
 	}
 
 	if req.Verbose {
-		info, err := createSandboxInfo(sb.InfraContainer())
+		info, err := createSandboxInfo(sb.InfraContainer(), sb.NetworkInterfaces(), sb.DNSWarnings())
 		if err != nil {
 			return nil, fmt.Errorf("creating sandbox info: %w", err)
 		}
@@ -88,23 +89,31 @@ func toPodIPs(ips []string) (result []*types.PodIP) {
 	return result
 }
 
-func createSandboxInfo(c *oci.Container) (map[string]string, error) {
+func createSandboxInfo(c *oci.Container, networkInterfaces []sandbox.NetworkInterface, dnsWarnings []string) (map[string]string, error) {
 	var info interface{}
 	if c.Spoofed() {
 		info = struct {
-			RuntimeSpec spec.Spec `json:"runtimeSpec,omitempty"`
+			RuntimeSpec       spec.Spec                  `json:"runtimeSpec,omitempty"`
+			NetworkInterfaces []sandbox.NetworkInterface `json:"networkInterfaces,omitempty"`
+			DNSWarnings       []string                   `json:"dnsWarnings,omitempty"`
 		}{
 			c.Spec(),
+			networkInterfaces,
+			dnsWarnings,
 		}
 	} else {
 		info = struct {
-			Image       string    `json:"image"`
-			Pid         int       `json:"pid"`
-			RuntimeSpec spec.Spec `json:"runtimeSpec,omitempty"`
+			Image             string                     `json:"image"`
+			Pid               int                        `json:"pid"`
+			RuntimeSpec       spec.Spec                  `json:"runtimeSpec,omitempty"`
+			NetworkInterfaces []sandbox.NetworkInterface `json:"networkInterfaces,omitempty"`
+			DNSWarnings       []string                   `json:"dnsWarnings,omitempty"`
 		}{
 			c.Image(),
 			c.State().Pid,
 			c.Spec(),
+			networkInterfaces,
+			dnsWarnings,
 		}
 	}
 	bytes, err := json.Marshal(info)
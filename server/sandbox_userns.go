@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/containers/storage/pkg/idtools"
+	"github.com/cri-o/cri-o/internal/config/userns"
+	"github.com/cri-o/cri-o/internal/log"
+	libconfig "github.com/cri-o/cri-o/pkg/config"
+	"github.com/cri-o/cri-o/server/metrics"
+)
+
+// newUsernsAllocator creates the allocator for the user namespace ID ranges of
+// pods. It returns nil if neither allocation ranges nor an allocation user are
+// configured.
+func newUsernsAllocator(config *libconfig.Config) (*userns.Allocator, error) {
+	var uids, gids []idtools.IDMap
+	switch {
+	case len(config.UsernsAllocationRanges) > 0:
+		ranges, err := userns.ParseRanges(config.UsernsAllocationRanges)
+		if err != nil {
+			return nil, err
+		}
+		uids, gids = ranges, ranges
+	case config.UsernsAllocationUser != "":
+		var err error
+		uids, gids, err = userns.SubordinateRanges(config.UsernsAllocationUser)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, nil
+	}
+	return userns.New(config.UsernsAllocationFile, uids, gids)
+}
+
+// allocateSandboxUserns allocates the user namespace ID range of a sandbox.
+func (s *Server) allocateSandboxUserns(id string) (*userns.Allocation, error) {
+	if s.usernsAllocator == nil {
+		return nil, fmt.Errorf("no ID mappings provided and user namespace ID range allocation is not configured")
+	}
+	allocation, err := s.usernsAllocator.Allocate(id)
+	if err != nil {
+		return nil, fmt.Errorf("allocate user namespace ID range: %w", err)
+	}
+	s.updateUsernsMetrics()
+	return allocation, nil
+}
+
+// releaseSandboxUserns releases the user namespace ID range of a sandbox, if
+// it has one.
+func (s *Server) releaseSandboxUserns(ctx context.Context, id string) {
+	if s.usernsAllocator == nil {
+		return
+	}
+	if err := s.usernsAllocator.Release(id); err != nil {
+		log.Warnf(ctx, "Unable to release user namespace ID range of sandbox %s: %v", id, err)
+	}
+	s.updateUsernsMetrics()
+}
+
+// pruneSandboxUserns releases the user namespace ID ranges of sandboxes, which
+// do not exist anymore.
+func (s *Server) pruneSandboxUserns(ctx context.Context) {
+	if s.usernsAllocator == nil {
+		return
+	}
+	if err := s.usernsAllocator.Prune(func(id string) bool {
+		return s.getSandbox(ctx, id) != nil
+	}); err != nil {
+		log.Warnf(ctx, "Unable to release user namespace ID ranges of removed sandboxes: %v", err)
+	}
+	s.updateUsernsMetrics()
+}
+
+func (s *Server) updateUsernsMetrics() {
+	allocated := s.usernsAllocator.Allocated()
+	metrics.Instance().MetricUsernsRangesSet("allocated", allocated)
+	metrics.Instance().MetricUsernsRangesSet("available", s.usernsAllocator.Total()-allocated)
+}
@@ -0,0 +1,22 @@
+package server
+
+import (
+	"context"
+
+	"github.com/cri-o/cri-o/internal/log"
+)
+
+// maxSeccompArtifactSize is the maximum size of seccomp profiles pulled from
+// OCI artifacts.
+const maxSeccompArtifactSize = 1 << 20
+
+// pullSeccompArtifact pulls the seccomp profile contained in the OCI artifact
+// ref by using the image service and the signature policy of the server.
+func (s *Server) pullSeccompArtifact(ctx context.Context, ref string) ([]byte, error) {
+	log.Debugf(ctx, "Pulling seccomp profile artifact %s", ref)
+	artifact, err := s.StorageImageServer().PullArtifact(ctx, s.config.SystemContext, ref, maxSeccompArtifactSize)
+	if err != nil {
+		return nil, err
+	}
+	return artifact.Data, nil
+}
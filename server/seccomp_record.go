@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cri-o/cri-o/internal/config/seccomp"
+	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/internal/oci"
+	json "github.com/json-iterator/go"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	kubeletTypes "k8s.io/kubernetes/pkg/kubelet/types"
+)
+
+// writeRecordedSeccompProfile writes the seccomp profile recorded for an
+// exited container to the seccomp record directory.
+func (s *Server) writeRecordedSeccompProfile(ctx context.Context, c *oci.Container) {
+	result, ok := s.seccompNotifiers.Load(c.ID())
+	if !ok {
+		return
+	}
+	notifier, ok := result.(*seccomp.Notifier)
+	if !ok || !notifier.Record() {
+		return
+	}
+
+	profile := notifier.RecordedProfile()
+	if profile == nil {
+		log.Warnf(ctx, "No syscalls recorded for container %s, not writing a seccomp profile", c.ID())
+		return
+	}
+	path := filepath.Join(s.config.SeccompRecordDir, recordedSeccompProfileName(c.Labels(), c.ID()))
+	if err := writeSeccompProfile(path, profile); err != nil {
+		log.Errorf(ctx, "Unable to write recorded seccomp profile of container %s: %v", c.ID(), err)
+		return
+	}
+	log.Infof(ctx, "Wrote recorded seccomp profile of container %s to %s", c.ID(), path)
+}
+
+// recordedSeccompProfileName returns the file name of a recorded seccomp
+// profile, which is NAMESPACE_POD_CONTAINER.json for Kubernetes containers.
+func recordedSeccompProfileName(labels map[string]string, id string) string {
+	parts := []string{
+		labels[kubeletTypes.KubernetesPodNamespaceLabel],
+		labels[kubeletTypes.KubernetesPodNameLabel],
+		labels[kubeletTypes.KubernetesContainerNameLabel],
+	}
+	for _, part := range parts {
+		if part == "" || strings.ContainsRune(part, filepath.Separator) {
+			return id + ".json"
+		}
+	}
+	return strings.Join(parts, "_") + ".json"
+}
+
+func writeSeccompProfile(path string, profile *specs.LinuxSeccomp) error {
+	content, err := json.MarshalIndent(profile, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal profile: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, append(content, '\n'), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
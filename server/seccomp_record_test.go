@@ -0,0 +1,43 @@
+package server
+
+import (
+	"testing"
+
+	kubeletTypes "k8s.io/kubernetes/pkg/kubelet/types"
+)
+
+func TestRecordedSeccompProfileName(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		labels map[string]string
+		want   string
+	}{
+		{
+			name: "kubernetes container",
+			labels: map[string]string{
+				kubeletTypes.KubernetesPodNamespaceLabel:  "default",
+				kubeletTypes.KubernetesPodNameLabel:       "pod",
+				kubeletTypes.KubernetesContainerNameLabel: "ctr",
+			},
+			want: "default_pod_ctr.json",
+		},
+		{
+			name:   "missing labels",
+			labels: map[string]string{kubeletTypes.KubernetesPodNameLabel: "pod"},
+			want:   "id.json",
+		},
+		{
+			name: "path separator",
+			labels: map[string]string{
+				kubeletTypes.KubernetesPodNamespaceLabel:  "default",
+				kubeletTypes.KubernetesPodNameLabel:       "../pod",
+				kubeletTypes.KubernetesContainerNameLabel: "ctr",
+			},
+			want: "id.json",
+		},
+	} {
+		if got := recordedSeccompProfileName(tc.labels, "id"); got != tc.want {
+			t.Errorf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
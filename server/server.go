@@ -25,7 +25,9 @@ import (
 	imageTypes "github.com/containers/image/v5/types"
 	"github.com/containers/storage/pkg/idtools"
 	storageTypes "github.com/containers/storage/types"
+	"github.com/cri-o/cri-o/internal/audit"
 	"github.com/cri-o/cri-o/internal/config/seccomp"
+	"github.com/cri-o/cri-o/internal/config/userns"
 	"github.com/cri-o/cri-o/internal/hostport"
 	"github.com/cri-o/cri-o/internal/lib"
 	"github.com/cri-o/cri-o/internal/lib/sandbox"
@@ -78,6 +80,14 @@ type Server struct {
 
 	minimumMappableUID, minimumMappableGID int64
 
+	// usernsAllocator allocates the user namespace ID ranges of pods
+	// without ID mappings.
+	usernsAllocator *userns.Allocator
+
+	// auditLogger records privileged container operations, it is nil if the
+	// audit log is disabled.
+	auditLogger *audit.Logger
+
 	// pullOperationsInProgress is used to avoid pulling the same image in parallel. Goroutines
 	// will block on the pullResult.
 	pullOperationsInProgress map[pullArguments]*pullOperation
@@ -89,6 +99,17 @@ type Server struct {
 	seccompNotifierChan chan seccomp.Notification
 	seccompNotifiers    sync.Map
 
+	// hostportReconcileLock is write locked by the hostport reconciliation
+	// and read locked while adding or removing the hostports of a sandbox.
+	hostportReconcileLock  sync.RWMutex
+	hostportReconciliation hostportReconciliation
+	// hostportReservations is the index of the host ports reserved by the
+	// sandboxes.
+	hostportReservations *hostport.Reservations
+
+	// events are the recorded events of the inspect API.
+	events eventRecorder
+
 	// NRI runtime interface
 	nri *nriAPI
 }
@@ -296,12 +317,32 @@ func (s *Server) restore(ctx context.Context) []string {
 
 	// Restore sandbox IPs
 	for _, sb := range s.ListSandboxes() {
-		ips, err := s.getSandboxIPs(ctx, sb)
+		networkResults, err := s.getSandboxNetworkResults(ctx, sb)
 		if err != nil {
 			log.Warnf(ctx, "Could not restore sandbox IP for %v: %v", sb.ID(), err)
 			continue
 		}
-		sb.AddIPs(ips)
+		if len(networkResults) == 0 {
+			continue
+		}
+		if sb.NetworkResults() == nil {
+			// cache the results of the CNI plugin for the next restart
+			sb.SetNetworkResults(networkResults)
+			if err := sb.SaveNetworkResults(ctx); err != nil {
+				log.Warnf(ctx, "Could not cache network results for %v: %v", sb.ID(), err)
+			}
+		}
+		sb.AddIPs(sb.NetworkInterfaces()[0].IPs)
+	}
+
+	// Restore the host port reservations
+	for _, sb := range s.ListSandboxes() {
+		if sb.HostNetwork() || sb.NetworkStopped() {
+			continue
+		}
+		if err := s.hostportReservations.Reserve(sb.ID(), sb.Name(), sb.PortMappings()); err != nil {
+			log.Warnf(ctx, "Could not restore host port reservations for %v: %v", sb.ID(), err)
+		}
 	}
 
 	// Return a slice of images to remove, if internal_wipe is set.
@@ -316,7 +357,11 @@ func (s *Server) restore(ctx context.Context) []string {
 // Shutdown attempts to shut down the server's storage cleanly
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.config.CNIManagerShutdown()
+	s.config.NamespaceManager().StopPool()
 	s.resourceStore.Close()
+	if err := s.auditLogger.Close(); err != nil {
+		log.Warnf(ctx, "Unable to close audit log: %v", err)
+	}
 
 	if err := s.ContainerServer.Shutdown(); err != nil {
 		return err
@@ -428,13 +473,29 @@ func New(
 		}
 	}
 
-	hostportManager := hostport.NewMetaHostportManager()
+	var hostportManager hostport.HostPortManager
+	switch config.HostPortBackend {
+	case libconfig.HostPortBackendNFTables:
+		hostportManager = hostport.NewNftablesMetaHostportManager()
+	default:
+		hostportManager = hostport.NewMetaHostportManager()
+	}
 
 	idMappings, err := getIDMappings(config)
 	if err != nil {
 		return nil, err
 	}
 
+	usernsAllocator, err := newUsernsAllocator(config)
+	if err != nil {
+		return nil, fmt.Errorf("create user namespace ID range allocator: %w", err)
+	}
+
+	auditLogger, err := newAuditLogger(config)
+	if err != nil {
+		return nil, fmt.Errorf("create audit log: %w", err)
+	}
+
 	if os.Getenv(rootlessEnvName) == "" {
 		// Not running as rootless, reset XDG_RUNTIME_DIR and DBUS_SESSION_BUS_ADDRESS
 		os.Unsetenv("XDG_RUNTIME_DIR")
@@ -444,11 +505,14 @@ func New(
 	s := &Server{
 		ContainerServer:          containerServer,
 		hostportManager:          hostportManager,
+		hostportReservations:     hostport.NewReservations(),
 		config:                   *config,
 		monitorsChan:             make(chan struct{}),
 		defaultIDMappings:        idMappings,
 		minimumMappableUID:       config.MinimumMappableUID,
 		minimumMappableGID:       config.MinimumMappableGID,
+		usernsAllocator:          usernsAllocator,
+		auditLogger:              auditLogger,
 		pullOperationsInProgress: make(map[pullArguments]*pullOperation),
 		resourceStore:            resourcestore.New(),
 	}
@@ -456,6 +520,7 @@ func New(
 		// creating a container events channel only if the evented pleg is enabled
 		s.ContainerEventsChan = make(chan types.ContainerEventResponse, 1000)
 	}
+	s.config.CNIManagerSetStatusHandler(s.handleCNIStatus)
 	if err := configureMaxThreads(); err != nil {
 		return nil, err
 	}
@@ -472,7 +537,10 @@ func New(
 	}
 
 	deletedImages := s.restore(ctx)
+	s.pruneSandboxUserns(ctx)
+	s.pruneSandboxMCSLevels(ctx)
 	s.wipeIfAppropriate(ctx, deletedImages)
+	s.startHostportReconciliation(ctx)
 
 	var bindAddressStr string
 	bindAddress := net.ParseIP(config.StreamAddress)
@@ -543,10 +611,17 @@ func New(
 		logrus.Debug("Metrics are disabled")
 	}
 
+	// Allow seccomp profiles to reference OCI artifacts
+	s.config.SetSeccompArtifactPuller(s.pullSeccompArtifact)
+
 	if err := s.startSeccompNotifierWatcher(ctx); err != nil {
 		return nil, fmt.Errorf("start seccomp notifier watcher: %w", err)
 	}
 
+	if err := s.config.NamespaceManager().StartPool(s.config.NamespacePoolSize); err != nil {
+		return nil, fmt.Errorf("start namespace pool: %w", err)
+	}
+
 	// Set up our NRI adaptation.
 	api, err := nriIf.New(s.Config().NRI)
 	if err != nil {
@@ -786,8 +861,9 @@ func (s *Server) startSeccompNotifierWatcher(ctx context.Context) error {
 			ctx := msg.Ctx()
 			id := msg.ContainerID()
 			syscall := msg.Syscall()
+			count := msg.Count()
 
-			log.Infof(ctx, "Got seccomp notifier message for container ID: %s (syscall = %s)", id, syscall)
+			log.Infof(ctx, "Got seccomp notifier message for container ID: %s (syscall = %s, count = %d)", id, syscall, count)
 
 			result, ok := s.seccompNotifiers.Load(id)
 			if !ok {
@@ -799,9 +875,13 @@ func (s *Server) startSeccompNotifierWatcher(ctx context.Context) error {
 				log.Errorf(ctx, "Notifier is not a seccomp notifier type")
 				continue
 			}
-			notifier.AddSyscall(syscall)
+			notifier.AddSyscalls(syscall, count)
 
 			ctr := s.ContainerServer.GetContainer(ctx, id)
+			if ctr == nil {
+				log.Warnf(ctx, "Unable to find container %s for seccomp notifier message", id)
+				continue
+			}
 			usedSyscalls := notifier.UsedSyscalls()
 
 			if notifier.StopContainers() {
@@ -820,7 +900,12 @@ func (s *Server) startSeccompNotifierWatcher(ctx context.Context) error {
 				})
 			}
 
-			metrics.Instance().MetricContainersSeccompNotifierCountTotalInc(ctr.Name(), syscall)
+			if notifier.Audit() {
+				s.recordContainerWarning(ctx, ctr, eventReasonSeccompSyscallAudited,
+					fmt.Sprintf("Container %s used the syscall %s %d time(s), which is blocked by its seccomp profile", ctr.Name(), syscall, count))
+			}
+
+			metrics.Instance().MetricContainersSeccompNotifierCountTotalAdd(float64(count), ctr.Name(), syscall)
 		}
 	}()
 
@@ -895,6 +980,7 @@ func (s *Server) handleExit(ctx context.Context, event fsnotify.Event) {
 	}
 
 	if nriCtr != nil {
+		s.writeRecordedSeccompProfile(ctx, nriCtr)
 		if err := s.nri.stopContainer(ctx, nil, nriCtr); err != nil {
 			log.Warnf(ctx, "NRI stop container request of %s failed: %v", nriCtr.ID(), err)
 		}
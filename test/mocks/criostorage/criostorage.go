@@ -97,6 +97,21 @@ func (mr *MockImageServerMockRecorder) PrepareImage(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrepareImage", reflect.TypeOf((*MockImageServer)(nil).PrepareImage), arg0, arg1)
 }
 
+// PullArtifact mocks base method.
+func (m *MockImageServer) PullArtifact(arg0 context.Context, arg1 *types.SystemContext, arg2 string, arg3 int64) (*storage0.Artifact, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PullArtifact", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*storage0.Artifact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PullArtifact indicates an expected call of PullArtifact.
+func (mr *MockImageServerMockRecorder) PullArtifact(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PullArtifact", reflect.TypeOf((*MockImageServer)(nil).PullArtifact), arg0, arg1, arg2, arg3)
+}
+
 // PullImage mocks base method.
 func (m *MockImageServer) PullImage(arg0 *types.SystemContext, arg1 string, arg2 *storage0.ImageCopyOptions) (types.ImageReference, error) {
 	m.ctrl.T.Helper()